@@ -2,16 +2,20 @@ package app
 
 import (
 	"context"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"os"
+	"time"
 	"weplant-backend/helper"
+	"weplant-backend/pkg"
+	"weplant-backend/repository"
 )
 
 func GetConnection() *mongo.Client {
 	mongoUri := os.Getenv("MONGO_URI")
 
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoUri))
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoUri).SetMonitor(RepositoryMonitor()))
 	helper.PanicIfError(err)
 	return client
 }
@@ -20,3 +24,23 @@ func CloseConnection(client *mongo.Client) {
 	err := client.Disconnect(context.TODO())
 	helper.PanicIfError(err)
 }
+
+// RepositoryMonitor times every command by the repository method sending it, commands sent from outside the
+// repositories are not timed.
+func RepositoryMonitor() *event.CommandMonitor {
+	observe := func(ctx context.Context, command string, durationNanos int64, result string) {
+		operation, ok := repository.OperationFromContext(ctx)
+		if !ok {
+			return
+		}
+		pkg.MongoOperationDuration.WithLabelValues(operation.Repository, operation.Method, command, result).Observe(time.Duration(durationNanos).Seconds())
+	}
+	return &event.CommandMonitor{
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			observe(ctx, e.CommandName, e.DurationNanos, "success")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			observe(ctx, e.CommandName, e.DurationNanos, "error")
+		},
+	}
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
+	"weplant-backend/model/schema"
+)
+
+// embeddedOrder is how customer orders and merchant manage orders used to be stored inside their documents
+type embeddedOrder struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt int                `bson:"created_at,omitempty"`
+	UpdatedAt int                `bson:"updated_at,omitempty"`
+	ProductId string             `bson:"product_id,omitempty"`
+	Price     int                `bson:"price,omitempty"`
+	Quantity  int                `bson:"quantity,omitempty"`
+	Address   *schema.Address    `bson:"address,omitempty"`
+}
+
+// legacyKey is the same for a customer order and the manage order written along with it, neither knows the id of the other
+func (order embeddedOrder) legacyKey() string {
+	return fmt.Sprintf("%s:%d:%d:%d", order.ProductId, order.CreatedAt, order.Price, order.Quantity)
+}
+
+// migratedOrder keeps the legacy key of an embedded order next to it, so the manage orders of a migration run again
+// are still matched with the customer orders moved before
+type migratedOrder struct {
+	schema.Order `bson:",inline"`
+	LegacyKey    string `bson:"legacy_key"`
+}
+
+func (order embeddedOrder) items() []schema.OrderItem {
+	return []schema.OrderItem{
+		{
+			ProductId: order.ProductId,
+			Price:     order.Price,
+			Quantity:  order.Quantity,
+		},
+	}
+}
+
+type embeddedCustomer struct {
+	Id           primitive.ObjectID   `bson:"_id"`
+	Transactions []schema.Transaction `bson:"transactions"`
+	Orders       []embeddedOrder      `bson:"orders"`
+}
+
+type embeddedMerchant struct {
+	Id     primitive.ObjectID `bson:"_id"`
+	Orders []embeddedOrder    `bson:"orders"`
+}
+
+// MigrateEmbeddedOrders moves the transactions and orders embedded in customers and merchants into their own
+// collections. Documents keep their ids and manage orders are matched to the customer orders by a stored legacy
+// key, so running it again after a failure does not duplicate anything.
+func MigrateEmbeddedOrders(ctx context.Context, database *mongo.Database) error {
+	customerCollection := database.Collection("customer")
+	merchantCollection := database.Collection("merchant")
+	productCollection := database.Collection("product")
+	transactionCollection := database.Collection("transaction")
+	orderCollection := database.Collection("order")
+
+	upsert := options.Replace().SetUpsert(true)
+
+	_, err := orderCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"legacy_key", 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	cursor, err := customerCollection.Find(ctx, bson.D{
+		{"$or", bson.A{
+			bson.D{{"transactions", bson.D{{"$exists", true}}}},
+			bson.D{{"orders", bson.D{{"$exists", true}}}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	var customers []embeddedCustomer
+	err = cursor.All(ctx, &customers)
+	if err != nil {
+		return err
+	}
+
+	for _, customer := range customers {
+		for _, transaction := range customer.Transactions {
+			transaction.CustomerId = customer.Id.Hex()
+			_, err = transactionCollection.ReplaceOne(ctx, bson.D{{"_id", transaction.Id}}, transaction, upsert)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, v := range customer.Orders {
+			// the merchant was never stored on customer orders, it comes from the product when it still exists
+			var product schema.Product
+			productId, _ := primitive.ObjectIDFromHex(v.ProductId)
+			err = productCollection.FindOne(ctx, bson.D{{"_id", productId}}).Decode(&product)
+			if err != nil && err != mongo.ErrNoDocuments {
+				return err
+			}
+			_, err = orderCollection.ReplaceOne(ctx, bson.D{{"_id", v.Id}}, migratedOrder{
+				Order: schema.Order{
+					Id:         v.Id,
+					CreatedAt:  v.CreatedAt,
+					UpdatedAt:  v.UpdatedAt,
+					CustomerId: customer.Id.Hex(),
+					MerchantId: product.MerchantId,
+					Items:      v.items(),
+					Subtotal:   v.Price * v.Quantity,
+					Total:      v.Price * v.Quantity,
+					Status:     schema.OrderStatusPaid,
+					Address:    v.Address,
+					History: []schema.OrderHistory{
+						{
+							CreatedAt: v.CreatedAt,
+							Status:    schema.OrderStatusPaid,
+						},
+					},
+				},
+				LegacyKey: v.legacyKey(),
+			}, upsert)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = customerCollection.UpdateByID(ctx, customer.Id, bson.D{
+			{"$unset", bson.D{
+				{"transactions", ""},
+				{"orders", ""},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("migrated embedded transactions and orders of %d customers", len(customers))
+
+	cursor, err = merchantCollection.Find(ctx, bson.D{{"orders", bson.D{{"$exists", true}}}})
+	if err != nil {
+		return err
+	}
+	var merchants []embeddedMerchant
+	err = cursor.All(ctx, &merchants)
+	if err != nil {
+		return err
+	}
+
+	for _, merchant := range merchants {
+		for _, v := range merchant.Orders {
+			// every manage order was written together with a customer order, skip the ones already migrated from the
+			// customer side and fill in the merchant of those whose product was gone
+			count, err := orderCollection.CountDocuments(ctx, bson.D{
+				{"legacy_key", v.legacyKey()},
+				{"_id", bson.D{{"$ne", v.Id}}},
+			})
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				_, err = orderCollection.UpdateMany(ctx, bson.D{
+					{"legacy_key", v.legacyKey()},
+					{"merchant_id", bson.D{{"$in", bson.A{"", nil}}}},
+				}, bson.D{
+					{"$set", bson.D{{"merchant_id", merchant.Id.Hex()}}},
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			_, err = orderCollection.ReplaceOne(ctx, bson.D{{"_id", v.Id}}, migratedOrder{
+				Order: schema.Order{
+					Id:         v.Id,
+					CreatedAt:  v.CreatedAt,
+					UpdatedAt:  v.UpdatedAt,
+					MerchantId: merchant.Id.Hex(),
+					Items:      v.items(),
+					Subtotal:   v.Price * v.Quantity,
+					Total:      v.Price * v.Quantity,
+					Status:     schema.OrderStatusPaid,
+					Address:    v.Address,
+					History: []schema.OrderHistory{
+						{
+							CreatedAt: v.CreatedAt,
+							Status:    schema.OrderStatusPaid,
+						},
+					},
+				},
+				LegacyKey: v.legacyKey(),
+			}, upsert)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = merchantCollection.UpdateByID(ctx, merchant.Id, bson.D{
+			{"$unset", bson.D{
+				{"orders", ""},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("migrated embedded manage orders of %d merchants", len(merchants))
+
+	return nil
+}
+
+// MigrateOrderItems turns the orders stored with a single product into orders with items. Each of them stays its own
+// order, merging them per merchant would mix statuses the merchants already moved separately.
+func MigrateOrderItems(ctx context.Context, database *mongo.Database) error {
+	orderCollection := database.Collection("order")
+
+	cursor, err := orderCollection.Find(ctx, bson.D{{"product_id", bson.D{{"$exists", true}}}})
+	if err != nil {
+		return err
+	}
+	var orders []embeddedOrder
+	err = cursor.All(ctx, &orders)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range orders {
+		_, err = orderCollection.UpdateByID(ctx, v.Id, bson.D{
+			{"$set", bson.D{
+				{"items", v.items()},
+				{"subtotal", v.Price * v.Quantity},
+				{"total", v.Price * v.Quantity},
+			}},
+			{"$unset", bson.D{
+				{"product_id", ""},
+				{"price", ""},
+				{"quantity", ""},
+			}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("migrated %d single product orders into orders with items", len(orders))
+
+	return nil
+}
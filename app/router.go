@@ -7,55 +7,142 @@ import (
 	"weplant-backend/controller"
 	"weplant-backend/exception"
 	"weplant-backend/middleware"
+	"weplant-backend/pkg"
+	"weplant-backend/repository"
 )
 
-func NewRouter(swagger fs.FS, authController controller.AuthController, merchantController controller.MerchantController, productController controller.ProductController, categoryController controller.CategoryController, customerController controller.CustomerController, cartController controller.CartController, transactionController controller.TransactionController) *httprouter.Router {
+func NewRouter(swagger fs.FS, authController controller.AuthController, merchantController controller.MerchantController, productController controller.ProductController, categoryController controller.CategoryController, customerController controller.CustomerController, cartController controller.CartController, transactionController controller.TransactionController, ledgerController controller.LedgerController, payoutController controller.PayoutController, shippingController controller.ShippingController, reviewController controller.ReviewController, wishlistController controller.WishlistController, voucherController controller.VoucherController, productRepository repository.ProductRepository) *httprouter.Router {
 
-	router := httprouter.New()
+	router := patternRouter{httprouter.New()}
 
-	router.PanicHandler = exception.ErrorHandler
+	productOwner := middleware.NewProductOwnerMiddleware(productRepository)
+
+	router.PanicHandler = middleware.PanicHandler(exception.ErrorHandler)
 
 	router.ServeFiles("/docs/*filepath", http.FS(swagger))
+	router.Handler(http.MethodGet, "/metrics", middleware.MetricsTokenMiddleware(pkg.MetricsHandler()))
 
 	router.POST("/api/v1/auth/merchant", authController.LoginMerchant)
 	router.POST("/api/v1/auth/customer", authController.LoginCustomer)
+	router.POST("/api/v1/auth/refresh", authController.Refresh)
+	router.POST("/api/v1/auth/logout", authController.Logout)
 
 	router.POST("/api/v1/merchants", merchantController.Create)
 	router.GET("/api/v1/merchants/:merchantId", merchantController.FindById)
-	router.GET("/api/v1/merchants/:merchantId/orders", middleware.AuthMiddleware(merchantController.FindManageOrderById, "merchant"))
-	router.PUT("/api/v1/merchants/:merchantId", middleware.AuthMiddleware(merchantController.Update, "merchant"))
-	router.PATCH("/api/v1/merchants/:merchantId/image", middleware.AuthMiddleware(merchantController.UpdateMainImage, "merchant"))
-	router.DELETE("/api/v1/merchants/:merchantId", middleware.AuthMiddleware(merchantController.Delete, "merchant"))
+	router.GET("/api/v1/merchants/:merchantId/orders", middleware.AuthMiddleware(middleware.OwnerMiddleware(merchantController.FindManageOrderById, "merchantId"), "merchant"))
+	router.PATCH("/api/v1/merchants/:merchantId/orders/:orderId/status", middleware.AuthMiddleware(middleware.OwnerMiddleware(merchantController.UpdateOrderStatus, "merchantId"), "merchant"))
+	router.PATCH("/api/v1/merchants/:merchantId/orders/:orderId/shipping", middleware.AuthMiddleware(middleware.OwnerMiddleware(merchantController.UpdateOrderShipping, "merchantId"), "merchant"))
+	router.GET("/api/v1/merchants/:merchantId/ledger", middleware.AuthMiddleware(middleware.OwnerMiddleware(ledgerController.FindByMerchantId, "merchantId"), "merchant"))
+	router.GET("/api/v1/merchants/:merchantId/payouts", middleware.AuthMiddleware(middleware.OwnerMiddleware(payoutController.FindByMerchantId, "merchantId"), "merchant"))
+	router.POST("/api/v1/merchants/:merchantId/payouts", middleware.AuthMiddleware(middleware.OwnerMiddleware(payoutController.Create, "merchantId"), "merchant"))
+	router.GET("/api/v1/merchants/:merchantId/vouchers", middleware.AuthMiddleware(middleware.OwnerMiddleware(voucherController.FindByMerchantId, "merchantId"), "merchant"))
+	router.POST("/api/v1/merchants/:merchantId/vouchers", middleware.AuthMiddleware(middleware.OwnerMiddleware(voucherController.Create, "merchantId"), "merchant"))
+	router.PUT("/api/v1/merchants/:merchantId/vouchers/:voucherId", middleware.AuthMiddleware(middleware.OwnerMiddleware(voucherController.Update, "merchantId"), "merchant"))
+	router.DELETE("/api/v1/merchants/:merchantId/vouchers/:voucherId", middleware.AuthMiddleware(middleware.OwnerMiddleware(voucherController.Delete, "merchantId"), "merchant"))
+	router.PUT("/api/v1/merchants/:merchantId/reviews/:reviewId/reply", middleware.AuthMiddleware(middleware.OwnerMiddleware(reviewController.Reply, "merchantId"), "merchant"))
+	router.PUT("/api/v1/merchants/:merchantId", middleware.AuthMiddleware(middleware.OwnerMiddleware(merchantController.Update, "merchantId"), "merchant"))
+	router.PATCH("/api/v1/merchants/:merchantId/image", middleware.AuthMiddleware(middleware.OwnerMiddleware(merchantController.UpdateMainImage, "merchantId"), "merchant"))
+	router.DELETE("/api/v1/merchants/:merchantId", middleware.AuthMiddleware(middleware.OwnerMiddleware(merchantController.Delete, "merchantId"), "merchant"))
 
 	router.GET("/api/v1/products/:productId", productController.FindById)
 	router.GET("/api/v1/products", productController.FindAll)
-	router.POST("/api/v1/products", middleware.AuthMiddleware(productController.Create, "merchant"))
-	router.PUT("/api/v1/products/:productId", middleware.AuthMiddleware(productController.Update, "merchant"))
-	router.PATCH("/api/v1/products/:productId/image", middleware.AuthMiddleware(productController.UpdateMainImage, "merchant"))
-	router.POST("/api/v1/products/:productId/images", middleware.AuthMiddleware(productController.PushImageIntoImages, "merchant"))
-	router.DELETE("/api/v1/products/:productId/images/:imageId", middleware.AuthMiddleware(productController.PullImageFromImages, "merchant"))
-	router.DELETE("/api/v1/products/:productId", middleware.AuthMiddleware(productController.Delete, "merchant"))
+	router.GET("/api/v1/products/:productId/reviews", reviewController.FindByProductId)
+	router.POST("/api/v1/products", middleware.AuthMiddleware(middleware.FormOwnerMiddleware(productController.Create, "merchant_id"), "merchant"))
+	router.PUT("/api/v1/products/:productId", middleware.AuthMiddleware(productOwner.Handle(productController.Update), "merchant"))
+	router.PATCH("/api/v1/products/:productId/image", middleware.AuthMiddleware(productOwner.Handle(productController.UpdateMainImage), "merchant"))
+	router.POST("/api/v1/products/:productId/images", middleware.AuthMiddleware(productOwner.Handle(productController.PushImageIntoImages), "merchant"))
+	router.DELETE("/api/v1/products/:productId/images/:imageId", middleware.AuthMiddleware(productOwner.Handle(productController.PullImageFromImages), "merchant"))
+	router.DELETE("/api/v1/products/:productId", middleware.AuthMiddleware(productOwner.Handle(productController.Delete), "merchant"))
+	router.POST("/api/v1/products/:productId/variants", middleware.AuthMiddleware(productOwner.Handle(productController.CreateVariant), "merchant"))
+	router.PUT("/api/v1/products/:productId/variants/:variantId", middleware.AuthMiddleware(productOwner.Handle(productController.UpdateVariant), "merchant"))
+	router.DELETE("/api/v1/products/:productId/variants/:variantId", middleware.AuthMiddleware(productOwner.Handle(productController.DeleteVariant), "merchant"))
 
 	router.GET("/api/v1/categories/:categoryId", categoryController.FindById)
 	router.GET("/api/v1/categories", categoryController.FindAll)
+	router.GET("/api/v1/category-tree", categoryController.FindTree)
 	router.POST("/api/v1/categories", middleware.AuthMiddleware(categoryController.Create, "merchant"))
+	router.PUT("/api/v1/categories/:categoryId", middleware.AdminMiddleware(categoryController.Update))
+	router.PATCH("/api/v1/categories/:categoryId/image", middleware.AdminMiddleware(categoryController.UpdateMainImage))
+	router.DELETE("/api/v1/categories/:categoryId", middleware.AdminMiddleware(categoryController.Delete))
 
 	router.POST("/api/v1/customers", customerController.Create)
 	router.GET("/api/v1/customers/:customerId", customerController.FindById)
-	router.GET("/api/v1/customers/:customerId/carts", middleware.AuthMiddleware(customerController.FindCartById, "customer"))
-	router.GET("/api/v1/customers/:customerId/transactions", middleware.AuthMiddleware(customerController.FindTransactionById, "customer"))
-	router.GET("/api/v1/customers/:customerId/orders", middleware.AuthMiddleware(customerController.FindOrderById, "customer"))
-	router.PUT("/api/v1/customers/:customerId", middleware.AuthMiddleware(customerController.Update, "customer"))
-	router.PATCH("/api/v1/customers/:customerId/image", middleware.AuthMiddleware(customerController.UpdateMainImage, "customer"))
-	router.DELETE("/api/v1/customers/:customerId", middleware.AuthMiddleware(customerController.Delete, "customer"))
+	router.GET("/api/v1/customers/:customerId/carts", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.FindCartById, "customerId"), "customer"))
+	router.GET("/api/v1/customers/:customerId/transactions", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.FindTransactionById, "customerId"), "customer"))
+	router.GET("/api/v1/customers/:customerId/wishlist", middleware.AuthMiddleware(middleware.OwnerMiddleware(wishlistController.FindByCustomerId, "customerId"), "customer"))
+	router.POST("/api/v1/customers/:customerId/wishlist", middleware.AuthMiddleware(middleware.OwnerMiddleware(wishlistController.PushProductToWishlist, "customerId"), "customer"))
+	router.DELETE("/api/v1/customers/:customerId/wishlist/:productId", middleware.AuthMiddleware(middleware.OwnerMiddleware(wishlistController.PullProductFromWishlist, "customerId"), "customer"))
+	router.POST("/api/v1/customers/:customerId/wishlist/:productId/cart", middleware.AuthMiddleware(middleware.OwnerMiddleware(wishlistController.MoveToCart, "customerId"), "customer"))
+	router.POST("/api/v1/customers/:customerId/vouchers/validate", middleware.AuthMiddleware(middleware.OwnerMiddleware(voucherController.ValidateCode, "customerId"), "customer"))
+	router.GET("/api/v1/customers/:customerId/shipping-rates", middleware.AuthMiddleware(middleware.OwnerMiddleware(shippingController.Quote, "customerId"), "customer"))
+	router.GET("/api/v1/customers/:customerId/orders", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.FindOrderById, "customerId"), "customer"))
+	router.PATCH("/api/v1/customers/:customerId/orders/:orderId/confirm", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.ConfirmOrder, "customerId"), "customer"))
+	router.PATCH("/api/v1/customers/:customerId/orders/:orderId/refund", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.RequestRefund, "customerId"), "customer"))
+	router.POST("/api/v1/customers/:customerId/reviews", middleware.AuthMiddleware(middleware.OwnerMiddleware(reviewController.Create, "customerId"), "customer"))
+	router.PUT("/api/v1/customers/:customerId/reviews/:reviewId", middleware.AuthMiddleware(middleware.OwnerMiddleware(reviewController.Update, "customerId"), "customer"))
+	router.DELETE("/api/v1/customers/:customerId/reviews/:reviewId", middleware.AuthMiddleware(middleware.OwnerMiddleware(reviewController.Delete, "customerId"), "customer"))
+	router.PUT("/api/v1/customers/:customerId", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.Update, "customerId"), "customer"))
+	router.PATCH("/api/v1/customers/:customerId/image", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.UpdateMainImage, "customerId"), "customer"))
+	router.DELETE("/api/v1/customers/:customerId", middleware.AuthMiddleware(middleware.OwnerMiddleware(customerController.Delete, "customerId"), "customer"))
 
-	router.POST("/api/v1/carts/:customerId", middleware.AuthMiddleware(cartController.PushProductToCart, "customer"))
-	router.PATCH("/api/v1/carts/:customerId/products/:productId", middleware.AuthMiddleware(cartController.UpdateProductQuantity, "customer"))
-	router.DELETE("/api/v1/carts/:customerId/products/:productId", middleware.AuthMiddleware(cartController.PullProductFromCart, "customer"))
+	router.POST("/api/v1/carts/:customerId", middleware.AuthMiddleware(middleware.OwnerMiddleware(cartController.PushProductToCart, "customerId"), "customer"))
+	router.PATCH("/api/v1/carts/:customerId/products/:productId", middleware.AuthMiddleware(middleware.OwnerMiddleware(cartController.UpdateProductQuantity, "customerId"), "customer"))
+	router.DELETE("/api/v1/carts/:customerId/products/:productId", middleware.AuthMiddleware(middleware.OwnerMiddleware(cartController.PullProductFromCart, "customerId"), "customer"))
 
 	router.POST("/api/v1/callback", transactionController.Callback)
-	router.POST("/api/v1/transactions/:customerId", middleware.AuthMiddleware(transactionController.Create, "customer"))
-	router.DELETE("/api/v1/transactions/:customerId/transactions/:transactionId", middleware.AuthMiddleware(transactionController.Cancel, "customer"))
+	router.POST("/api/v1/transactions/:customerId", middleware.AuthMiddleware(middleware.OwnerMiddleware(transactionController.Create, "customerId"), "customer"))
+	router.DELETE("/api/v1/transactions/:customerId/transactions/:transactionId", middleware.AuthMiddleware(middleware.OwnerMiddleware(transactionController.Cancel, "customerId"), "customer"))
+
+	router.PATCH("/api/v1/payouts/:payoutId", middleware.AdminMiddleware(payoutController.UpdateStatus))
+
+	router.GET("/api/v1/vouchers", middleware.AdminMiddleware(voucherController.FindByMerchantId))
+	router.POST("/api/v1/vouchers", middleware.AdminMiddleware(voucherController.Create))
+	router.PUT("/api/v1/vouchers/:voucherId", middleware.AdminMiddleware(voucherController.Update))
+	router.DELETE("/api/v1/vouchers/:voucherId", middleware.AdminMiddleware(voucherController.Delete))
+
+	return router.Router
+}
+
+// patternRouter registers every route with the pattern it matches, the metrics are labelled by it
+type patternRouter struct {
+	*httprouter.Router
+}
+
+func (router patternRouter) Handle(method string, path string, handle httprouter.Handle) {
+	router.Router.Handle(method, path, middleware.RouteMiddleware(handle, path))
+}
+
+func (router patternRouter) Handler(method string, path string, handler http.Handler) {
+	router.Handle(method, path, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+func (router patternRouter) GET(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodGet, path, handle)
+}
+
+func (router patternRouter) POST(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPost, path, handle)
+}
+
+func (router patternRouter) PUT(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPut, path, handle)
+}
+
+func (router patternRouter) PATCH(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPatch, path, handle)
+}
+
+func (router patternRouter) DELETE(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodDelete, path, handle)
+}
 
-	return router
+// ServeFiles serves root under path like httprouter does, path ends with /*filepath
+func (router patternRouter) ServeFiles(path string, root http.FileSystem) {
+	fileServer := http.FileServer(root)
+	router.GET(path, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		request.URL.Path = params.ByName("filepath")
+		fileServer.ServeHTTP(writer, request)
+	})
 }
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// Schedule runs job every interval in the background until ctx is done
+func Schedule(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(ctx)
+			}
+		}
+	}()
+}
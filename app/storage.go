@@ -0,0 +1,66 @@
+package app
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"os"
+	"weplant-backend/repository"
+)
+
+// GetImageRepository picks the image storage named by IMAGE_STORAGE: cloudinary (the default), local or s3
+func GetImageRepository() repository.ImageRepository {
+	switch os.Getenv("IMAGE_STORAGE") {
+	case "", "cloudinary":
+		return repository.NewCloudinaryRepository(GetCloud())
+	case "local":
+		return repository.NewLocalImageRepository(GetLocalImageDir(), os.Getenv("LOCAL_IMAGE_URL"))
+	case "s3":
+		return repository.NewS3ImageRepository(repository.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+	}
+	panic("unknown IMAGE_STORAGE " + os.Getenv("IMAGE_STORAGE"))
+}
+
+func GetLocalImageDir() string {
+	dir := os.Getenv("LOCAL_IMAGE_DIR")
+	if dir == "" {
+		return "uploads"
+	}
+	return dir
+}
+
+// ServeImages serves the images from the local storage directory when it is the storage in use
+func ServeImages(router *httprouter.Router) {
+	if os.Getenv("IMAGE_STORAGE") != "local" {
+		return
+	}
+	ServeLocalImages(router, GetLocalImageDir())
+}
+
+func ServeLocalImages(router *httprouter.Router, dir string) {
+	patternRouter{router}.ServeFiles(repository.LocalImagePath+"*filepath", imageFileSystem{http.Dir(dir)})
+}
+
+// imageFileSystem only opens files so the directory is not listed
+type imageFileSystem struct {
+	http.FileSystem
+}
+
+func (fileSystem imageFileSystem) Open(name string) (http.File, error) {
+	file, err := fileSystem.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil || stat.IsDir() {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+	return file, nil
+}
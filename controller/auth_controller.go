@@ -8,4 +8,6 @@ import (
 type AuthController interface {
 	LoginCustomer(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	LoginMerchant(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Refresh(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Logout(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
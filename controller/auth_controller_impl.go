@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"github.com/go-playground/validator/v10"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"weplant-backend/helper"
@@ -10,11 +11,13 @@ import (
 
 type AuthControllerImpl struct {
 	AuthService service.AuthService
+	Validate    *validator.Validate
 }
 
-func NewAuthController(authService service.AuthService) AuthController {
+func NewAuthController(authService service.AuthService, validate *validator.Validate) AuthController {
 	return &AuthControllerImpl{
 		AuthService: authService,
+		Validate:    validate,
 	}
 }
 
@@ -24,7 +27,11 @@ func (controller *AuthControllerImpl) LoginCustomer(writer http.ResponseWriter,
 	var loginRequest web.LoginRequest
 	helper.ReadFromRequestBody(request, &loginRequest)
 
-	customer := controller.AuthService.LoginCustomer(ctx, loginRequest)
+	err := controller.Validate.Struct(loginRequest)
+	helper.PanicIfValidationError(err)
+
+	customer, err := controller.AuthService.LoginCustomer(ctx, loginRequest)
+	helper.PanicIfError(err)
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
@@ -40,7 +47,11 @@ func (controller *AuthControllerImpl) LoginMerchant(writer http.ResponseWriter,
 	var loginRequest web.LoginRequest
 	helper.ReadFromRequestBody(request, &loginRequest)
 
-	merchant := controller.AuthService.LoginMerchant(ctx, loginRequest)
+	err := controller.Validate.Struct(loginRequest)
+	helper.PanicIfValidationError(err)
+
+	merchant, err := controller.AuthService.LoginMerchant(ctx, loginRequest)
+	helper.PanicIfError(err)
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
@@ -50,3 +61,42 @@ func (controller *AuthControllerImpl) LoginMerchant(writer http.ResponseWriter,
 	helper.WriteToResponseBody(writer, webResponse)
 }
 
+func (controller *AuthControllerImpl) Refresh(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var refreshTokenRequest web.RefreshTokenRequest
+	helper.ReadFromRequestBody(request, &refreshTokenRequest)
+
+	err := controller.Validate.Struct(refreshTokenRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.AuthService.Refresh(ctx, refreshTokenRequest)
+	helper.PanicIfError(err)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) Logout(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var refreshTokenRequest web.RefreshTokenRequest
+	helper.ReadFromRequestBody(request, &refreshTokenRequest)
+
+	err := controller.Validate.Struct(refreshTokenRequest)
+	helper.PanicIfValidationError(err)
+
+	err = controller.AuthService.Logout(ctx, refreshTokenRequest)
+	helper.PanicIfError(err)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nil,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"github.com/go-playground/validator/v10"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"weplant-backend/helper"
@@ -10,11 +11,13 @@ import (
 
 type CartControllerImpl struct {
 	CartService service.CartService
+	Validate    *validator.Validate
 }
 
-func NewCartController(cartService service.CartService) CartController {
+func NewCartController(cartService service.CartService, validate *validator.Validate) CartController {
 	return &CartControllerImpl{
 		CartService: cartService,
+		Validate:    validate,
 	}
 }
 
@@ -27,7 +30,11 @@ func (controller *CartControllerImpl) PushProductToCart(writer http.ResponseWrit
 	cartRequest.CustomerId = customerId
 	cartRequest.Quantity = 1
 
-	res := controller.CartService.PushProductToCart(ctx, cartRequest)
+	err := controller.Validate.Struct(cartRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.CartService.PushProductToCart(ctx, cartRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -46,7 +53,11 @@ func (controller *CartControllerImpl) UpdateProductQuantity(writer http.Response
 	cartRequest.CustomerId = customerId
 	cartRequest.ProductId = productId
 
-	res := controller.CartService.UpdateProductQuantity(ctx, cartRequest)
+	err := controller.Validate.Struct(cartRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.CartService.UpdateProductQuantity(ctx, cartRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -59,8 +70,10 @@ func (controller *CartControllerImpl) PullProductFromCart(writer http.ResponseWr
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 	productId := params.ByName("productId")
+	variantId := request.URL.Query().Get("variantId")
 
-	controller.CartService.PullProductFromCart(ctx, customerId, productId)
+	err := controller.CartService.PullProductFromCart(ctx, customerId, productId, variantId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"github.com/go-playground/validator/v10"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"weplant-backend/helper"
@@ -10,11 +11,13 @@ import (
 
 type CategoryControllerImpl struct {
 	CategoryService service.CategoryService
+	Validate        *validator.Validate
 }
 
-func NewCategoryController(categoryService service.CategoryService) CategoryController {
+func NewCategoryController(categoryService service.CategoryService, validate *validator.Validate) CategoryController {
 	return &CategoryControllerImpl{
 		CategoryService: categoryService,
+		Validate:        validate,
 	}
 }
 
@@ -24,12 +27,15 @@ func (controller *CategoryControllerImpl) Create(writer http.ResponseWriter, req
 	var categoryCreateRequest web.CategoryCreateRequest
 	helper.ReadFromRequestBody(request, &categoryCreateRequest)
 
-	res := controller.CategoryService.Create(ctx, web.CategoryCreateRequest{
-		CreatedAt: helper.GetTimeNow(),
-		UpdatedAt: helper.GetTimeNow(),
-		Name:      categoryCreateRequest.Name,
-		Slug:      helper.SlugGenerate(categoryCreateRequest.Name),
-	})
+	categoryCreateRequest.CreatedAt = helper.GetTimeNow()
+	categoryCreateRequest.UpdatedAt = helper.GetTimeNow()
+	categoryCreateRequest.Slug = helper.SlugGenerate(categoryCreateRequest.Name)
+
+	err := controller.Validate.Struct(categoryCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.CategoryService.Create(ctx, categoryCreateRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -41,8 +47,11 @@ func (controller *CategoryControllerImpl) Create(writer http.ResponseWriter, req
 func (controller *CategoryControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	ctx := request.Context()
 	categoryId := params.ByName("categoryId")
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
 
-	res := controller.CategoryService.FindById(ctx, categoryId)
+	res, err := controller.CategoryService.FindById(ctx, categoryId, cursor, perPage)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -54,7 +63,11 @@ func (controller *CategoryControllerImpl) FindById(writer http.ResponseWriter, r
 func (controller *CategoryControllerImpl) FindAll(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	ctx := request.Context()
 
-	res := controller.CategoryService.FindAll(ctx)
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.CategoryService.FindAll(ctx, cursor, perPage)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -62,3 +75,75 @@ func (controller *CategoryControllerImpl) FindAll(writer http.ResponseWriter, re
 	}
 	helper.WriteToResponseBody(writer, webResponse)
 }
+
+func (controller *CategoryControllerImpl) FindTree(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	res, err := controller.CategoryService.FindTree(ctx)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	categoryId := params.ByName("categoryId")
+
+	var categoryUpdateRequest web.CategoryUpdateRequest
+	helper.ReadFromRequestBody(request, &categoryUpdateRequest)
+
+	categoryUpdateRequest.Id = categoryId
+	categoryUpdateRequest.UpdatedAt = helper.GetTimeNow()
+	categoryUpdateRequest.Slug = helper.SlugGenerate(categoryUpdateRequest.Name)
+
+	err := controller.Validate.Struct(categoryUpdateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.CategoryService.Update(ctx, categoryUpdateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) UpdateMainImage(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	categoryId := params.ByName("categoryId")
+
+	helper.ParseImageForm(writer, request, helper.CategoryImageLimit)
+	mainImage := helper.ReadFormImage(request, "image", helper.CategoryImageLimit)
+
+	res, err := controller.CategoryService.UpdateMainImage(ctx, web.CategoryUpdateImageRequest{
+		Id:        categoryId,
+		UpdatedAt: helper.GetTimeNow(),
+		MainImage: &mainImage,
+	})
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CategoryControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	categoryId := params.ByName("categoryId")
+
+	err := controller.CategoryService.Delete(ctx, categoryId)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nil,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -11,6 +11,8 @@ type CustomerController interface {
 	FindCartById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	FindTransactionById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	FindOrderById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	ConfirmOrder(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RequestRefund(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	UpdateMainImage(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
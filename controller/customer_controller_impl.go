@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"github.com/go-playground/validator/v10"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"weplant-backend/helper"
@@ -10,11 +11,13 @@ import (
 
 type CustomerControllerImpl struct {
 	CustomerService service.CustomerService
+	Validate        *validator.Validate
 }
 
-func NewCustomerController(customerService service.CustomerService) CustomerController {
+func NewCustomerController(customerService service.CustomerService, validate *validator.Validate) CustomerController {
 	return &CustomerControllerImpl{
 		CustomerService: customerService,
+		Validate:        validate,
 	}
 }
 
@@ -27,7 +30,11 @@ func (controller *CustomerControllerImpl) Create(writer http.ResponseWriter, req
 	customerCreateRequest.CreatedAt = helper.GetTimeNow()
 	customerCreateRequest.UpdatedAt = helper.GetTimeNow()
 
-	res := controller.CustomerService.Create(ctx, customerCreateRequest)
+	err := controller.Validate.Struct(customerCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.CustomerService.Create(ctx, customerCreateRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -40,7 +47,8 @@ func (controller *CustomerControllerImpl) FindById(writer http.ResponseWriter, r
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 
-	res := controller.CustomerService.FindById(ctx, customerId)
+	res, err := controller.CustomerService.FindById(ctx, customerId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -53,7 +61,8 @@ func (controller *CustomerControllerImpl) FindCartById(writer http.ResponseWrite
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 
-	res := controller.CustomerService.FindCartById(ctx, customerId)
+	res, err := controller.CustomerService.FindCartById(ctx, customerId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -66,7 +75,11 @@ func (controller *CustomerControllerImpl) FindTransactionById(writer http.Respon
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 
-	res := controller.CustomerService.FindTransactionById(ctx, customerId)
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.CustomerService.FindTransactionById(ctx, customerId, cursor, perPage)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -79,7 +92,54 @@ func (controller *CustomerControllerImpl) FindOrderById(writer http.ResponseWrit
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 
-	res := controller.CustomerService.FindOrderById(ctx, customerId)
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.CustomerService.FindOrderById(ctx, customerId, cursor, perPage)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CustomerControllerImpl) ConfirmOrder(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+	orderId := params.ByName("orderId")
+
+	res, err := controller.CustomerService.ConfirmOrder(ctx, web.OrderConfirmRequest{
+		Id:         orderId,
+		CustomerId: customerId,
+		UpdatedAt:  helper.GetTimeNow(),
+	})
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *CustomerControllerImpl) RequestRefund(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+	orderId := params.ByName("orderId")
+
+	var orderRefundRequest web.OrderRefundRequest
+	helper.ReadFromRequestBody(request, &orderRefundRequest)
+	orderRefundRequest.Id = orderId
+	orderRefundRequest.CustomerId = customerId
+	orderRefundRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(orderRefundRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.CustomerService.RequestRefund(ctx, orderRefundRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -97,7 +157,11 @@ func (controller *CustomerControllerImpl) Update(writer http.ResponseWriter, req
 	customerUpdateRequest.Id = customerId
 	customerUpdateRequest.UpdatedAt = helper.GetTimeNow()
 
-	res := controller.CustomerService.Update(ctx, customerUpdateRequest)
+	err := controller.Validate.Struct(customerUpdateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.CustomerService.Update(ctx, customerUpdateRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -110,19 +174,15 @@ func (controller *CustomerControllerImpl) UpdateMainImage(writer http.ResponseWr
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 
-	file, fileHeader, err := request.FormFile("image")
-	helper.PanicIfError(err)
-
-	filename := helper.GetFileName(fileHeader.Filename)
+	helper.ParseImageForm(writer, request, helper.CustomerImageLimit)
+	mainImage := helper.ReadFormImage(request, "image", helper.CustomerImageLimit)
 
-	res := controller.CustomerService.UpdateMainImage(ctx, web.CustomerUpdateImageRequest{
+	res, err := controller.CustomerService.UpdateMainImage(ctx, web.CustomerUpdateImageRequest{
 		Id:        customerId,
 		UpdatedAt: helper.GetTimeNow(),
-		MainImage: &web.ImageUpdateRequest{
-			FileName: filename,
-			URL:      file,
-		},
+		MainImage: &mainImage,
 	})
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -135,7 +195,8 @@ func (controller *CustomerControllerImpl) Delete(writer http.ResponseWriter, req
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 
-	controller.CustomerService.Delete(ctx, customerId)
+	err := controller.CustomerService.Delete(ctx, customerId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type LedgerController interface {
+	FindByMerchantId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
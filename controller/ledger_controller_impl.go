@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"weplant-backend/helper"
+	"weplant-backend/model/web"
+	"weplant-backend/service"
+)
+
+type LedgerControllerImpl struct {
+	LedgerService service.LedgerService
+}
+
+func NewLedgerController(ledgerService service.LedgerService) LedgerController {
+	return &LedgerControllerImpl{
+		LedgerService: ledgerService,
+	}
+}
+
+func (controller *LedgerControllerImpl) FindByMerchantId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	merchantId := params.ByName("merchantId")
+
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.LedgerService.FindByMerchantId(ctx, merchantId, cursor, perPage)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
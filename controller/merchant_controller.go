@@ -9,6 +9,8 @@ type MerchantController interface {
 	Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	FindManageOrderById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateOrderStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateOrderShipping(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	UpdateMainImage(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"github.com/go-playground/validator/v10"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"weplant-backend/helper"
@@ -10,17 +11,21 @@ import (
 
 type MerchantControllerImpl struct {
 	MerchantService service.MerchantService
+	Validate        *validator.Validate
 }
 
-func NewMerchantController(merchantService service.MerchantService) MerchantController {
+func NewMerchantController(merchantService service.MerchantService, validate *validator.Validate) MerchantController {
 	return &MerchantControllerImpl{
 		MerchantService: merchantService,
+		Validate:        validate,
 	}
 }
 
 func (controller *MerchantControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	ctx := request.Context()
 
+	helper.ParseImageForm(writer, request, helper.MerchantImageLimit)
+
 	email := request.PostFormValue("email")
 	password := request.PostFormValue("password")
 	name := request.PostFormValue("name")
@@ -30,13 +35,9 @@ func (controller *MerchantControllerImpl) Create(writer http.ResponseWriter, req
 	province := request.PostFormValue("province")
 	postalCode := request.PostFormValue("postal_code")
 
-	file, fileHeader, err := request.FormFile("image")
-
-	helper.PanicIfError(err)
-
-	filename := helper.GetFileName(fileHeader.Filename)
+	mainImage := helper.ReadFormImage(request, "image", helper.MerchantImageLimit)
 
-	res := controller.MerchantService.Create(ctx, web.MerchantCreateRequest{
+	merchantCreateRequest := web.MerchantCreateRequest{
 		CreatedAt: helper.GetTimeNow(),
 		UpdatedAt: helper.GetTimeNow(),
 		Email:     email,
@@ -44,17 +45,20 @@ func (controller *MerchantControllerImpl) Create(writer http.ResponseWriter, req
 		Name:      name,
 		Slug:      helper.SlugGenerate(name),
 		Phone:     phone,
-		MainImage: &web.ImageCreateRequest{
-			FileName: filename,
-			URL:      file,
-		},
+		MainImage: &mainImage,
 		Address: &web.AddressCreateRequest{
 			Address:    address,
 			City:       city,
 			Province:   province,
 			PostalCode: postalCode,
 		},
-	})
+	}
+
+	err := controller.Validate.Struct(merchantCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.MerchantService.Create(ctx, merchantCreateRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -67,7 +71,11 @@ func (controller *MerchantControllerImpl) FindById(writer http.ResponseWriter, r
 	ctx := request.Context()
 	merchantId := params.ByName("merchantId")
 
-	res := controller.MerchantService.FindById(ctx, merchantId)
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.MerchantService.FindById(ctx, merchantId, cursor, perPage)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -80,7 +88,59 @@ func (controller *MerchantControllerImpl) FindManageOrderById(writer http.Respon
 	ctx := request.Context()
 	merchantId := params.ByName("merchantId")
 
-	res := controller.MerchantService.FindManageOrderById(ctx, merchantId)
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.MerchantService.FindManageOrderById(ctx, merchantId, cursor, perPage)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *MerchantControllerImpl) UpdateOrderStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	merchantId := params.ByName("merchantId")
+	orderId := params.ByName("orderId")
+
+	var manageOrderUpdateStatusRequest web.ManageOrderUpdateStatusRequest
+	helper.ReadFromRequestBody(request, &manageOrderUpdateStatusRequest)
+	manageOrderUpdateStatusRequest.Id = orderId
+	manageOrderUpdateStatusRequest.MerchantId = merchantId
+	manageOrderUpdateStatusRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(manageOrderUpdateStatusRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.MerchantService.UpdateOrderStatus(ctx, manageOrderUpdateStatusRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *MerchantControllerImpl) UpdateOrderShipping(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	merchantId := params.ByName("merchantId")
+	orderId := params.ByName("orderId")
+
+	var manageOrderUpdateShippingRequest web.ManageOrderUpdateShippingRequest
+	helper.ReadFromRequestBody(request, &manageOrderUpdateShippingRequest)
+	manageOrderUpdateShippingRequest.Id = orderId
+	manageOrderUpdateShippingRequest.MerchantId = merchantId
+	manageOrderUpdateShippingRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(manageOrderUpdateShippingRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.MerchantService.UpdateOrderShipping(ctx, manageOrderUpdateShippingRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -99,7 +159,11 @@ func (controller *MerchantControllerImpl) Update(writer http.ResponseWriter, req
 	merchantUpdateRequest.Id = merchantId
 	merchantUpdateRequest.UpdatedAt = helper.GetTimeNow()
 
-	res := controller.MerchantService.Update(ctx, merchantUpdateRequest)
+	err := controller.Validate.Struct(merchantUpdateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.MerchantService.Update(ctx, merchantUpdateRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -112,19 +176,15 @@ func (controller *MerchantControllerImpl) UpdateMainImage(writer http.ResponseWr
 	ctx := request.Context()
 	merchantId := params.ByName("merchantId")
 
-	file, fileHeader, err := request.FormFile("image")
-
-	helper.PanicIfError(err)
-	filename := helper.GetFileName(fileHeader.Filename)
+	helper.ParseImageForm(writer, request, helper.MerchantImageLimit)
+	mainImage := helper.ReadFormImage(request, "image", helper.MerchantImageLimit)
 
-	res := controller.MerchantService.UpdateMainImage(ctx, web.MerchantUpdateImageRequest{
+	res, err := controller.MerchantService.UpdateMainImage(ctx, web.MerchantUpdateImageRequest{
 		Id:        merchantId,
 		UpdatedAt: helper.GetTimeNow(),
-		MainImage: &web.ImageUpdateRequest{
-			FileName: filename,
-			URL:      file,
-		},
+		MainImage: &mainImage,
 	})
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -137,7 +197,8 @@ func (controller *MerchantControllerImpl) Delete(writer http.ResponseWriter, req
 	ctx := request.Context()
 	merchantId := params.ByName("merchantId")
 
-	controller.MerchantService.Delete(ctx, merchantId)
+	err := controller.MerchantService.Delete(ctx, merchantId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
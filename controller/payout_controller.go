@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type PayoutController interface {
+	Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindByMerchantId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
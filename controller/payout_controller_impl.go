@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"weplant-backend/helper"
+	"weplant-backend/model/web"
+	"weplant-backend/service"
+)
+
+type PayoutControllerImpl struct {
+	PayoutService service.PayoutService
+	Validate      *validator.Validate
+}
+
+func NewPayoutController(payoutService service.PayoutService, validate *validator.Validate) PayoutController {
+	return &PayoutControllerImpl{
+		PayoutService: payoutService,
+		Validate:      validate,
+	}
+}
+
+func (controller *PayoutControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	merchantId := params.ByName("merchantId")
+
+	var payoutCreateRequest web.PayoutCreateRequest
+	helper.ReadFromRequestBody(request, &payoutCreateRequest)
+	payoutCreateRequest.MerchantId = merchantId
+	payoutCreateRequest.CreatedAt = helper.GetTimeNow()
+	payoutCreateRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(payoutCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.PayoutService.Create(ctx, payoutCreateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *PayoutControllerImpl) FindByMerchantId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	merchantId := params.ByName("merchantId")
+
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.PayoutService.FindByMerchantId(ctx, merchantId, cursor, perPage)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *PayoutControllerImpl) UpdateStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	payoutId := params.ByName("payoutId")
+
+	var payoutUpdateStatusRequest web.PayoutUpdateStatusRequest
+	helper.ReadFromRequestBody(request, &payoutUpdateStatusRequest)
+	payoutUpdateStatusRequest.Id = payoutId
+	payoutUpdateStatusRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(payoutUpdateStatusRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.PayoutService.UpdateStatus(ctx, payoutUpdateStatusRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -14,4 +14,7 @@ type ProductController interface {
 	PushImageIntoImages(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	PullImageFromImages(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	CreateVariant(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateVariant(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteVariant(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
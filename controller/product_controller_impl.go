@@ -1,10 +1,9 @@
 package controller
 
 import (
-	"errors"
+	"github.com/go-playground/validator/v10"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
-	"strconv"
 	"weplant-backend/helper"
 	"weplant-backend/model/web"
 	"weplant-backend/service"
@@ -12,48 +11,36 @@ import (
 
 type ProductControllerImpl struct {
 	ProductService service.ProductService
+	Validate       *validator.Validate
 }
 
-func NewProductController(productService service.ProductService) ProductController {
+func NewProductController(productService service.ProductService, validate *validator.Validate) ProductController {
 	return &ProductControllerImpl{
 		ProductService: productService,
+		Validate:       validate,
 	}
 }
 
 func (controller *ProductControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	ctx := request.Context()
 
-	err := request.ParseMultipartForm(32 << 20)
-	helper.PanicIfError(err)
+	helper.ParseImageForm(writer, request, helper.ProductImageLimit)
 
 	merchantId := request.PostFormValue("merchant_id")
 	name := request.PostFormValue("name")
 	description := request.PostFormValue("description")
-	price, err := strconv.Atoi(request.PostFormValue("price"))
-	helper.PanicIfError(err)
-	stock, err := strconv.Atoi(request.PostFormValue("stock"))
-	helper.PanicIfError(err)
+	price := helper.ReadFormInt(request, "price")
+	stock := helper.ReadFormInt(request, "stock")
+	weight := helper.ReadFormInt(request, "weight")
+	length := helper.ReadFormIntDefault(request, "length", 0)
+	width := helper.ReadFormIntDefault(request, "width", 0)
+	height := helper.ReadFormIntDefault(request, "height", 0)
 
 	// main image
-	file, fileHeader, err := request.FormFile("image")
-	helper.PanicIfError(err)
-	if file == nil {
-		panic(errors.New("no file").Error())
-	}
-	filename := helper.GetFileName(fileHeader.Filename)
+	mainImage := helper.ReadFormImage(request, "image", helper.ProductImageLimit)
 
 	// images
-	images := request.MultipartForm.File["images"]
-	var imagesCreateRequest []web.ImageCreateRequest
-	for _, img := range images {
-		file, err := img.Open()
-		helper.PanicIfError(err)
-		file.Close()
-		imagesCreateRequest = append(imagesCreateRequest, web.ImageCreateRequest{
-			FileName: helper.GetFileName(img.Filename),
-			URL:      file,
-		})
-	}
+	imagesCreateRequest := helper.ReadFormImages(request, "images", helper.ProductImageLimit)
 
 	// categories
 	categories := request.PostForm["categories"]
@@ -64,7 +51,7 @@ func (controller *ProductControllerImpl) Create(writer http.ResponseWriter, requ
 		})
 	}
 
-	res := controller.ProductService.Create(ctx, web.ProductCreateRequest{
+	productCreateRequest := web.ProductCreateRequest{
 		CreatedAt:   helper.GetTimeNow(),
 		UpdatedAt:   helper.GetTimeNow(),
 		MerchantId:  merchantId,
@@ -73,13 +60,20 @@ func (controller *ProductControllerImpl) Create(writer http.ResponseWriter, requ
 		Description: description,
 		Price:       price,
 		Stock:       stock,
-		MainImage: &web.ImageCreateRequest{
-			FileName: filename,
-			URL:      file,
-		},
-		Images:     imagesCreateRequest,
-		Categories: categoriesCreateRequest,
-	})
+		Weight:      weight,
+		Length:      length,
+		Width:       width,
+		Height:      height,
+		MainImage:   &mainImage,
+		Images:      imagesCreateRequest,
+		Categories:  categoriesCreateRequest,
+	}
+
+	err := controller.Validate.Struct(productCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ProductService.Create(ctx, productCreateRequest)
+	helper.PanicIfError(err)
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
@@ -93,7 +87,8 @@ func (controller *ProductControllerImpl) FindById(writer http.ResponseWriter, re
 	ctx := request.Context()
 	productId := params.ByName("productId")
 
-	res := controller.ProductService.FindById(ctx, productId)
+	res, err := controller.ProductService.FindById(ctx, productId)
+	helper.PanicIfError(err)
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
@@ -105,49 +100,33 @@ func (controller *ProductControllerImpl) FindById(writer http.ResponseWriter, re
 
 func (controller *ProductControllerImpl) FindAll(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	ctx := request.Context()
-
-	var page int
-	var perPage int
-
-	queryPage := request.URL.Query().Get("page")
-	queryPerPage := request.URL.Query().Get("perPage")
-
-	if queryPage == "" {
-		page = 1
-	} else {
-		a, err := strconv.Atoi(queryPage)
-		helper.PanicIfError(err)
-		page = a
+	query := request.URL.Query()
+
+	productSearchRequest := web.ProductSearchRequest{
+		Search:      query.Get("search"),
+		CategoryIds: query["category"],
+		MerchantId:  query.Get("merchant"),
+		MinPrice:    helper.ReadQueryInt(request, "minPrice", 0),
+		MaxPrice:    helper.ReadQueryInt(request, "maxPrice", 0),
+		InStock:     query.Get("inStock") == "true",
+		City:        query.Get("city"),
+		Province:    query.Get("province"),
+		Sort:        query.Get("sort"),
+		Cursor:      helper.ReadQueryCursor(request, "cursor"),
+		PerPage:     helper.ReadQueryInt(request, "perPage", 10),
 	}
 
-	if queryPerPage == "" {
-		perPage = 10
-	} else {
-		a, err := strconv.Atoi(queryPerPage)
-		helper.PanicIfError(err)
-		perPage = a
-	}
+	err := controller.Validate.Struct(productSearchRequest)
+	helper.PanicIfValidationError(err)
 
-	search := request.URL.Query().Get("search")
-
-	if search != "" {
-		res := controller.ProductService.FindAllWithSearch(ctx, search, page, perPage)
-		webResponse := web.WebResponse{
-			Code:   http.StatusOK,
-			Status: "OK",
-			Data:   res,
-		}
-		helper.WriteToResponseBody(writer, webResponse)
-	} else {
-		res := controller.ProductService.FindAll(ctx, page, perPage)
-		webResponse := web.WebResponse{
-			Code:   http.StatusOK,
-			Status: "OK",
-			Data:   res,
-		}
-		helper.WriteToResponseBody(writer, webResponse)
+	res, err := controller.ProductService.Search(ctx, productSearchRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
 	}
-
+	helper.WriteToResponseBody(writer, webResponse)
 }
 
 func (controller *ProductControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -161,7 +140,11 @@ func (controller *ProductControllerImpl) Update(writer http.ResponseWriter, requ
 	productUpdateRequest.Id = productId
 	productUpdateRequest.UpdatedAt = helper.GetTimeNow()
 
-	res := controller.ProductService.Update(ctx, productUpdateRequest)
+	err := controller.Validate.Struct(productUpdateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ProductService.Update(ctx, productUpdateRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -174,19 +157,15 @@ func (controller *ProductControllerImpl) UpdateMainImage(writer http.ResponseWri
 	ctx := request.Context()
 	productId := params.ByName("productId")
 
-	file, fileHeader, err := request.FormFile("image")
-	helper.PanicIfError(err)
-
-	filename := helper.GetFileName(fileHeader.Filename)
+	helper.ParseImageForm(writer, request, helper.ProductImageLimit)
+	mainImage := helper.ReadFormImage(request, "image", helper.ProductImageLimit)
 
-	res := controller.ProductService.UpdateMainImage(ctx, web.ProductUpdateImageRequest{
+	res, err := controller.ProductService.UpdateMainImage(ctx, web.ProductUpdateImageRequest{
 		Id:        productId,
 		UpdatedAt: helper.GetTimeNow(),
-		MainImage: &web.ImageUpdateRequest{
-			FileName: filename,
-			URL:      file,
-		},
+		MainImage: &mainImage,
 	})
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -200,7 +179,8 @@ func (controller *ProductControllerImpl) PullImageFromImages(writer http.Respons
 	productId := params.ByName("productId")
 	imageId := params.ByName("imageId")
 
-	controller.ProductService.PullImageFromImages(ctx, productId, imageId)
+	err := controller.ProductService.PullImageFromImages(ctx, productId, imageId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -213,35 +193,81 @@ func (controller *ProductControllerImpl) PushImageIntoImages(writer http.Respons
 	ctx := request.Context()
 	productId := params.ByName("productId")
 
-	err := request.ParseMultipartForm(32 << 20)
+	helper.ParseImageForm(writer, request, helper.ProductImageLimit)
+	imageCreateRequest := helper.ReadFormImages(request, "images", helper.ProductImageLimit)
+
+	res, err := controller.ProductService.PushImageIntoImages(ctx, productId, imageCreateRequest)
 	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
 
-	images := request.MultipartForm.File["images"]
+func (controller *ProductControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	productId := params.ByName("productId")
 
-	var imageCreateRequest []web.ImageCreateRequest
-	for _, image := range images {
-		file, err := image.Open()
-		helper.PanicIfError(err)
-		imageCreateRequest = append(imageCreateRequest, web.ImageCreateRequest{
-			FileName: helper.GetFileName(image.Filename),
-			URL:      file,
-		})
+	err := controller.ProductService.Delete(ctx, productId)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nil,
 	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
 
-	controller.ProductService.PushImageIntoImages(ctx, productId, imageCreateRequest)
+func (controller *ProductControllerImpl) CreateVariant(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var variantCreateRequest web.ProductVariantCreateRequest
+	helper.ReadFromRequestBody(request, &variantCreateRequest)
+	variantCreateRequest.ProductId = params.ByName("productId")
+
+	err := controller.Validate.Struct(variantCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ProductService.CreateVariant(ctx, variantCreateRequest)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
-		Data:   nil,
+		Data:   res,
 	}
 	helper.WriteToResponseBody(writer, webResponse)
 }
 
-func (controller *ProductControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+func (controller *ProductControllerImpl) UpdateVariant(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var variantUpdateRequest web.ProductVariantUpdateRequest
+	helper.ReadFromRequestBody(request, &variantUpdateRequest)
+	variantUpdateRequest.Id = params.ByName("variantId")
+	variantUpdateRequest.ProductId = params.ByName("productId")
+
+	err := controller.Validate.Struct(variantUpdateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ProductService.UpdateVariant(ctx, variantUpdateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ProductControllerImpl) DeleteVariant(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	ctx := request.Context()
 	productId := params.ByName("productId")
+	variantId := params.ByName("variantId")
 
-	controller.ProductService.Delete(ctx, productId)
+	err := controller.ProductService.DeleteVariant(ctx, productId, variantId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type ReviewController interface {
+	Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindByProductId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Reply(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"weplant-backend/helper"
+	"weplant-backend/model/web"
+	"weplant-backend/service"
+)
+
+type ReviewControllerImpl struct {
+	ReviewService service.ReviewService
+	Validate      *validator.Validate
+}
+
+func NewReviewController(reviewService service.ReviewService, validate *validator.Validate) ReviewController {
+	return &ReviewControllerImpl{
+		ReviewService: reviewService,
+		Validate:      validate,
+	}
+}
+
+func (controller *ReviewControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+
+	helper.ParseImageForm(writer, request, helper.ReviewImageLimit)
+
+	// photos are optional
+	imagesCreateRequest := helper.ReadFormImages(request, "images", helper.ReviewImageLimit)
+
+	reviewCreateRequest := web.ReviewCreateRequest{
+		CreatedAt:  helper.GetTimeNow(),
+		UpdatedAt:  helper.GetTimeNow(),
+		CustomerId: customerId,
+		OrderId:    request.PostFormValue("order_id"),
+		ProductId:  request.PostFormValue("product_id"),
+		Rating:     helper.ReadFormInt(request, "rating"),
+		Text:       request.PostFormValue("text"),
+		Images:     imagesCreateRequest,
+	}
+
+	err := controller.Validate.Struct(reviewCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ReviewService.Create(ctx, reviewCreateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ReviewControllerImpl) FindByProductId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	productId := params.ByName("productId")
+
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.ReviewService.FindByProductId(ctx, productId, cursor, perPage)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ReviewControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var reviewUpdateRequest web.ReviewUpdateRequest
+	helper.ReadFromRequestBody(request, &reviewUpdateRequest)
+	reviewUpdateRequest.Id = params.ByName("reviewId")
+	reviewUpdateRequest.CustomerId = params.ByName("customerId")
+	reviewUpdateRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(reviewUpdateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ReviewService.Update(ctx, reviewUpdateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ReviewControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+	reviewId := params.ByName("reviewId")
+
+	err := controller.ReviewService.Delete(ctx, customerId, reviewId)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nil,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ReviewControllerImpl) Reply(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var reviewReplyRequest web.ReviewReplyRequest
+	helper.ReadFromRequestBody(request, &reviewReplyRequest)
+	reviewReplyRequest.Id = params.ByName("reviewId")
+	reviewReplyRequest.MerchantId = params.ByName("merchantId")
+	reviewReplyRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(reviewReplyRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ReviewService.Reply(ctx, reviewReplyRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
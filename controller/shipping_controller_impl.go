@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"weplant-backend/helper"
+	"weplant-backend/model/web"
+	"weplant-backend/service"
+)
+
+type ShippingControllerImpl struct {
+	ShippingService service.ShippingService
+	Validate        *validator.Validate
+}
+
+func NewShippingController(shippingService service.ShippingService, validate *validator.Validate) ShippingController {
+	return &ShippingControllerImpl{
+		ShippingService: shippingService,
+		Validate:        validate,
+	}
+}
+
+func (controller *ShippingControllerImpl) Quote(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+
+	shippingQuoteRequest := web.ShippingQuoteRequest{
+		CustomerId: customerId,
+		Province:   request.URL.Query().Get("province"),
+	}
+
+	err := controller.Validate.Struct(shippingQuoteRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.ShippingService.Quote(ctx, shippingQuoteRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
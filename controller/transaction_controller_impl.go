@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"github.com/go-playground/validator/v10"
 	"github.com/julienschmidt/httprouter"
 	"github.com/midtrans/midtrans-go/coreapi"
 	"net/http"
+	"weplant-backend/exception"
 	"weplant-backend/helper"
 	"weplant-backend/model/web"
 	"weplant-backend/service"
@@ -11,11 +13,15 @@ import (
 
 type TransactionControllerImpl struct {
 	TransactionService service.TransactionService
+	Validate           *validator.Validate
+	ServerKey          string
 }
 
-func NewTransactionController(transactionService service.TransactionService) TransactionController {
+func NewTransactionController(transactionService service.TransactionService, validate *validator.Validate, serverKey string) TransactionController {
 	return &TransactionControllerImpl{
 		TransactionService: transactionService,
+		Validate:           validate,
+		ServerKey:          serverKey,
 	}
 }
 
@@ -23,15 +29,30 @@ func (controller *TransactionControllerImpl) Create(writer http.ResponseWriter,
 	ctx := request.Context()
 	customerId := params.ByName("customerId")
 
-	var addressCreateRequest web.AddressCreateRequest
-	helper.ReadFromRequestBody(request, &addressCreateRequest)
+	// the address, the payment method and the courier are sent side by side in the body
+	var body struct {
+		web.AddressCreateRequest
+		web.TransactionPaymentRequest
+		web.TransactionShippingRequest
+		VoucherCode string `json:"voucher_code"`
+	}
+	helper.ReadFromRequestBody(request, &body)
+
+	transactionCreateRequest := web.TransactionCreateRequest{
+		CreatedAt:   helper.GetTimeNow(),
+		UpdatedAt:   helper.GetTimeNow(),
+		CustomerId:  customerId,
+		Address:     &body.AddressCreateRequest,
+		Payment:     &body.TransactionPaymentRequest,
+		Shipping:    &body.TransactionShippingRequest,
+		VoucherCode: body.VoucherCode,
+	}
 
-	res := controller.TransactionService.Create(ctx, web.TransactionCreateRequest{
-		CreatedAt:  helper.GetTimeNow(),
-		UpdatedAt:  helper.GetTimeNow(),
-		CustomerId: customerId,
-		Address:    &addressCreateRequest,
-	})
+	err := controller.Validate.Struct(transactionCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.TransactionService.Create(ctx, transactionCreateRequest)
+	helper.PanicIfError(err)
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
@@ -46,7 +67,8 @@ func (controller *TransactionControllerImpl) Cancel(writer http.ResponseWriter,
 	customerId := params.ByName("customerId")
 	transactionId := params.ByName("transactionId")
 
-	controller.TransactionService.Cancel(ctx, customerId, transactionId)
+	err := controller.TransactionService.Cancel(ctx, customerId, transactionId)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
@@ -61,7 +83,13 @@ func (controller *TransactionControllerImpl) Callback(writer http.ResponseWriter
 	var cb coreapi.TransactionStatusResponse
 	helper.ReadFromRequestBody(request, &cb)
 
-	controller.TransactionService.Callback(ctx, cb)
+	if !helper.VerifyMidtransSignature(cb, controller.ServerKey) {
+		helper.GetLogger(ctx).Info("rejected midtrans notification", "order_id", cb.OrderID, "status_code", cb.StatusCode, "remote_addr", request.RemoteAddr)
+		panic(exception.NewForbiddenError("signature key is invalid"))
+	}
+
+	err := controller.TransactionService.Callback(ctx, cb)
+	helper.PanicIfError(err)
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
 		Status: "OK",
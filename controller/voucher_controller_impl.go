@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"weplant-backend/helper"
+	"weplant-backend/model/web"
+	"weplant-backend/service"
+)
+
+// VoucherControllerImpl serves the merchant and the admin routes, the admin routes have no merchant id and manage the platform vouchers
+type VoucherControllerImpl struct {
+	VoucherService service.VoucherService
+	Validate       *validator.Validate
+}
+
+func NewVoucherController(voucherService service.VoucherService, validate *validator.Validate) VoucherController {
+	return &VoucherControllerImpl{
+		VoucherService: voucherService,
+		Validate:       validate,
+	}
+}
+
+func (controller *VoucherControllerImpl) Create(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var voucherCreateRequest web.VoucherCreateRequest
+	helper.ReadFromRequestBody(request, &voucherCreateRequest)
+	voucherCreateRequest.MerchantId = params.ByName("merchantId")
+	voucherCreateRequest.CreatedAt = helper.GetTimeNow()
+	voucherCreateRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(voucherCreateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.VoucherService.Create(ctx, voucherCreateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *VoucherControllerImpl) FindByMerchantId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	merchantId := params.ByName("merchantId")
+
+	cursor := helper.ReadQueryCursor(request, "cursor")
+	perPage := helper.ReadQueryInt(request, "perPage", 10)
+
+	res, err := controller.VoucherService.FindByMerchantId(ctx, merchantId, cursor, perPage)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *VoucherControllerImpl) Update(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var voucherUpdateRequest web.VoucherUpdateRequest
+	helper.ReadFromRequestBody(request, &voucherUpdateRequest)
+	voucherUpdateRequest.Id = params.ByName("voucherId")
+	voucherUpdateRequest.MerchantId = params.ByName("merchantId")
+	voucherUpdateRequest.UpdatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(voucherUpdateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.VoucherService.Update(ctx, voucherUpdateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *VoucherControllerImpl) Delete(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	merchantId := params.ByName("merchantId")
+	voucherId := params.ByName("voucherId")
+
+	err := controller.VoucherService.Delete(ctx, merchantId, voucherId)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nil,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *VoucherControllerImpl) ValidateCode(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	var voucherValidateRequest web.VoucherValidateRequest
+	helper.ReadFromRequestBody(request, &voucherValidateRequest)
+	voucherValidateRequest.CustomerId = params.ByName("customerId")
+
+	err := controller.Validate.Struct(voucherValidateRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.VoucherService.ValidateCode(ctx, voucherValidateRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+type WishlistController interface {
+	FindByCustomerId(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	PushProductToWishlist(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	PullProductFromWishlist(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	MoveToCart(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"weplant-backend/helper"
+	"weplant-backend/model/web"
+	"weplant-backend/service"
+)
+
+type WishlistControllerImpl struct {
+	WishlistService service.WishlistService
+	Validate        *validator.Validate
+}
+
+func NewWishlistController(wishlistService service.WishlistService, validate *validator.Validate) WishlistController {
+	return &WishlistControllerImpl{
+		WishlistService: wishlistService,
+		Validate:        validate,
+	}
+}
+
+func (controller *WishlistControllerImpl) FindByCustomerId(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+
+	res, err := controller.WishlistService.FindByCustomerId(ctx, customerId)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *WishlistControllerImpl) PushProductToWishlist(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+
+	var wishlistRequest web.WishlistProductCreateRequest
+	helper.ReadFromRequestBody(request, &wishlistRequest)
+	wishlistRequest.CustomerId = customerId
+	wishlistRequest.CreatedAt = helper.GetTimeNow()
+
+	err := controller.Validate.Struct(wishlistRequest)
+	helper.PanicIfValidationError(err)
+
+	res, err := controller.WishlistService.PushProductToWishlist(ctx, wishlistRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   res,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *WishlistControllerImpl) PullProductFromWishlist(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+	customerId := params.ByName("customerId")
+	productId := params.ByName("productId")
+
+	err := controller.WishlistService.PullProductFromWishlist(ctx, customerId, productId)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nil,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *WishlistControllerImpl) MoveToCart(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ctx := request.Context()
+
+	moveRequest := web.WishlistMoveToCartRequest{
+		CustomerId: params.ByName("customerId"),
+		ProductId:  params.ByName("productId"),
+		VariantId:  request.URL.Query().Get("variantId"),
+	}
+
+	err := controller.Validate.Struct(moveRequest)
+	helper.PanicIfValidationError(err)
+
+	err = controller.WishlistService.MoveToCart(ctx, moveRequest)
+	helper.PanicIfError(err)
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   nil,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
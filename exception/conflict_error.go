@@ -0,0 +1,29 @@
+package exception
+
+import "net/http"
+
+type ConflictError struct {
+	Message string
+	Code    string
+}
+
+func NewConflictError(error string) ConflictError {
+	return ConflictError{Message: error, Code: ErrorCodeConflict}
+}
+
+// NewDuplicateKeyError is used when mongo rejects a write because of a unique index
+func NewDuplicateKeyError(error string) ConflictError {
+	return ConflictError{Message: error, Code: ErrorCodeDuplicateKey}
+}
+
+func (e ConflictError) Error() string {
+	return e.Message
+}
+
+func (e ConflictError) StatusCode() int {
+	return http.StatusConflict
+}
+
+func (e ConflictError) ErrorCode() string {
+	return e.Code
+}
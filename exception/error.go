@@ -0,0 +1,21 @@
+package exception
+
+const (
+	ErrorCodeValidation      = "VALIDATION_ERROR"
+	ErrorCodeUnauthorized    = "UNAUTHORIZED"
+	ErrorCodeForbidden       = "FORBIDDEN"
+	ErrorCodeNotFound        = "NOT_FOUND"
+	ErrorCodeConflict        = "CONFLICT"
+	ErrorCodeDuplicateKey    = "DUPLICATE_KEY"
+	ErrorCodeOutOfStock      = "OUT_OF_STOCK"
+	ErrorCodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+	ErrorCodePaymentGateway  = "PAYMENT_GATEWAY_ERROR"
+	ErrorCodeInternal        = "INTERNAL_SERVER_ERROR"
+)
+
+// Error is implemented by every domain error, ErrorHandler uses it to pick the status and error code
+type Error interface {
+	error
+	StatusCode() int
+	ErrorCode() string
+}
@@ -2,80 +2,56 @@ package exception
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"weplant-backend/model/web"
 )
 
 func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interface{}) {
-	if notFoundError(writer, request, err) {
+	if validationError(writer, request, err) {
 		return
 	}
-	if notUnauthorizedError(writer, request, err) {
+	if domainError(writer, request, err) {
 		return
 	}
-	//if validationError(writer, request, err) {
-	//	return
-	//}
 	internalServerError(writer, request, err)
 }
 
-//func validationError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
-//	exception, ok := err.(validator.ValidationErrors)
-//	if ok {
-//		writer.Header().Set("Content-Type", "application/json")
-//		writer.WriteHeader(http.StatusBadRequest)
-//
-//		webResponse := web.WebResponse{
-//			Code: http.StatusBadRequest,
-//			Status: "Bad Request",
-//			Data: exception.Error(),
-//		}
-//		helper.WriteToResponseBody(writer, webResponse)
-//		return true
-//	}else {
-//		return false
-//	}
-//}
-
-func notFoundError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
-	exception, ok := err.(NotFoundError)
-	if ok {
-		writer.Header().Set("Content-Type", "application/json")
-		writer.WriteHeader(http.StatusNotFound)
-
-		webResponse := web.WebResponse{
-			Code:   http.StatusNotFound,
-			Status: "NOT FOUND",
-			Data:   exception.Error,
-		}
-		writer.Header().Add("Content-Type", "application/json")
-		encoder := json.NewEncoder(writer)
-		err := encoder.Encode(webResponse)
-		if err != nil {
-			panic(err)
-		}
+func validationError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	e, ok := err.(error)
+	if !ok {
+		return false
+	}
+	var exception ValidationError
+	if errors.As(e, &exception) {
+		writeErrorResponse(writer, web.WebResponse{
+			Code:      exception.StatusCode(),
+			Status:    statusText(exception.StatusCode()),
+			ErrorCode: exception.ErrorCode(),
+			Data:      exception.Error(),
+			Errors:    exception.Fields,
+		})
 		return true
 	} else {
 		return false
 	}
 }
-func notUnauthorizedError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
-	exception, ok := err.(UnauthorizedError)
-	if ok {
-		writer.Header().Set("Content-Type", "application/json")
-		writer.WriteHeader(http.StatusUnauthorized)
 
-		webResponse := web.WebResponse{
-			Code:   http.StatusUnauthorized,
-			Status: "UNAUTHORIZED",
-			Data:   exception.Error,
-		}
-		writer.Header().Add("Content-Type", "application/json")
-		encoder := json.NewEncoder(writer)
-		err := encoder.Encode(webResponse)
-		if err != nil {
-			panic(err)
-		}
+func domainError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	e, ok := err.(error)
+	if !ok {
+		return false
+	}
+	var exception Error
+	if errors.As(e, &exception) {
+		writeErrorResponse(writer, web.WebResponse{
+			Code:      exception.StatusCode(),
+			Status:    statusText(exception.StatusCode()),
+			ErrorCode: exception.ErrorCode(),
+			Data:      exception.Error(),
+		})
 		return true
 	} else {
 		return false
@@ -83,19 +59,35 @@ func notUnauthorizedError(writer http.ResponseWriter, request *http.Request, err
 }
 
 func internalServerError(writer http.ResponseWriter, request *http.Request, err interface{}) {
+	var message string
+	switch e := err.(type) {
+	case error:
+		message = e.Error()
+	case string:
+		message = e
+	default:
+		message = fmt.Sprint(e)
+	}
+
+	writeErrorResponse(writer, web.WebResponse{
+		Code:      http.StatusInternalServerError,
+		Status:    statusText(http.StatusInternalServerError),
+		ErrorCode: ErrorCodeInternal,
+		Data:      message,
+	})
+}
+
+func writeErrorResponse(writer http.ResponseWriter, webResponse web.WebResponse) {
 	writer.Header().Set("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusInternalServerError)
+	writer.WriteHeader(webResponse.Code)
 
-	webResponse := web.WebResponse{
-		Code:   http.StatusInternalServerError,
-		Status: "Internal Server Error",
-		Data:   err,
-	}
-	writer.Header().Add("Content-Type", "application/json")
 	encoder := json.NewEncoder(writer)
-	err = encoder.Encode(webResponse)
+	err := encoder.Encode(webResponse)
 	if err != nil {
 		panic(err)
 	}
-	//helper.WriteToResponseBody(writer, webResponse)
+}
+
+func statusText(code int) string {
+	return strings.ToUpper(http.StatusText(code))
 }
@@ -0,0 +1,23 @@
+package exception
+
+import "net/http"
+
+type ForbiddenError struct {
+	Message string
+}
+
+func NewForbiddenError(error string) ForbiddenError {
+	return ForbiddenError{Message: error}
+}
+
+func (e ForbiddenError) Error() string {
+	return e.Message
+}
+
+func (e ForbiddenError) StatusCode() int {
+	return http.StatusForbidden
+}
+
+func (e ForbiddenError) ErrorCode() string {
+	return ErrorCodeForbidden
+}
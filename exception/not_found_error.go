@@ -1,9 +1,23 @@
 package exception
 
+import "net/http"
+
 type NotFoundError struct {
-	Error string
+	Message string
 }
 
 func NewNotFoundError(error string) NotFoundError {
-	return NotFoundError{Error: error}
+	return NotFoundError{Message: error}
+}
+
+func (e NotFoundError) Error() string {
+	return e.Message
+}
+
+func (e NotFoundError) StatusCode() int {
+	return http.StatusNotFound
+}
+
+func (e NotFoundError) ErrorCode() string {
+	return ErrorCodeNotFound
 }
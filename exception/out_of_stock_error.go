@@ -0,0 +1,24 @@
+package exception
+
+import "net/http"
+
+type OutOfStockError struct {
+	Message   string
+	ProductId string
+}
+
+func NewOutOfStockError(error string, productId string) OutOfStockError {
+	return OutOfStockError{Message: error, ProductId: productId}
+}
+
+func (e OutOfStockError) Error() string {
+	return e.Message
+}
+
+func (e OutOfStockError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+func (e OutOfStockError) ErrorCode() string {
+	return ErrorCodeOutOfStock
+}
@@ -0,0 +1,23 @@
+package exception
+
+import "net/http"
+
+type PayloadTooLargeError struct {
+	Message string
+}
+
+func NewPayloadTooLargeError(error string) PayloadTooLargeError {
+	return PayloadTooLargeError{Message: error}
+}
+
+func (e PayloadTooLargeError) Error() string {
+	return e.Message
+}
+
+func (e PayloadTooLargeError) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+func (e PayloadTooLargeError) ErrorCode() string {
+	return ErrorCodePayloadTooLarge
+}
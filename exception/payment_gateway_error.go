@@ -0,0 +1,23 @@
+package exception
+
+import "net/http"
+
+type PaymentGatewayError struct {
+	Message string
+}
+
+func NewPaymentGatewayError(error string) PaymentGatewayError {
+	return PaymentGatewayError{Message: error}
+}
+
+func (e PaymentGatewayError) Error() string {
+	return e.Message
+}
+
+func (e PaymentGatewayError) StatusCode() int {
+	return http.StatusBadGateway
+}
+
+func (e PaymentGatewayError) ErrorCode() string {
+	return ErrorCodePaymentGateway
+}
@@ -1,9 +1,23 @@
 package exception
 
+import "net/http"
+
 type UnauthorizedError struct {
-	Error string
+	Message string
 }
 
 func NewUnauthorizedError(error string) UnauthorizedError {
-	return UnauthorizedError{Error: error}
+	return UnauthorizedError{Message: error}
+}
+
+func (e UnauthorizedError) Error() string {
+	return e.Message
+}
+
+func (e UnauthorizedError) StatusCode() int {
+	return http.StatusUnauthorized
+}
+
+func (e UnauthorizedError) ErrorCode() string {
+	return ErrorCodeUnauthorized
 }
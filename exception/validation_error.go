@@ -0,0 +1,27 @@
+package exception
+
+import (
+	"net/http"
+	"weplant-backend/model/web"
+)
+
+type ValidationError struct {
+	Message string
+	Fields  []web.FieldErrorResponse
+}
+
+func NewValidationError(error string, fields ...web.FieldErrorResponse) ValidationError {
+	return ValidationError{Message: error, Fields: fields}
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+func (e ValidationError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (e ValidationError) ErrorCode() string {
+	return ErrorCodeValidation
+}
@@ -0,0 +1,54 @@
+package helper
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+type contextKey string
+
+const (
+	jwtPayloadKey contextKey = "jwt_payload"
+	loggerKey     contextKey = "logger"
+	requestLogKey contextKey = "request_log"
+)
+
+func SetJWTPayload(ctx context.Context, payload web.JWTPayload) context.Context {
+	return context.WithValue(ctx, jwtPayloadKey, payload)
+}
+
+func GetJWTPayload(ctx context.Context) (web.JWTPayload, bool) {
+	payload, ok := ctx.Value(jwtPayloadKey).(web.JWTPayload)
+	return payload, ok
+}
+
+func SetLogger(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// GetLogger returns the request scoped logger, DefaultLogger outside of a request
+func GetLogger(ctx context.Context) *Logger {
+	logger, ok := ctx.Value(loggerKey).(*Logger)
+	if !ok {
+		return DefaultLogger
+	}
+	return logger
+}
+
+// RequestLog collects what the handlers learn about a request for its log line, handlers further down only get a
+// copy of the request so they fill this in instead of the context
+type RequestLog struct {
+	UserId string
+	Role   string
+	Panic  interface{}
+	Stack  []byte
+}
+
+func SetRequestLog(ctx context.Context, requestLog *RequestLog) context.Context {
+	return context.WithValue(ctx, requestLogKey, requestLog)
+}
+
+func GetRequestLog(ctx context.Context) (*RequestLog, bool) {
+	requestLog, ok := ctx.Value(requestLogKey).(*RequestLog)
+	return requestLog, ok
+}
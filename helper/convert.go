@@ -1,9 +1,15 @@
 package helper
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/exception"
+)
 
 func ObjectIDFromHex(id string) primitive.ObjectID {
 	objectId, err := primitive.ObjectIDFromHex(id)
-	PanicIfError(err)
+	if err != nil {
+		panic(exception.NewValidationError(fmt.Sprintf("%s is not a valid id", id)))
+	}
 	return objectId
 }
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+)
+
+// Cursor marks where a page of a keyset pagination starts, right after the item holding Value and Id, or right before it going Backward
+type Cursor struct {
+	Value    interface{} `json:"v,omitempty"`
+	Id       string      `json:"id"`
+	Backward bool        `json:"b,omitempty"`
+}
+
+// EncodeCursor makes the cursor opaque for the clients
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return cursor, err
+	}
+	if !primitive.IsValidObjectID(cursor.Id) {
+		return cursor, errors.New("cursor is invalid")
+	}
+	// json reads every number as a float, whole ones go back to integers to compare like the stored field
+	if number, ok := cursor.Value.(float64); ok && number == math.Trunc(number) {
+		cursor.Value = int64(number)
+	}
+	return cursor, nil
+}
@@ -3,12 +3,16 @@ package helper
 import (
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/mongo"
+	"reflect"
+	"strings"
 	"weplant-backend/exception"
+	"weplant-backend/model/web"
 )
 
 func PanicIfError(err error) {
 	if err != nil {
-		panic(err.Error())
+		panic(err)
 	}
 }
 
@@ -18,11 +22,75 @@ func PanicIfErrorNotFound(err error) {
 	}
 }
 
-func IfValidationError(err error) []string {
-	var errors []string
-	for _, e := range err.(validator.ValidationErrors) {
-		errorMessage := fmt.Sprintf("Error on field %s, condition: %s", e.Field(), e.ActualTag())
-		errors = append(errors, errorMessage)
+// WrapDuplicateKeyError turns a mongo unique index violation into a conflict, other errors are returned as is
+func WrapDuplicateKeyError(err error, message string) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return exception.NewDuplicateKeyError(message)
+	}
+	return err
+}
+
+// PanicIfValidationError turns the result of validator.Struct into a ValidationError listing every failed field
+func PanicIfValidationError(err error) {
+	if err == nil {
+		return
+	}
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		panic(err)
+	}
+	panic(exception.NewValidationError("request is invalid", IfValidationError(validationErrors)...))
+}
+
+func IfValidationError(err validator.ValidationErrors) []web.FieldErrorResponse {
+	var errors []web.FieldErrorResponse
+	for _, e := range err {
+		errors = append(errors, web.FieldErrorResponse{
+			Field:   validationField(e),
+			Message: validationMessage(e),
+		})
 	}
 	return errors
 }
+
+// validationField drops the root struct name, e.g. MerchantCreateRequest.address.city becomes address.city
+func validationField(e validator.FieldError) string {
+	namespace := strings.SplitN(e.Namespace(), ".", 2)
+	if len(namespace) < 2 {
+		return e.Field()
+	}
+	return namespace[1]
+}
+
+func validationMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "password":
+		return "must contain at least one letter and one number"
+	case "phone":
+		return "must be a valid indonesian phone number"
+	case "postal_code":
+		return "must be a valid indonesian postal code"
+	case "objectid":
+		return "must be a valid id"
+	case "min":
+		if e.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", e.Param())
+		}
+		return fmt.Sprintf("must be at least %s", e.Param())
+	case "max":
+		if e.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", e.Param())
+		}
+		return fmt.Sprintf("must be at most %s", e.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", e.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", e.Param())
+	default:
+		return fmt.Sprintf("failed on the %s rule", e.Tag())
+	}
+}
@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"weplant-backend/exception"
+	"weplant-backend/model/web"
+)
+
+func ReadFormFile(request *http.Request, field string) (multipart.File, *multipart.FileHeader) {
+	file, fileHeader, err := request.FormFile(field)
+	if err != nil {
+		panic(exception.NewValidationError(field+" is required", web.FieldErrorResponse{
+			Field:   field,
+			Message: err.Error(),
+		}))
+	}
+	return file, fileHeader
+}
+
+func ReadFormInt(request *http.Request, field string) int {
+	value, err := strconv.Atoi(request.PostFormValue(field))
+	if err != nil {
+		panic(exception.NewValidationError(field+" must be a number", web.FieldErrorResponse{
+			Field:   field,
+			Message: "must be a number",
+		}))
+	}
+	return value
+}
+
+// ReadFormIntDefault returns defaultValue when the form field is not set
+func ReadFormIntDefault(request *http.Request, field string, defaultValue int) int {
+	if request.PostFormValue(field) == "" {
+		return defaultValue
+	}
+	return ReadFormInt(request, field)
+}
+
+// ReadQueryInt returns defaultValue when the query parameter is not set
+func ReadQueryInt(request *http.Request, key string, defaultValue int) int {
+	query := request.URL.Query().Get(key)
+	if query == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(query)
+	if err != nil {
+		panic(exception.NewValidationError(key+" must be a number", web.FieldErrorResponse{
+			Field:   key,
+			Message: "must be a number",
+		}))
+	}
+	return value
+}
+
+// ReadQueryCursor returns the cursor query parameter as is, it must be one that was handed out in a response
+func ReadQueryCursor(request *http.Request, key string) string {
+	query := request.URL.Query().Get(key)
+	if query == "" {
+		return ""
+	}
+	_, err := DecodeCursor(query)
+	if err != nil {
+		panic(exception.NewValidationError(key+" is invalid", web.FieldErrorResponse{
+			Field:   key,
+			Message: "must be a cursor from a previous page",
+		}))
+	}
+	return query
+}
@@ -0,0 +1,354 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"weplant-backend/exception"
+	"weplant-backend/model/web"
+)
+
+// ImageLimit bounds the images an endpoint accepts, MaxCount is the most images one request uploads
+type ImageLimit struct {
+	MaxCount  int
+	MaxSize   int64
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+}
+
+var (
+	MerchantImageLimit = ImageLimit{MaxCount: 1, MaxSize: 2 << 20, MinWidth: 100, MinHeight: 100, MaxWidth: 4096, MaxHeight: 4096}
+	CustomerImageLimit = ImageLimit{MaxCount: 1, MaxSize: 2 << 20, MinWidth: 100, MinHeight: 100, MaxWidth: 4096, MaxHeight: 4096}
+	CategoryImageLimit = ImageLimit{MaxCount: 1, MaxSize: 2 << 20, MinWidth: 100, MinHeight: 100, MaxWidth: 4096, MaxHeight: 4096}
+	ProductImageLimit  = ImageLimit{MaxCount: 10, MaxSize: 5 << 20, MinWidth: 300, MinHeight: 300, MaxWidth: 6000, MaxHeight: 6000}
+	ReviewImageLimit   = ImageLimit{MaxCount: 5, MaxSize: 5 << 20, MinWidth: 100, MinHeight: 100, MaxWidth: 6000, MaxHeight: 6000}
+)
+
+// ImageVariants are generated for every upload, Size is the longest side and images are never upscaled
+var ImageVariants = []struct {
+	Name string
+	Size int
+}{
+	{Name: "thumbnail", Size: 150},
+	{Name: "medium", Size: 600},
+	{Name: "large", Size: 1200},
+}
+
+var imageContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// ProcessedImage is an upload decoded, checked and encoded again, which drops its metadata such as EXIF
+type ProcessedImage struct {
+	Content  []byte
+	Width    int
+	Height   int
+	BlurHash string
+	Variants []ProcessedImageVariant
+}
+
+type ProcessedImageVariant struct {
+	Name    string
+	Content []byte
+	Width   int
+	Height  int
+}
+
+// formFieldsSize leaves room in an image form for the fields sent along the images
+const formFieldsSize = 1 << 20
+
+// ParseImageForm parses a multipart form uploading images within limit, a larger body is refused without reading it
+// to the end
+func ParseImageForm(writer http.ResponseWriter, request *http.Request, limit ImageLimit) {
+	maxBodySize := int64(limit.MaxCount)*limit.MaxSize + formFieldsSize
+	request.Body = http.MaxBytesReader(writer, request.Body, maxBodySize)
+	err := request.ParseMultipartForm(32 << 20)
+	if errors.Is(err, http.ErrNotMultipart) {
+		// the images are reported missing when they are read
+		return
+	}
+	// go 1.18 has no MaxBytesError to match on
+	if err != nil && strings.Contains(err.Error(), "request body too large") {
+		panic(exception.NewPayloadTooLargeError(fmt.Sprintf("request must be at most %dKB", maxBodySize>>10)))
+	}
+	PanicIfError(err)
+}
+
+// ReadFormImage reads, checks and processes the image uploaded in field
+func ReadFormImage(request *http.Request, field string, limit ImageLimit) web.ImageCreateRequest {
+	file, fileHeader := ReadFormFile(request, field)
+	defer file.Close()
+	return readImage(field, file, fileHeader, limit)
+}
+
+// ReadFormImages reads, checks and processes every image uploaded in field, it is fine when there is none
+func ReadFormImages(request *http.Request, field string, limit ImageLimit) []web.ImageCreateRequest {
+	if request.MultipartForm == nil {
+		return nil
+	}
+	var images []web.ImageCreateRequest
+	for _, fileHeader := range request.MultipartForm.File[field] {
+		file, err := fileHeader.Open()
+		PanicIfError(err)
+		images = append(images, readImage(field, file, fileHeader, limit))
+		file.Close()
+	}
+	return images
+}
+
+func readImage(field string, file multipart.File, fileHeader *multipart.FileHeader, limit ImageLimit) web.ImageCreateRequest {
+	if fileHeader.Size > limit.MaxSize {
+		panic(imageValidationError(field, fmt.Sprintf("must be at most %dKB", limit.MaxSize>>10)))
+	}
+	data, err := io.ReadAll(io.LimitReader(file, limit.MaxSize+1))
+	PanicIfError(err)
+	if int64(len(data)) > limit.MaxSize {
+		panic(imageValidationError(field, fmt.Sprintf("must be at most %dKB", limit.MaxSize>>10)))
+	}
+
+	processed, err := ProcessImage(data, limit)
+	if err != nil {
+		panic(imageValidationError(field, err.Error()))
+	}
+
+	filename := GetFileName(fileHeader.Filename)
+	var variants []web.ImageVariantRequest
+	for _, variant := range processed.Variants {
+		variants = append(variants, web.ImageVariantRequest{
+			Name:   variant.Name,
+			URL:    bytes.NewReader(variant.Content),
+			Width:  variant.Width,
+			Height: variant.Height,
+		})
+	}
+	return web.ImageCreateRequest{
+		Name:     fileHeader.Filename,
+		FileName: filename,
+		URL:      bytes.NewReader(processed.Content),
+		Width:    processed.Width,
+		Height:   processed.Height,
+		BlurHash: processed.BlurHash,
+		Variants: variants,
+	}
+}
+
+func imageValidationError(field string, message string) exception.ValidationError {
+	return exception.NewValidationError(field+" "+message, web.FieldErrorResponse{
+		Field:   field,
+		Message: message,
+	})
+}
+
+// ProcessImage only accepts JPEG, PNG and WebP within limit, it turns JPEGs upright and strips the metadata
+func ProcessImage(data []byte, limit ImageLimit) (ProcessedImage, error) {
+	contentType := http.DetectContentType(data)
+	if !imageContentTypes[contentType] {
+		return ProcessedImage{}, errors.New("must be a JPEG, PNG or WebP image")
+	}
+
+	// the size is checked before decoding so a huge image is never held in memory
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, errors.New("is not a valid image")
+	}
+	orientation := 1
+	if contentType == "image/jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	width, height := config.Width, config.Height
+	if orientation >= 5 {
+		width, height = height, width
+	}
+	if width < limit.MinWidth || height < limit.MinHeight {
+		return ProcessedImage{}, fmt.Errorf("must be at least %dx%d pixels", limit.MinWidth, limit.MinHeight)
+	}
+	if width > limit.MaxWidth || height > limit.MaxHeight {
+		return ProcessedImage{}, fmt.Errorf("must be at most %dx%d pixels", limit.MaxWidth, limit.MaxHeight)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, errors.New("is not a valid image")
+	}
+	decoded = orient(decoded, orientation)
+
+	content, err := encodeImage(decoded)
+	if err != nil {
+		return ProcessedImage{}, err
+	}
+	processed := ProcessedImage{
+		Content:  content,
+		Width:    decoded.Bounds().Dx(),
+		Height:   decoded.Bounds().Dy(),
+		BlurHash: BlurHash(resizeImage(decoded, 32), 4, 3),
+	}
+	for _, v := range ImageVariants {
+		resized := resizeImage(decoded, v.Size)
+		content, err := encodeImage(resized)
+		if err != nil {
+			return ProcessedImage{}, err
+		}
+		processed.Variants = append(processed.Variants, ProcessedImageVariant{
+			Name:    v.Name,
+			Content: content,
+			Width:   resized.Bounds().Dx(),
+			Height:  resized.Bounds().Dy(),
+		})
+	}
+	return processed, nil
+}
+
+// resizeImage scales img down so its longest side is at most size
+func resizeImage(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+	if width >= height {
+		height = maxInt(1, height*size/width)
+		width = size
+	} else {
+		width = maxInt(1, width*size/height)
+		height = size
+	}
+	resized := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Src, nil)
+	return resized
+}
+
+// encodeImage writes opaque images as JPEG and keeps transparent ones as PNG
+func encodeImage(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+	var err error
+	if opaque(img) {
+		err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buffer, img)
+	}
+	return buffer.Bytes(), err
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// orient turns img upright following its EXIF orientation
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	outWidth, outHeight := width, height
+	if orientation >= 5 {
+		outWidth, outHeight = height, width
+	}
+	oriented := image.NewRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			oriented.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return oriented
+}
+
+// jpegOrientation reads the orientation tag of the EXIF segment, 1 (upright) when there is none
+func jpegOrientation(data []byte) int {
+	i := 2
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		length := int(data[i+2])<<8 | int(data[i+3])
+		if marker == 0xDA || length < 2 || i+2+length > len(data) {
+			break
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	var uint16At func(int) int
+	var uint32At func(int) int
+	switch string(tiff[:2]) {
+	case "II":
+		uint16At = func(i int) int { return int(tiff[i]) | int(tiff[i+1])<<8 }
+		uint32At = func(i int) int { return uint16At(i) | uint16At(i+2)<<16 }
+	case "MM":
+		uint16At = func(i int) int { return int(tiff[i])<<8 | int(tiff[i+1]) }
+		uint32At = func(i int) int { return uint16At(i)<<16 | uint16At(i+2) }
+	default:
+		return 1
+	}
+	ifd := uint32At(4)
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := uint16At(ifd)
+	for e := 0; e < entries; e++ {
+		entry := ifd + 2 + e*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if uint16At(entry) == 0x0112 {
+			orientation := uint16At(entry + 8)
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
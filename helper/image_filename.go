@@ -1,11 +1,11 @@
 package helper
 
 import (
-	"strconv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"strings"
-	"time"
 )
 
+// GetFileName makes the storage key of an upload, the id keeps two uploads of image.jpg apart
 func GetFileName(filename string) string {
 	name := strings.Split(filename, ".")
 	name = name[:len(name)-1]
@@ -13,5 +13,5 @@ func GetFileName(filename string) string {
 	img := strings.Join(name, "-")
 	img = strings.Join(strings.Split(img, " "), "-")
 
-	return strconv.Itoa(int(time.Now().Unix())) + "-" + img
+	return primitive.NewObjectID().Hex() + "-" + img
 }
@@ -3,12 +3,15 @@ package helper
 import (
 	"encoding/json"
 	"net/http"
+	"weplant-backend/exception"
 )
 
 func ReadFromRequestBody(request *http.Request, result interface{}) {
 	decoder := json.NewDecoder(request.Body)
 	err := decoder.Decode(result)
-	PanicIfError(err)
+	if err != nil {
+		panic(exception.NewValidationError("request body is invalid: " + err.Error()))
+	}
 }
 
 func WriteToResponseBody(writer http.ResponseWriter, response interface{}) {
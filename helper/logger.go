@@ -0,0 +1,82 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Logger writes one JSON object per line, fields are kept in the order they were given
+type Logger struct {
+	writer io.Writer
+	mutex  *sync.Mutex
+	fields []interface{}
+}
+
+// DefaultLogger is used wherever no request scoped logger is in the context
+var DefaultLogger = NewLogger(os.Stdout)
+
+func NewLogger(writer io.Writer) *Logger {
+	return &Logger{
+		writer: writer,
+		mutex:  &sync.Mutex{},
+	}
+}
+
+// With returns a logger adding the key value pairs to every line
+func (logger *Logger) With(keyValues ...interface{}) *Logger {
+	fields := append(append([]interface{}{}, logger.fields...), keyValues...)
+	return &Logger{
+		writer: logger.writer,
+		mutex:  logger.mutex,
+		fields: fields,
+	}
+}
+
+func (logger *Logger) Info(message string, keyValues ...interface{}) {
+	logger.write("info", message, keyValues)
+}
+
+func (logger *Logger) Error(message string, keyValues ...interface{}) {
+	logger.write("error", message, keyValues)
+}
+
+func (logger *Logger) write(level string, message string, keyValues []interface{}) {
+	line := []byte(`{"time":`)
+	line = appendJSON(line, time.Now().UTC().Format(time.RFC3339Nano))
+	line = append(line, `,"level":`...)
+	line = appendJSON(line, level)
+	line = append(line, `,"message":`...)
+	line = appendJSON(line, message)
+
+	fields := append(append([]interface{}{}, logger.fields...), keyValues...)
+	for i := 0; i < len(fields); i += 2 {
+		line = append(line, ',')
+		line = appendJSON(line, fmt.Sprint(fields[i]))
+		line = append(line, ':')
+		if i+1 < len(fields) {
+			line = appendJSON(line, fields[i+1])
+		} else {
+			line = append(line, "null"...)
+		}
+	}
+	line = append(line, "}\n"...)
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	logger.writer.Write(line)
+}
+
+func appendJSON(line []byte, value interface{}) []byte {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return append(line, encoded...)
+}
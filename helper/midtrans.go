@@ -1,6 +1,9 @@
 package helper
 
 import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
@@ -31,3 +34,17 @@ func MidtransEnvType(env string) midtrans.EnvironmentType {
 		panic(errors.New("Error Env Type").Error())
 	}
 }
+
+// MidtransSignatureKey is SHA512(order_id+status_code+gross_amount+server_key) as documented by midtrans
+func MidtransSignatureKey(orderId string, statusCode string, grossAmount string, serverKey string) string {
+	hash := sha512.Sum512([]byte(orderId + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(hash[:])
+}
+
+func VerifyMidtransSignature(notification coreapi.TransactionStatusResponse, serverKey string) bool {
+	if notification.SignatureKey == "" || serverKey == "" {
+		return false
+	}
+	expected := MidtransSignatureKey(notification.OrderID, notification.StatusCode, notification.GrossAmount, serverKey)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(notification.SignatureKey)) == 1
+}
@@ -6,6 +6,7 @@ import (
 	"weplant-backend/app"
 	"weplant-backend/controller"
 	"weplant-backend/integration_test/repository_mock"
+	"weplant-backend/integration_test/schema_mock"
 	"weplant-backend/model/web"
 	"weplant-backend/pkg"
 	"weplant-backend/service"
@@ -15,36 +16,70 @@ var MerchantRepository = repository_mock.MerchantRepositoryMock{Mock: mock.Mock{
 var ProductRepository = repository_mock.ProductRepositoryMock{Mock: mock.Mock{}}
 var CategoryRepository = repository_mock.CategoryRepositoryMock{Mock: mock.Mock{}}
 var CustomerRepository = repository_mock.CustomerRepositoryMock{Mock: mock.Mock{}}
-var CloudinaryRepository = repository_mock.CloudinaryRepositoryMock{Mock: mock.Mock{}}
+var ImageRepository = repository_mock.ImageRepositoryMock{Mock: mock.Mock{}}
+var ImageReferenceRepository = repository_mock.ImageReferenceRepositoryMock{Mock: mock.Mock{}}
 var MidtransRepository = repository_mock.MidtransRepositoryMock{Mock: mock.Mock{}}
+var RefreshTokenRepository = repository_mock.RefreshTokenRepositoryMock{Mock: mock.Mock{}}
+var PaymentNotificationRepository = repository_mock.PaymentNotificationRepositoryMock{Mock: mock.Mock{}}
+var ReservationRepository = repository_mock.ReservationRepositoryMock{Mock: mock.Mock{}}
+var SessionRepository = repository_mock.SessionRepositoryMock{Mock: mock.Mock{}}
+var TransactionRepository = repository_mock.TransactionRepositoryMock{Mock: mock.Mock{}}
+var OrderRepository = repository_mock.OrderRepositoryMock{Mock: mock.Mock{}}
+var LedgerRepository = repository_mock.LedgerRepositoryMock{Mock: mock.Mock{}}
+var PayoutRepository = repository_mock.PayoutRepositoryMock{Mock: mock.Mock{}}
+var ShippingRateProvider = repository_mock.ShippingRateProviderMock{Mock: mock.Mock{}}
+var ReviewRepository = repository_mock.ReviewRepositoryMock{Mock: mock.Mock{}}
+var VoucherRepository = repository_mock.VoucherRepositoryMock{Mock: mock.Mock{}}
+var VoucherUsageRepository = repository_mock.VoucherUsageRepositoryMock{Mock: mock.Mock{}}
+
+const MidtransServerKey = "SB-Mid-server-test"
 
 func SetupRouterTest() *httprouter.Router {
 	// service
-	authService := service.NewAuthService(&MerchantRepository, &CustomerRepository)
-	merchantService := service.NewMerchantService(&MerchantRepository, &CloudinaryRepository, &ProductRepository)
-	productService := service.NewProductService(&ProductRepository, &CloudinaryRepository, &CategoryRepository, &MerchantRepository, &CustomerRepository)
-	categoryService := service.NewCategoryService(&CategoryRepository, &ProductRepository)
-	customerService := service.NewCustomerService(&CustomerRepository, &ProductRepository, &CloudinaryRepository)
+	authService := service.NewAuthService(&MerchantRepository, &CustomerRepository, &RefreshTokenRepository)
+	merchantService := service.NewMerchantService(&MerchantRepository, &ImageRepository, &ProductRepository, &RefreshTokenRepository, &OrderRepository, &SessionRepository, &LedgerRepository)
+	productService := service.NewProductService(&ProductRepository, &ImageRepository, &CategoryRepository, &MerchantRepository, &CustomerRepository)
+	categoryService := service.NewCategoryService(&CategoryRepository, &ProductRepository, &ImageRepository)
+	customerService := service.NewCustomerService(&CustomerRepository, &ProductRepository, &ImageRepository, &RefreshTokenRepository, &TransactionRepository, &OrderRepository, &LedgerRepository, &MerchantRepository, &SessionRepository)
 	cartService := service.NewCartService(&CustomerRepository, &ProductRepository)
-	transactionService := service.NewTransactionService(&CustomerRepository, &ProductRepository, &MidtransRepository, &MerchantRepository)
+	transactionService := service.NewTransactionService(&CustomerRepository, &ProductRepository, &MidtransRepository, &MerchantRepository, &PaymentNotificationRepository, &ReservationRepository, &SessionRepository, &TransactionRepository, &OrderRepository, &ShippingRateProvider, &VoucherRepository, &VoucherUsageRepository)
+	ledgerService := service.NewLedgerService(&LedgerRepository, &MerchantRepository, &SessionRepository)
+	payoutService := service.NewPayoutService(&PayoutRepository, &LedgerRepository, &MerchantRepository, &SessionRepository)
+	shippingService := service.NewShippingService(&CustomerRepository, &ProductRepository, &MerchantRepository, &ShippingRateProvider)
+	voucherService := service.NewVoucherService(&VoucherRepository, &VoucherUsageRepository, &MerchantRepository, &CategoryRepository, &CustomerRepository, &ProductRepository)
+	wishlistService := service.NewWishlistService(&CustomerRepository, &ProductRepository, &SessionRepository)
+	reviewService := service.NewReviewService(&ReviewRepository, &OrderRepository, &ProductRepository, &MerchantRepository, &CustomerRepository, &ImageRepository, &SessionRepository)
+
+	// validator
+	validate := pkg.NewValidator()
 
 	// controller
-	authController := controller.NewAuthController(authService)
-	merchantController := controller.NewMerchantController(merchantService)
-	productController := controller.NewProductController(productService)
-	categoryController := controller.NewCategoryController(categoryService)
-	customerController := controller.NewCustomerController(customerService)
-	cartController := controller.NewCartController(cartService)
-	transactionController := controller.NewTransactionController(transactionService)
+	authController := controller.NewAuthController(authService, validate)
+	merchantController := controller.NewMerchantController(merchantService, validate)
+	productController := controller.NewProductController(productService, validate)
+	categoryController := controller.NewCategoryController(categoryService, validate)
+	customerController := controller.NewCustomerController(customerService, validate)
+	cartController := controller.NewCartController(cartService, validate)
+	transactionController := controller.NewTransactionController(transactionService, validate, MidtransServerKey)
+	ledgerController := controller.NewLedgerController(ledgerService)
+	payoutController := controller.NewPayoutController(payoutService, validate)
+	shippingController := controller.NewShippingController(shippingService, validate)
+	reviewController := controller.NewReviewController(reviewService, validate)
+	wishlistController := controller.NewWishlistController(wishlistService, validate)
+	voucherController := controller.NewVoucherController(voucherService, validate)
 
-	router := app.NewRouter(nil, authController, merchantController, productController, categoryController, customerController, cartController, transactionController)
+	router := app.NewRouter(nil, authController, merchantController, productController, categoryController, customerController, cartController, transactionController, ledgerController, payoutController, shippingController, reviewController, wishlistController, voucherController, &ProductRepository)
 
 	return router
 }
 
 func GetJWTTokenTest(role string) string {
+	id := schema_mock.Customer.Id.Hex()
+	if role == "merchant" {
+		id = schema_mock.Merchant.Id.Hex()
+	}
 	return pkg.GenerateToken(web.JWTPayload{
-		Id:   "1",
+		Id:   id,
 		Role: role,
 	})
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/stretchr/testify/mock"
 	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
 )
 
 type CategoryRepositoryMock struct {
@@ -56,10 +57,55 @@ func (repository *CategoryRepositoryMock) FindAll(ctx context.Context) ([]schema
 
 }
 
+func (repository *CategoryRepositoryMock) FindPage(ctx context.Context, page repo.Page) ([]schema.Category, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Category), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *CategoryRepositoryMock) CountDocuments(ctx context.Context) (int, error) {
+	arguments := repository.Mock.Called(ctx)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
 func (repository *CategoryRepositoryMock) Update(ctx context.Context, category schema.Category) (schema.Category, error) {
-	panic("implement me")
+	arguments := repository.Mock.Called(ctx, category)
+
+	if arguments.Get(1) != nil {
+		return schema.Category{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Category{}, errors.New("error")
+	} else {
+		category := arguments.Get(0).(schema.Category)
+		return category, nil
+	}
 }
 
 func (repository *CategoryRepositoryMock) Delete(ctx context.Context, categoryId string) error {
-	panic("implement me")
+	arguments := repository.Mock.Called(ctx, categoryId)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
 }
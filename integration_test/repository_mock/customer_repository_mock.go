@@ -107,9 +107,9 @@ func (repository *CustomerRepositoryMock) UpdateProductQuantity(ctx context.Cont
 	}
 }
 
-func (repository *CustomerRepositoryMock) PullProductFromCart(ctx context.Context, customerId string, productId string) error {
+func (repository *CustomerRepositoryMock) PullProductFromCart(ctx context.Context, customerId string, product schema.CartProduct) error {
 
-	arguments := repository.Mock.Called(ctx, customerId, productId)
+	arguments := repository.Mock.Called(ctx, customerId, product)
 
 	if arguments.Get(0) != nil {
 		return arguments.Get(0).(error)
@@ -131,38 +131,42 @@ func (repository *CustomerRepositoryMock) PullProductFromAllCart(ctx context.Con
 
 }
 
-func (repository *CustomerRepositoryMock) CreateTransaction(ctx context.Context, customerId string, transaction schema.Transaction) error {
-
-	arguments := repository.Mock.Called(ctx, customerId, transaction)
+func (repository *CustomerRepositoryMock) PullVariantFromAllCart(ctx context.Context, productId string, variantId string) error {
+	arguments := repository.Mock.Called(ctx, productId, variantId)
 
 	if arguments.Get(0) != nil {
 		return arguments.Get(0).(error)
 	} else {
 		return nil
 	}
-
 }
 
-func (repository *CustomerRepositoryMock) DeleteTransaction(ctx context.Context, customerId string, transactionId string) error {
-
-	arguments := repository.Mock.Called(ctx, customerId, transactionId)
+func (repository *CustomerRepositoryMock) PushProductToWishlist(ctx context.Context, customerId string, product schema.WishlistProduct) error {
+	arguments := repository.Mock.Called(ctx, customerId, product)
 
 	if arguments.Get(0) != nil {
 		return arguments.Get(0).(error)
 	} else {
 		return nil
 	}
-
 }
 
-func (repository *CustomerRepositoryMock) CreateOrder(ctx context.Context, customerId string, order schema.OrderProduct) error {
-
-	arguments := repository.Mock.Called(ctx, customerId, order)
+func (repository *CustomerRepositoryMock) PullProductFromWishlist(ctx context.Context, customerId string, productId string) error {
+	arguments := repository.Mock.Called(ctx, customerId, productId)
 
 	if arguments.Get(0) != nil {
 		return arguments.Get(0).(error)
 	} else {
 		return nil
 	}
+}
+
+func (repository *CustomerRepositoryMock) PullProductFromAllWishlist(ctx context.Context, productId string) error {
+	arguments := repository.Mock.Called(ctx, productId)
 
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
 }
@@ -0,0 +1,25 @@
+package repository_mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	repo "weplant-backend/repository"
+)
+
+type ImageReferenceRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ImageReferenceRepositoryMock) FindAll(ctx context.Context) ([]repo.ImageReference, error) {
+	arguments := repository.Mock.Called(ctx)
+
+	if arguments.Get(1) != nil {
+		return nil, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	} else {
+		return arguments.Get(0).([]repo.ImageReference), nil
+	}
+}
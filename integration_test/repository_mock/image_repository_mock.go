@@ -0,0 +1,50 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	repo "weplant-backend/repository"
+)
+
+type ImageRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ImageRepositoryMock) UploadImage(ctx context.Context, filename string, image interface{}) (string, error) {
+	arguments := repository.Mock.Called(ctx, filename, image)
+
+	if arguments.Get(1) != nil {
+		return "", arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return "", errors.New("error")
+	} else {
+		return arguments.Get(0).(string), nil
+	}
+}
+
+func (repository *ImageRepositoryMock) DeleteImage(ctx context.Context, filename string) error {
+	arguments := repository.Mock.Called(ctx, filename)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ImageRepositoryMock) ListImages(ctx context.Context) ([]repo.StoredImage, error) {
+	arguments := repository.Mock.Called(ctx)
+
+	if arguments.Get(1) != nil {
+		return nil, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, nil
+	} else {
+		return arguments.Get(0).([]repo.StoredImage), nil
+	}
+}
@@ -0,0 +1,69 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
+)
+
+type LedgerRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *LedgerRepositoryMock) Create(ctx context.Context, entry schema.LedgerEntry) (schema.LedgerEntry, error) {
+	arguments := repository.Mock.Called(ctx, entry)
+
+	if arguments.Get(1) != nil {
+		return schema.LedgerEntry{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.LedgerEntry{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.LedgerEntry), nil
+	}
+}
+
+func (repository *LedgerRepositoryMock) FindByMerchantId(ctx context.Context, merchantId string, page repo.Page) ([]schema.LedgerEntry, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, merchantId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.LedgerEntry), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *LedgerRepositoryMock) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, merchantId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *LedgerRepositoryMock) SumByMerchantId(ctx context.Context, merchantId string) (int64, error) {
+	arguments := repository.Mock.Called(ctx, merchantId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int64), nil
+	}
+}
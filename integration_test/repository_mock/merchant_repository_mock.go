@@ -73,6 +73,36 @@ func (repository *MerchantRepositoryMock) FindBySlug(ctx context.Context, slug s
 
 }
 
+func (repository *MerchantRepositoryMock) FindAll(ctx context.Context) ([]schema.Merchant, error) {
+
+	arguments := repository.Mock.Called(ctx)
+
+	if arguments.Get(1) != nil {
+		return nil, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Merchant), nil
+	}
+}
+
+func (repository *MerchantRepositoryMock) FindByLocation(ctx context.Context, city string, province string) ([]schema.Merchant, error) {
+
+	arguments := repository.Mock.Called(ctx, city, province)
+
+	if arguments.Get(1) != nil {
+		return nil, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return []schema.Merchant{}, nil
+	} else {
+		return arguments.Get(0).([]schema.Merchant), nil
+	}
+}
+
 func (repository *MerchantRepositoryMock) Update(ctx context.Context, merchant schema.Merchant) (schema.Merchant, error) {
 
 	arguments := repository.Mock.Called(ctx, merchant)
@@ -90,9 +120,9 @@ func (repository *MerchantRepositoryMock) Update(ctx context.Context, merchant s
 
 }
 
-func (repository *MerchantRepositoryMock) Delete(ctx context.Context, merchantId string) error {
+func (repository *MerchantRepositoryMock) UpdateBalance(ctx context.Context, merchant schema.Merchant) error {
 
-	arguments := repository.Mock.Called(ctx, merchantId)
+	arguments := repository.Mock.Called(ctx, merchant)
 
 	if arguments.Get(0) != nil {
 		return arguments.Get(0).(error)
@@ -102,14 +132,46 @@ func (repository *MerchantRepositoryMock) Delete(ctx context.Context, merchantId
 
 }
 
-func (repository *MerchantRepositoryMock) PushProductToManageOrders(ctx context.Context, merchantId string, product schema.ManageOrderProduct) error {
+func (repository *MerchantRepositoryMock) WithdrawBalance(ctx context.Context, merchantId string, amount int64) error {
 
-	arguments := repository.Mock.Called(ctx, merchantId, product)
+	arguments := repository.Mock.Called(ctx, merchantId, amount)
 
 	if arguments.Get(0) != nil {
 		return arguments.Get(0).(error)
 	} else {
 		return nil
 	}
+}
+
+func (repository *MerchantRepositoryMock) SetBalance(ctx context.Context, merchantId string, balance int64) error {
+
+	arguments := repository.Mock.Called(ctx, merchantId, balance)
 
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *MerchantRepositoryMock) Delete(ctx context.Context, merchantId string) error {
+
+	arguments := repository.Mock.Called(ctx, merchantId)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+
+}
+
+func (repository *MerchantRepositoryMock) UpdateRating(ctx context.Context, merchantId string, count int, total int) error {
+	arguments := repository.Mock.Called(ctx, merchantId, count, total)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
 }
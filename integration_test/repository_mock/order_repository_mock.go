@@ -0,0 +1,107 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
+)
+
+type OrderRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *OrderRepositoryMock) Create(ctx context.Context, order schema.Order) (schema.Order, error) {
+	arguments := repository.Mock.Called(ctx, order)
+
+	if arguments.Get(1) != nil {
+		return schema.Order{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Order{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Order), nil
+	}
+}
+
+func (repository *OrderRepositoryMock) FindById(ctx context.Context, orderId string) (schema.Order, error) {
+	arguments := repository.Mock.Called(ctx, orderId)
+
+	if arguments.Get(1) != nil {
+		return schema.Order{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Order{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Order), nil
+	}
+}
+
+func (repository *OrderRepositoryMock) FindByCustomerId(ctx context.Context, customerId string, page repo.Page) ([]schema.Order, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, customerId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Order), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *OrderRepositoryMock) CountByCustomerId(ctx context.Context, customerId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, customerId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *OrderRepositoryMock) FindByMerchantId(ctx context.Context, merchantId string, page repo.Page) ([]schema.Order, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, merchantId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Order), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *OrderRepositoryMock) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, merchantId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *OrderRepositoryMock) UpdateStatus(ctx context.Context, orderId string, from string, history schema.OrderHistory, shipping *schema.OrderShipping) error {
+	arguments := repository.Mock.Called(ctx, orderId, from, history, shipping)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+)
+
+type PaymentNotificationRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PaymentNotificationRepositoryMock) Create(ctx context.Context, notification schema.PaymentNotification) (schema.PaymentNotification, error) {
+	arguments := repository.Mock.Called(ctx, notification)
+
+	if arguments.Get(1) != nil {
+		return schema.PaymentNotification{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.PaymentNotification{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.PaymentNotification), nil
+	}
+}
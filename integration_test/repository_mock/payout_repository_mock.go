@@ -0,0 +1,79 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
+)
+
+type PayoutRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *PayoutRepositoryMock) Create(ctx context.Context, payout schema.Payout) (schema.Payout, error) {
+	arguments := repository.Mock.Called(ctx, payout)
+
+	if arguments.Get(1) != nil {
+		return schema.Payout{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Payout{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Payout), nil
+	}
+}
+
+func (repository *PayoutRepositoryMock) FindById(ctx context.Context, payoutId string) (schema.Payout, error) {
+	arguments := repository.Mock.Called(ctx, payoutId)
+
+	if arguments.Get(1) != nil {
+		return schema.Payout{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Payout{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Payout), nil
+	}
+}
+
+func (repository *PayoutRepositoryMock) FindByMerchantId(ctx context.Context, merchantId string, page repo.Page) ([]schema.Payout, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, merchantId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Payout), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *PayoutRepositoryMock) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, merchantId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *PayoutRepositoryMock) UpdateStatus(ctx context.Context, payoutId string, from string, history schema.PayoutHistory) error {
+	arguments := repository.Mock.Called(ctx, payoutId, from, history)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/stretchr/testify/mock"
 	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
 )
 
 type ProductRepositoryMock struct {
@@ -42,33 +43,18 @@ func (repository *ProductRepositoryMock) FindById(ctx context.Context, productId
 	}
 }
 
-func (repository *ProductRepositoryMock) FindAll(ctx context.Context, skip int, limit int) ([]schema.Product, error) {
+func (repository *ProductRepositoryMock) Search(ctx context.Context, search repo.ProductSearch, page repo.Page) (repo.ProductSearchResult, error) {
 
-	arguments := repository.Mock.Called(ctx, skip, limit)
+	arguments := repository.Mock.Called(ctx, search, page)
 
 	if arguments.Get(1) != nil {
-		return nil, arguments.Get(1).(error)
-	}
-
-	if arguments.Get(0) == nil {
-		return []schema.Product{}, nil
-	} else {
-		return arguments.Get(0).([]schema.Product), nil
-	}
-}
-
-func (repository *ProductRepositoryMock) FindAllWithSearch(ctx context.Context, search string, skip int, limit int) ([]schema.Product, error) {
-
-	arguments := repository.Mock.Called(ctx, search, skip, limit)
-
-	if arguments.Get(1) != nil {
-		return nil, arguments.Get(1).(error)
+		return repo.ProductSearchResult{}, arguments.Get(1).(error)
 	}
 
 	if arguments.Get(0) == nil {
-		return []schema.Product{}, nil
+		return repo.ProductSearchResult{}, nil
 	} else {
-		return arguments.Get(0).([]schema.Product), nil
+		return arguments.Get(0).(repo.ProductSearchResult), nil
 	}
 }
 
@@ -88,7 +74,7 @@ func (repository *ProductRepositoryMock) Update(ctx context.Context, product sch
 	}
 }
 
-func (repository ProductRepositoryMock) PushImageIntoImages(ctx context.Context, productId string, images []schema.Image) ([]schema.Image, error) {
+func (repository *ProductRepositoryMock) PushImageIntoImages(ctx context.Context, productId string, images []schema.Image) ([]schema.Image, error) {
 
 	arguments := repository.Mock.Called(ctx, productId, images)
 
@@ -103,7 +89,7 @@ func (repository ProductRepositoryMock) PushImageIntoImages(ctx context.Context,
 	}
 }
 
-func (repository ProductRepositoryMock) PullImageFromImages(ctx context.Context, productId string, imageId string) (schema.Image, error) {
+func (repository *ProductRepositoryMock) PullImageFromImages(ctx context.Context, productId string, imageId string) (schema.Image, error) {
 
 	arguments := repository.Mock.Called(ctx, productId, imageId)
 
@@ -118,7 +104,7 @@ func (repository ProductRepositoryMock) PullImageFromImages(ctx context.Context,
 	}
 }
 
-func (repository ProductRepositoryMock) Delete(ctx context.Context, productId string) error {
+func (repository *ProductRepositoryMock) Delete(ctx context.Context, productId string) error {
 
 	arguments := repository.Mock.Called(ctx, productId)
 
@@ -129,55 +115,80 @@ func (repository ProductRepositoryMock) Delete(ctx context.Context, productId st
 	}
 }
 
-func (repository ProductRepositoryMock) CountDocuments(ctx context.Context) (int, error) {
+func (repository *ProductRepositoryMock) FindByMerchantId(ctx context.Context, merchantId string) ([]schema.Product, error) {
 
-	arguments := repository.Mock.Called(ctx)
+	arguments := repository.Mock.Called(ctx, merchantId)
 
 	if arguments.Get(1) != nil {
-		return 0, arguments.Get(1).(error)
+		return nil, arguments.Get(1).(error)
 	}
 
 	if arguments.Get(0) == nil {
-		return 0, errors.New("error")
+		return []schema.Product{}, nil
 	} else {
-		return arguments.Get(0).(int), nil
+		products := arguments.Get(0).([]schema.Product)
+		return products, nil
 	}
+
 }
 
-func (repository ProductRepositoryMock) FindByMerchantId(ctx context.Context, merchantId string) ([]schema.Product, error) {
+func (repository *ProductRepositoryMock) FindPageByMerchantId(ctx context.Context, merchantId string, page repo.Page) ([]schema.Product, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, merchantId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Product), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
 
+func (repository *ProductRepositoryMock) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
 	arguments := repository.Mock.Called(ctx, merchantId)
 
 	if arguments.Get(1) != nil {
-		return nil, arguments.Get(1).(error)
+		return 0, arguments.Get(1).(error)
 	}
 
 	if arguments.Get(0) == nil {
-		return []schema.Product{}, nil
+		return 0, errors.New("error")
 	} else {
-		products := arguments.Get(0).([]schema.Product)
-		return products, nil
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *ProductRepositoryMock) FindByCategoryIds(ctx context.Context, categoryIds []string, page repo.Page) ([]schema.Product, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, categoryIds, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
 	}
 
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Product), arguments.Get(1).(repo.PageInfo), nil
+	}
 }
 
-func (repository ProductRepositoryMock) FindByCategoryId(ctx context.Context, categoryId string) ([]schema.Product, error) {
-	arguments := repository.Mock.Called(ctx, categoryId)
+func (repository *ProductRepositoryMock) CountByCategoryIds(ctx context.Context, categoryIds []string) (int, error) {
+	arguments := repository.Mock.Called(ctx, categoryIds)
 
 	if arguments.Get(1) != nil {
-		return nil, arguments.Get(1).(error)
+		return 0, arguments.Get(1).(error)
 	}
 
 	if arguments.Get(0) == nil {
-		return []schema.Product{}, nil
+		return 0, errors.New("error")
 	} else {
-		products := arguments.Get(0).([]schema.Product)
-		return products, nil
+		return arguments.Get(0).(int), nil
 	}
-
 }
 
-func (repository ProductRepositoryMock) PullCategoryIdFromProduct(ctx context.Context, categoryId string) error {
+func (repository *ProductRepositoryMock) PullCategoryIdFromProduct(ctx context.Context, categoryId string) error {
 
 	arguments := repository.Mock.Called(ctx, categoryId)
 
@@ -189,7 +200,17 @@ func (repository ProductRepositoryMock) PullCategoryIdFromProduct(ctx context.Co
 
 }
 
-func (repository ProductRepositoryMock) UpdateQuantity(ctx context.Context, product schema.Product) error {
+func (repository *ProductRepositoryMock) UpdateCategoryName(ctx context.Context, categoryId string, name string) error {
+	arguments := repository.Mock.Called(ctx, categoryId, name)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) UpdateQuantity(ctx context.Context, product schema.Product) error {
 
 	arguments := repository.Mock.Called(ctx, product)
 
@@ -199,3 +220,83 @@ func (repository ProductRepositoryMock) UpdateQuantity(ctx context.Context, prod
 		return nil
 	}
 }
+
+func (repository *ProductRepositoryMock) IncrementSold(ctx context.Context, productId string, quantity int) error {
+	arguments := repository.Mock.Called(ctx, productId, quantity)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) ReserveStock(ctx context.Context, productId string, quantity int) error {
+	arguments := repository.Mock.Called(ctx, productId, quantity)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) PushVariant(ctx context.Context, productId string, variant schema.ProductVariant) error {
+	arguments := repository.Mock.Called(ctx, productId, variant)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) UpdateVariant(ctx context.Context, productId string, from schema.ProductVariant, variant schema.ProductVariant) error {
+	arguments := repository.Mock.Called(ctx, productId, from, variant)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) PullVariant(ctx context.Context, productId string, variant schema.ProductVariant) error {
+	arguments := repository.Mock.Called(ctx, productId, variant)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) UpdateVariantQuantity(ctx context.Context, productId string, variantId string, quantity int) error {
+	arguments := repository.Mock.Called(ctx, productId, variantId, quantity)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) ReserveVariantStock(ctx context.Context, productId string, variantId string, quantity int) error {
+	arguments := repository.Mock.Called(ctx, productId, variantId, quantity)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ProductRepositoryMock) UpdateRating(ctx context.Context, productId string, count int, total int) error {
+	arguments := repository.Mock.Called(ctx, productId, count, total)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
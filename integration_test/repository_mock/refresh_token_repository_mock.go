@@ -0,0 +1,60 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+)
+
+type RefreshTokenRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *RefreshTokenRepositoryMock) Create(ctx context.Context, refreshToken schema.RefreshToken) (schema.RefreshToken, error) {
+	arguments := repository.Mock.Called(ctx, refreshToken)
+
+	if arguments.Get(1) != nil {
+		return schema.RefreshToken{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.RefreshToken{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.RefreshToken), nil
+	}
+}
+
+func (repository *RefreshTokenRepositoryMock) FindByTokenHash(ctx context.Context, tokenHash string) (schema.RefreshToken, error) {
+	arguments := repository.Mock.Called(ctx, tokenHash)
+
+	if arguments.Get(1) != nil {
+		return schema.RefreshToken{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.RefreshToken{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.RefreshToken), nil
+	}
+}
+
+func (repository *RefreshTokenRepositoryMock) Revoke(ctx context.Context, refreshTokenId string, replacedBy string) error {
+	arguments := repository.Mock.Called(ctx, refreshTokenId, replacedBy)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *RefreshTokenRepositoryMock) RevokeFamily(ctx context.Context, family string) error {
+	arguments := repository.Mock.Called(ctx, family)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+)
+
+type ReservationRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ReservationRepositoryMock) Create(ctx context.Context, reservation schema.Reservation) (schema.Reservation, error) {
+	arguments := repository.Mock.Called(ctx, reservation)
+
+	if arguments.Get(1) != nil {
+		return schema.Reservation{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Reservation{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Reservation), nil
+	}
+}
+
+func (repository *ReservationRepositoryMock) FindByTransactionId(ctx context.Context, transactionId string) (schema.Reservation, error) {
+	arguments := repository.Mock.Called(ctx, transactionId)
+
+	if arguments.Get(1) != nil {
+		return schema.Reservation{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Reservation{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Reservation), nil
+	}
+}
+
+func (repository *ReservationRepositoryMock) FindExpired(ctx context.Context, now int) ([]schema.Reservation, error) {
+	arguments := repository.Mock.Called(ctx, now)
+
+	if arguments.Get(1) != nil {
+		return nil, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Reservation), nil
+	}
+}
+
+func (repository *ReservationRepositoryMock) UpdateStatus(ctx context.Context, reservationId string, from string, to string) error {
+	arguments := repository.Mock.Called(ctx, reservationId, from, to)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
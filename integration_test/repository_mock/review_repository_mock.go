@@ -0,0 +1,99 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
+)
+
+type ReviewRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *ReviewRepositoryMock) Create(ctx context.Context, review schema.Review) (schema.Review, error) {
+	arguments := repository.Mock.Called(ctx, review)
+
+	if arguments.Get(1) != nil {
+		return schema.Review{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Review{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Review), nil
+	}
+}
+
+func (repository *ReviewRepositoryMock) FindById(ctx context.Context, reviewId string) (schema.Review, error) {
+	arguments := repository.Mock.Called(ctx, reviewId)
+
+	if arguments.Get(1) != nil {
+		return schema.Review{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Review{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Review), nil
+	}
+}
+
+func (repository *ReviewRepositoryMock) FindByProductId(ctx context.Context, productId string, page repo.Page) ([]schema.Review, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, productId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Review), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *ReviewRepositoryMock) CountByProductId(ctx context.Context, productId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, productId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *ReviewRepositoryMock) Update(ctx context.Context, review schema.Review, fromRating int) error {
+	arguments := repository.Mock.Called(ctx, review, fromRating)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ReviewRepositoryMock) UpdateReply(ctx context.Context, reviewId string, reply schema.ReviewReply) error {
+	arguments := repository.Mock.Called(ctx, reviewId, reply)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *ReviewRepositoryMock) Delete(ctx context.Context, review schema.Review) error {
+	arguments := repository.Mock.Called(ctx, review)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,21 @@
+package repository_mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+type SessionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+// WithTransaction runs fn directly, there is no real session behind the mock
+func (repository *SessionRepositoryMock) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	arguments := repository.Mock.Called(ctx)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	}
+
+	return fn(ctx)
+}
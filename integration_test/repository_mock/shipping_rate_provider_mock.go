@@ -0,0 +1,26 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/repository"
+)
+
+type ShippingRateProviderMock struct {
+	Mock mock.Mock
+}
+
+func (provider *ShippingRateProviderMock) Rates(ctx context.Context, request repository.ShippingRateRequest) ([]repository.ShippingRate, error) {
+	arguments := provider.Mock.Called(ctx, request)
+
+	if arguments.Get(1) != nil {
+		return nil, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, errors.New("error")
+	} else {
+		return arguments.Get(0).([]repository.ShippingRate), nil
+	}
+}
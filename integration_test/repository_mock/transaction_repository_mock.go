@@ -0,0 +1,79 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
+)
+
+type TransactionRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *TransactionRepositoryMock) Create(ctx context.Context, transaction schema.Transaction) (schema.Transaction, error) {
+	arguments := repository.Mock.Called(ctx, transaction)
+
+	if arguments.Get(1) != nil {
+		return schema.Transaction{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Transaction{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Transaction), nil
+	}
+}
+
+func (repository *TransactionRepositoryMock) FindById(ctx context.Context, transactionId string) (schema.Transaction, error) {
+	arguments := repository.Mock.Called(ctx, transactionId)
+
+	if arguments.Get(1) != nil {
+		return schema.Transaction{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Transaction{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Transaction), nil
+	}
+}
+
+func (repository *TransactionRepositoryMock) FindByCustomerId(ctx context.Context, customerId string, page repo.Page) ([]schema.Transaction, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, customerId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Transaction), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *TransactionRepositoryMock) CountByCustomerId(ctx context.Context, customerId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, customerId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *TransactionRepositoryMock) Delete(ctx context.Context, transactionId string) error {
+	arguments := repository.Mock.Called(ctx, transactionId)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+	repo "weplant-backend/repository"
+)
+
+type VoucherRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *VoucherRepositoryMock) Create(ctx context.Context, voucher schema.Voucher) (schema.Voucher, error) {
+	arguments := repository.Mock.Called(ctx, voucher)
+
+	if arguments.Get(1) != nil {
+		return schema.Voucher{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Voucher{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Voucher), nil
+	}
+}
+
+func (repository *VoucherRepositoryMock) FindById(ctx context.Context, voucherId string) (schema.Voucher, error) {
+	arguments := repository.Mock.Called(ctx, voucherId)
+
+	if arguments.Get(1) != nil {
+		return schema.Voucher{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Voucher{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Voucher), nil
+	}
+}
+
+func (repository *VoucherRepositoryMock) FindByCode(ctx context.Context, code string) (schema.Voucher, error) {
+	arguments := repository.Mock.Called(ctx, code)
+
+	if arguments.Get(1) != nil {
+		return schema.Voucher{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Voucher{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Voucher), nil
+	}
+}
+
+func (repository *VoucherRepositoryMock) FindByMerchantId(ctx context.Context, merchantId string, page repo.Page) ([]schema.Voucher, repo.PageInfo, error) {
+	arguments := repository.Mock.Called(ctx, merchantId, page)
+
+	if arguments.Get(2) != nil {
+		return nil, repo.PageInfo{}, arguments.Get(2).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return nil, repo.PageInfo{}, errors.New("error")
+	} else {
+		return arguments.Get(0).([]schema.Voucher), arguments.Get(1).(repo.PageInfo), nil
+	}
+}
+
+func (repository *VoucherRepositoryMock) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, merchantId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
+
+func (repository *VoucherRepositoryMock) Update(ctx context.Context, voucher schema.Voucher) (schema.Voucher, error) {
+	arguments := repository.Mock.Called(ctx, voucher)
+
+	if arguments.Get(1) != nil {
+		return schema.Voucher{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.Voucher{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.Voucher), nil
+	}
+}
+
+func (repository *VoucherRepositoryMock) Delete(ctx context.Context, voucherId string) error {
+	arguments := repository.Mock.Called(ctx, voucherId)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
+
+func (repository *VoucherRepositoryMock) IncrementUsage(ctx context.Context, voucherId string) error {
+	arguments := repository.Mock.Called(ctx, voucherId)
+
+	if arguments.Get(0) != nil {
+		return arguments.Get(0).(error)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package repository_mock
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"weplant-backend/model/schema"
+)
+
+type VoucherUsageRepositoryMock struct {
+	Mock mock.Mock
+}
+
+func (repository *VoucherUsageRepositoryMock) Create(ctx context.Context, usage schema.VoucherUsage) (schema.VoucherUsage, error) {
+	arguments := repository.Mock.Called(ctx, usage)
+
+	if arguments.Get(1) != nil {
+		return schema.VoucherUsage{}, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return schema.VoucherUsage{}, errors.New("error")
+	} else {
+		return arguments.Get(0).(schema.VoucherUsage), nil
+	}
+}
+
+func (repository *VoucherUsageRepositoryMock) CountByCustomerId(ctx context.Context, voucherId string, customerId string) (int, error) {
+	arguments := repository.Mock.Called(ctx, voucherId, customerId)
+
+	if arguments.Get(1) != nil {
+		return 0, arguments.Get(1).(error)
+	}
+
+	if arguments.Get(0) == nil {
+		return 0, errors.New("error")
+	} else {
+		return arguments.Get(0).(int), nil
+	}
+}
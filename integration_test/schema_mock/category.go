@@ -13,3 +13,12 @@ var Category = schema.Category{
 	Name:      "sayuran",
 	Slug:      "sayuran",
 }
+
+var SubCategory = schema.Category{
+	Id:        primitive.NewObjectID(),
+	CreatedAt: helper.GetTimeNow(),
+	UpdatedAt: helper.GetTimeNow(),
+	ParentId:  Category.Id.Hex(),
+	Name:      "sayuran daun",
+	Slug:      "sayuran-daun",
+}
@@ -19,12 +19,4 @@ var Customer = schema.Customer{
 		CartProduct,
 		CartProduct,
 	},
-	Transactions: []schema.Transaction{
-		Transaction,
-		Transaction,
-	},
-	Orders: []schema.OrderProduct{
-		OrderProduct,
-		OrderProduct,
-	},
 }
@@ -0,0 +1,16 @@
+package schema_mock
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+var LedgerEntry = schema.LedgerEntry{
+	Id:          primitive.NewObjectID(),
+	CreatedAt:   helper.GetTimeNow(),
+	MerchantId:  Merchant.Id.Hex(),
+	Type:        schema.LedgerTypeSale,
+	Amount:      90000,
+	ReferenceId: Order.Id.Hex(),
+}
@@ -17,9 +17,5 @@ var Merchant = schema.Merchant{
 	Phone:     "081234567890",
 	Balance:   2000000,
 	MainImage: &Image,
-	Orders: []schema.ManageOrderProduct{
-		ManageOrderProduct,
-		ManageOrderProduct,
-	},
-	Address: &Address,
+	Address:   &Address,
 }
@@ -6,12 +6,33 @@ import (
 	"weplant-backend/model/schema"
 )
 
-var OrderProduct = schema.OrderProduct{
-	Id:        primitive.NewObjectID(),
+var Order = schema.Order{
+	Id:            primitive.NewObjectID(),
+	CreatedAt:     helper.GetTimeNow(),
+	UpdatedAt:     helper.GetTimeNow(),
+	TransactionId: Transaction.Id.Hex(),
+	CustomerId:    Customer.Id.Hex(),
+	MerchantId:    Merchant.Id.Hex(),
+	Items: []schema.OrderItem{
+		OrderItem,
+	},
+	Subtotal:    90000,
+	ShippingFee: 10000,
+	Total:       100000,
+	Status:      schema.OrderStatusPaid,
+	Address:     &Address,
+	History: []schema.OrderHistory{
+		OrderHistory,
+	},
+}
+
+var OrderHistory = schema.OrderHistory{
 	CreatedAt: helper.GetTimeNow(),
-	UpdatedAt: helper.GetTimeNow(),
+	Status:    schema.OrderStatusPaid,
+}
+
+var OrderItem = schema.OrderItem{
 	ProductId: primitive.NewObjectID().Hex(),
 	Price:     30000,
 	Quantity:  3,
-	Address:   &Address,
 }
@@ -0,0 +1,15 @@
+package schema_mock
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+var PaymentNotification = schema.PaymentNotification{
+	Id:                primitive.NewObjectID(),
+	CreatedAt:         helper.GetTimeNow(),
+	OrderId:           Transaction.Id.Hex(),
+	TransactionStatus: "settlement",
+	CustomerId:        Customer.Id.Hex(),
+}
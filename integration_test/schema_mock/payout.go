@@ -0,0 +1,25 @@
+package schema_mock
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+var Payout = schema.Payout{
+	Id:            primitive.NewObjectID(),
+	CreatedAt:     helper.GetTimeNow(),
+	UpdatedAt:     helper.GetTimeNow(),
+	MerchantId:    Merchant.Id.Hex(),
+	Amount:        500000,
+	Status:        schema.PayoutStatusRequested,
+	BankName:      "BCA",
+	AccountNumber: "1234567890",
+	AccountName:   "ilham",
+	History: []schema.PayoutHistory{
+		{
+			CreatedAt: helper.GetTimeNow(),
+			Status:    schema.PayoutStatusRequested,
+		},
+	},
+}
@@ -14,12 +14,16 @@ var Product = schema.Product{
 	Id:          primitive.NewObjectID(),
 	CreatedAt:   helper.GetTimeNow(),
 	UpdatedAt:   helper.GetTimeNow(),
-	MerchantId:  primitive.NewObjectID().Hex(),
+	MerchantId:  Merchant.Id.Hex(),
 	Name:        "bunga melati",
 	Slug:        "bunga-melati",
 	Description: "lorem ipsum dolor sit amet",
 	Price:       30000,
 	Stock:       20,
+	Weight:      500,
+	Length:      20,
+	Width:       15,
+	Height:      10,
 	MainImage:   &Image,
 	Images: []schema.Image{
 		Image,
@@ -30,3 +34,16 @@ var Product = schema.Product{
 		ProductCategory,
 	},
 }
+
+var ProductVariant = schema.ProductVariant{
+	Id:  primitive.NewObjectID(),
+	Sku: "MLT-10CM",
+	Options: []schema.ProductVariantOption{
+		{
+			Name:  "pot",
+			Value: "10cm",
+		},
+	},
+	Price: 35000,
+	Stock: 8,
+}
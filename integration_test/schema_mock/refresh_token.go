@@ -6,12 +6,14 @@ import (
 	"weplant-backend/model/schema"
 )
 
-var ManageOrderProduct = schema.ManageOrderProduct{
+var RefreshToken = schema.RefreshToken{
 	Id:        primitive.NewObjectID(),
 	CreatedAt: helper.GetTimeNow(),
 	UpdatedAt: helper.GetTimeNow(),
-	ProductId: primitive.NewObjectID().Hex(),
-	Price:     30000,
-	Quantity:  2,
-	Address:   &Address,
+	ExpiredAt: helper.GetTimeNow() + 3600,
+	UserId:    Customer.Id.Hex(),
+	Role:      "customer",
+	Family:    primitive.NewObjectID().Hex(),
+	TokenHash: "a3f1c2",
+	Revoked:   false,
 }
@@ -0,0 +1,25 @@
+package schema_mock
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+var ReservationProduct = schema.ReservationProduct{
+	ProductId: Product.Id.Hex(),
+	Quantity:  2,
+}
+
+var Reservation = schema.Reservation{
+	Id:            primitive.NewObjectID(),
+	CreatedAt:     helper.GetTimeNow(),
+	UpdatedAt:     helper.GetTimeNow(),
+	ExpiredAt:     helper.GetTimeNow() - 60,
+	TransactionId: Transaction.Id.Hex(),
+	CustomerId:    Customer.Id.Hex(),
+	Status:        schema.ReservationStatusActive,
+	Products: []schema.ReservationProduct{
+		ReservationProduct,
+	},
+}
@@ -0,0 +1,20 @@
+package schema_mock
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+var Review = schema.Review{
+	Id:           primitive.NewObjectID(),
+	CreatedAt:    helper.GetTimeNow(),
+	UpdatedAt:    helper.GetTimeNow(),
+	OrderId:      Order.Id.Hex(),
+	ProductId:    OrderItem.ProductId,
+	MerchantId:   Merchant.Id.Hex(),
+	CustomerId:   Customer.Id.Hex(),
+	CustomerName: Customer.UserName,
+	Rating:       4,
+	Text:         "tanamannya sehat, pengiriman cepat",
+}
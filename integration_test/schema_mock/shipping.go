@@ -0,0 +1,18 @@
+package schema_mock
+
+import "weplant-backend/repository"
+
+var ShippingRates = []repository.ShippingRate{
+	{
+		Courier: "jne",
+		Service: "REG",
+		Fee:     9000,
+		Etd:     "1-2",
+	},
+	{
+		Courier: "jne",
+		Service: "YES",
+		Fee:     18000,
+		Etd:     "1",
+	},
+}
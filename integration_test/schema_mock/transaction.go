@@ -7,21 +7,50 @@ import (
 )
 
 var TransactionProduct = schema.TransactionProduct{
-	ProductId: primitive.NewObjectID().Hex(),
-	Price:     20000,
-	Quantity:  4,
+	ProductId:  primitive.NewObjectID().Hex(),
+	MerchantId: Merchant.Id.Hex(),
+	Price:      20000,
+	Quantity:   4,
+}
+
+// TransactionProductOtherMerchant is sold by another merchant, so the transaction is split into two orders
+var TransactionProductOtherMerchant = schema.TransactionProduct{
+	ProductId:  primitive.NewObjectID().Hex(),
+	MerchantId: primitive.NewObjectID().Hex(),
+	Price:      15000,
+	Quantity:   2,
 }
 
 var Transaction = schema.Transaction{
 	Id:          primitive.NewObjectID(),
 	CreatedAt:   helper.GetTimeNow(),
 	UpdatedAt:   helper.GetTimeNow(),
+	CustomerId:  Customer.Id.Hex(),
 	PaymentType: "gopay",
 	Status:      "pending",
-	QRCode:      "https://gqrodegopaty.com",
+	Payment: &schema.TransactionPayment{
+		Method:    schema.PaymentMethodGopay,
+		Deeplink:  "gojek://gopay/merchanttransfer?tref=1234",
+		QRCode:    "https://gqrodegopaty.com",
+		ExpiredAt: helper.GetTimeNow() + 900,
+	},
 	Products: []schema.TransactionProduct{
 		TransactionProduct,
-		TransactionProduct,
+		TransactionProductOtherMerchant,
+	},
+	Orders: []schema.TransactionOrder{
+		{
+			MerchantId:  TransactionProduct.MerchantId,
+			Subtotal:    80000,
+			ShippingFee: 10000,
+			Total:       90000,
+		},
+		{
+			MerchantId:  TransactionProductOtherMerchant.MerchantId,
+			Subtotal:    30000,
+			ShippingFee: 10000,
+			Total:       40000,
+		},
 	},
 	Address: &Address,
 }
@@ -0,0 +1,31 @@
+package schema_mock
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+var Voucher = schema.Voucher{
+	Id:               primitive.NewObjectID(),
+	CreatedAt:        helper.GetTimeNow(),
+	UpdatedAt:        helper.GetTimeNow(),
+	Code:             "HEMAT10",
+	MerchantId:       Merchant.Id.Hex(),
+	Type:             schema.VoucherTypePercentage,
+	Value:            10,
+	MinSpend:         100000,
+	MaxDiscount:      50000,
+	StartAt:          helper.GetTimeNow() - 3600,
+	EndAt:            helper.GetTimeNow() + 3600,
+	UsageLimit:       100,
+	PerCustomerLimit: 1,
+	UsedCount:        3,
+}
+
+var TransactionVoucher = schema.TransactionVoucher{
+	VoucherId:  Voucher.Id.Hex(),
+	Code:       Voucher.Code,
+	MerchantId: Voucher.MerchantId,
+	Discount:   8000,
+}
@@ -18,6 +18,7 @@ import (
 
 func TestLoginMerchant_Success(t *testing.T) {
 	config.MerchantRepository.Mock.On("FindByEmail", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.RefreshTokenRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.RefreshToken, nil)
 
 	router := config.SetupRouterTest()
 
@@ -73,6 +74,7 @@ func TestLoginMerchant_Failed(t *testing.T) {
 
 func TestLoginCustomer_Success(t *testing.T) {
 	config.CustomerRepository.Mock.On("FindByEmail", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.RefreshTokenRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.RefreshToken, nil)
 
 	router := config.SetupRouterTest()
 
@@ -122,3 +124,140 @@ func TestLoginCustomer_Failed(t *testing.T) {
 
 	assert.Equal(t, 500, response.StatusCode)
 }
+
+// Test Refresh Token
+
+func TestRefreshToken_Success(t *testing.T) {
+	config.RefreshTokenRepository.Mock.On("FindByTokenHash", mock.Anything, mock.Anything).Return(schema_mock.RefreshToken, nil)
+	config.RefreshTokenRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.RefreshToken, nil)
+	config.RefreshTokenRepository.Mock.On("Revoke", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := web.RefreshTokenRequest{
+		RefreshToken: "f0e1d2c3b4a5",
+	}
+	data, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/auth/refresh", bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestRefreshToken_Failed(t *testing.T) {
+	config.RefreshTokenRepository.Mock.On("FindByTokenHash", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+
+	router := config.SetupRouterTest()
+
+	requestBody := web.RefreshTokenRequest{
+		RefreshToken: "f0e1d2c3b4a5",
+	}
+	data, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/auth/refresh", bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+func TestRefreshToken_FailedReused(t *testing.T) {
+	refreshToken := schema_mock.RefreshToken
+	refreshToken.Revoked = true
+	config.RefreshTokenRepository.Mock.On("FindByTokenHash", mock.Anything, mock.Anything).Return(refreshToken, nil)
+	config.RefreshTokenRepository.Mock.On("RevokeFamily", mock.Anything, refreshToken.Family).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := web.RefreshTokenRequest{
+		RefreshToken: "f0e1d2c3b4a5",
+	}
+	data, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/auth/refresh", bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+	config.RefreshTokenRepository.Mock.AssertCalled(t, "RevokeFamily", mock.Anything, refreshToken.Family)
+}
+
+// Test Logout
+
+func TestLogout_Success(t *testing.T) {
+	config.RefreshTokenRepository.Mock.On("FindByTokenHash", mock.Anything, mock.Anything).Return(schema_mock.RefreshToken, nil)
+	config.RefreshTokenRepository.Mock.On("RevokeFamily", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := web.RefreshTokenRequest{
+		RefreshToken: "f0e1d2c3b4a5",
+	}
+	data, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/auth/logout", bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+}
+
+func TestLogout_Failed(t *testing.T) {
+	config.RefreshTokenRepository.Mock.On("FindByTokenHash", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.RefreshTokenRepository.Mock.On("RevokeFamily", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := web.RefreshTokenRequest{
+		RefreshToken: "f0e1d2c3b4a5",
+	}
+	data, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/auth/logout", bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
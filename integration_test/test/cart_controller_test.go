@@ -2,7 +2,6 @@ package test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
@@ -10,18 +9,20 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"weplant-backend/integration_test/config"
 	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
 )
 
 // Test PushProductToCart Cart
 
 func TestPushProductToCartCart_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PushProductToCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PushProductToCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -35,7 +36,7 @@ func TestPushProductToCartCart_Success(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/121", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -49,9 +50,9 @@ func TestPushProductToCartCart_Success(t *testing.T) {
 }
 
 func TestPushProductToCartCart_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PushProductToCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PushProductToCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -65,7 +66,7 @@ func TestPushProductToCartCart_Failed(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/121", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -78,10 +79,59 @@ func TestPushProductToCartCart_Failed(t *testing.T) {
 	assert.Equal(t, 404, response.StatusCode)
 }
 
+func TestPushProductToCartCartVariantRequired_Failed(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"product_id":"`+schema_mock.Product.Id.Hex()+`"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.CustomerRepository.Mock.AssertNotCalled(t, "PushProductToCart", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPushProductToCartCartVariant_Success(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.CustomerRepository.Mock.On("PushProductToCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"product_id":"`+schema_mock.Product.Id.Hex()+`","variant_id":"`+schema_mock.ProductVariant.Id.Hex()+`"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.CustomerRepository.Mock.AssertCalled(t, "PushProductToCart", mock.Anything, schema_mock.Customer.Id.Hex(), schema.CartProduct{
+		ProductId: schema_mock.Product.Id.Hex(),
+		VariantId: schema_mock.ProductVariant.Id.Hex(),
+		Quantity:  1,
+	})
+}
+
 func TestPushProductToCartCart_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PushProductToCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PushProductToCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -95,7 +145,7 @@ func TestPushProductToCartCart_FailedUnauthorized(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/121", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -110,9 +160,9 @@ func TestPushProductToCartCart_FailedUnauthorized(t *testing.T) {
 // Test UpdateProductQuantity Cart
 
 func TestUpdateProductQuantityCart_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("UpdateProductQuantity", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("UpdateProductQuantity", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -126,7 +176,7 @@ func TestUpdateProductQuantityCart_Success(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/carts/121/products/12", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex()+"/products/"+schema_mock.Product.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -140,9 +190,9 @@ func TestUpdateProductQuantityCart_Success(t *testing.T) {
 }
 
 func TestUpdateProductQuantityCart_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("UpdateProductQuantity", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("UpdateProductQuantity", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -156,7 +206,7 @@ func TestUpdateProductQuantityCart_Failed(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/carts/121/products/12", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex()+"/products/"+schema_mock.Product.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -170,9 +220,9 @@ func TestUpdateProductQuantityCart_Failed(t *testing.T) {
 }
 
 func TestUpdateProductQuantityCart_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("UpdateProductQuantity", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("UpdateProductQuantity", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -186,7 +236,7 @@ func TestUpdateProductQuantityCart_FailedUnauthorized(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/carts/121/products/12", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex()+"/products/"+schema_mock.Product.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -201,13 +251,13 @@ func TestUpdateProductQuantityCart_FailedUnauthorized(t *testing.T) {
 // Test PullProductFromCart Cart
 
 func TestPullProductFromCartCart_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PullProductFromCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/carts/121/products/12", nil)
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex()+"/products/"+schema_mock.Product.Id.Hex(), nil)
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -221,13 +271,13 @@ func TestPullProductFromCartCart_Success(t *testing.T) {
 }
 
 func TestPullProductFromCartCart_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PullProductFromCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/carts/121/products/12", nil)
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex()+"/products/"+schema_mock.Product.Id.Hex(), nil)
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -241,13 +291,13 @@ func TestPullProductFromCartCart_Failed(t *testing.T) {
 }
 
 func TestPullProductFromCartCart_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PullProductFromCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/carts/121/products/12", nil)
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/carts/"+schema_mock.Customer.Id.Hex()+"/products/"+schema_mock.Product.Id.Hex(), nil)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -258,3 +308,23 @@ func TestPullProductFromCartCart_FailedUnauthorized(t *testing.T) {
 
 	assert.Equal(t, 401, response.StatusCode)
 }
+
+func TestPullProductFromCartCart_FailedForbidden(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/carts/"+primitive.NewObjectID().Hex()+"/products/"+schema_mock.Product.Id.Hex(), nil)
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 403, response.StatusCode)
+}
@@ -2,45 +2,58 @@ package test_test
 
 import (
 	"bytes"
-	"context"
+	_ "embed"
 	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"weplant-backend/integration_test/config"
 	"weplant-backend/integration_test/schema_mock"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
+	"weplant-backend/repository"
 )
 
+//go:embed "elonmusk.jpg"
+var categoryImageTest []byte
+
 // Test FindById Category
 
 func TestFindByIdCategory_Success(t *testing.T) {
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
-	config.ProductRepository.Mock.On("FindByCategoryId", context.Background(), mock.Anything).Return([]schema.Product{
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{
+		schema_mock.Category,
+		schema_mock.SubCategory,
+	}, nil)
+	config.ProductRepository.Mock.On("FindByCategoryIds", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Product{
 		schema_mock.Product,
 		schema_mock.Product,
-	}, nil)
+	}, repository.PageInfo{}, nil)
+	config.ProductRepository.Mock.On("CountByCategoryIds", mock.Anything, mock.Anything).Return(2, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/categories/1", nil)
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/categories/1?perPage=2", nil)
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "FindByCategoryIds", mock.Anything, []string{schema_mock.Category.Id.Hex(), schema_mock.SubCategory.Id.Hex()}, repository.Page{Limit: 2})
 
 	//bytes, _ := io.ReadAll(response.Body)
 	//fmt.Println(string(bytes))
 }
 
 func TestFindByIdCategory_Failed(t *testing.T) {
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
 
 	router := config.SetupRouterTest()
 
@@ -58,26 +71,37 @@ func TestFindByIdCategory_Failed(t *testing.T) {
 // Test FindAll Category
 
 func TestFindAllCategory_Success(t *testing.T) {
-	config.CategoryRepository.Mock.On("FindAll", context.Background()).Return([]schema.Category{
+	config.CategoryRepository.Mock.On("FindPage", mock.Anything, mock.Anything).Return([]schema.Category{
 		schema_mock.Category,
 		schema_mock.Category,
-	}, nil)
+	}, repository.PageInfo{NextCursor: "next-cursor"}, nil)
+	config.CategoryRepository.Mock.On("CountDocuments", mock.Anything).Return(3, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/categories", nil)
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/categories?perPage=2", nil)
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.CategoryRepository.Mock.AssertCalled(t, "FindPage", mock.Anything, repository.Page{Limit: 2})
+
+	var webResponse struct {
+		Data web.CategoryFindAllResponse `json:"data"`
+	}
+	err := json.NewDecoder(response.Body).Decode(&webResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(webResponse.Data.Categories))
+	assert.Equal(t, 3, webResponse.Data.Metadata.TotalData)
+	assert.Equal(t, "next-cursor", webResponse.Data.Metadata.NextCursor)
 	//bytes, _ := io.ReadAll(response.Body)
 	//fmt.Println(string(bytes))
 }
 
 func TestFindAllCategory_Failed(t *testing.T) {
-	config.CategoryRepository.Mock.On("FindAll", context.Background()).Return(nil, errors.New("error"))
+	config.CategoryRepository.Mock.On("FindPage", mock.Anything, mock.Anything).Return(nil, repository.PageInfo{}, errors.New("error"))
 
 	router := config.SetupRouterTest()
 
@@ -95,7 +119,7 @@ func TestFindAllCategory_Failed(t *testing.T) {
 // Test Create Category
 
 func TestCreateCategory_Success(t *testing.T) {
-	config.CategoryRepository.Mock.On("Create", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
+	config.CategoryRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
 
 	router := config.SetupRouterTest()
 
@@ -123,7 +147,7 @@ func TestCreateCategory_Success(t *testing.T) {
 }
 
 func TestCreateCategory_Failed(t *testing.T) {
-	config.CategoryRepository.Mock.On("Create", context.Background(), mock.Anything).Return(schema_mock.Category, errors.New("error"))
+	config.CategoryRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Category, errors.New("error"))
 
 	router := config.SetupRouterTest()
 
@@ -151,7 +175,7 @@ func TestCreateCategory_Failed(t *testing.T) {
 }
 
 func TestCreateCategory_FailedUnauthorized(t *testing.T) {
-	config.CategoryRepository.Mock.On("Create", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
+	config.CategoryRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
 
 	router := config.SetupRouterTest()
 
@@ -176,3 +200,180 @@ func TestCreateCategory_FailedUnauthorized(t *testing.T) {
 	//bytes, _ := io.ReadAll(response.Body)
 	//fmt.Println(string(bytes))
 }
+
+const categoryAdminKeyTest = "admin-key-test"
+
+// Test FindTree Category
+
+func TestFindTreeCategory_Success(t *testing.T) {
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{
+		schema_mock.Category,
+		schema_mock.SubCategory,
+	}, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/category-tree", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+
+	body, _ := io.ReadAll(response.Body)
+	var webResponse struct {
+		Data []web.CategoryTreeResponse `json:"data"`
+	}
+	json.Unmarshal(body, &webResponse)
+	if !assert.Equal(t, 1, len(webResponse.Data)) || !assert.Equal(t, 1, len(webResponse.Data[0].Children)) {
+		return
+	}
+	assert.Equal(t, schema_mock.SubCategory.Id.Hex(), webResponse.Data[0].Children[0].Id)
+}
+
+// Test Update Category
+
+func TestUpdateCategoryParent_Success(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", categoryAdminKeyTest)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{
+		schema_mock.Category,
+		schema_mock.SubCategory,
+	}, nil)
+	config.CategoryRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.SubCategory, nil)
+	config.ProductRepository.Mock.On("UpdateCategoryName", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/categories/"+schema_mock.SubCategory.Id.Hex(), strings.NewReader(`{"name":"sayuran daun","parent_id":"`+schema_mock.Category.Id.Hex()+`"}`))
+	request.Header.Add("X-Admin-Key", categoryAdminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.CategoryRepository.Mock.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(category schema.Category) bool {
+		return category.ParentId == schema_mock.Category.Id.Hex() && category.Slug == "sayuran-daun"
+	}))
+	config.ProductRepository.Mock.AssertCalled(t, "UpdateCategoryName", mock.Anything, schema_mock.SubCategory.Id.Hex(), "sayuran daun")
+}
+
+func TestUpdateCategoryCyclic_Failed(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", categoryAdminKeyTest)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{
+		schema_mock.Category,
+		schema_mock.SubCategory,
+	}, nil)
+	config.CategoryRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/categories/"+schema_mock.Category.Id.Hex(), strings.NewReader(`{"name":"sayuran","parent_id":"`+schema_mock.SubCategory.Id.Hex()+`"}`))
+	request.Header.Add("X-Admin-Key", categoryAdminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.CategoryRepository.Mock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateCategoryParent_FailedUnauthorized(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", categoryAdminKeyTest)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/categories/"+schema_mock.Category.Id.Hex(), strings.NewReader(`{"name":"sayuran"}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+// Test Update Image Category
+
+func TestUpdateCategoryImage_Success(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", categoryAdminKeyTest)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.CategoryRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("https://image.com/sayuran.jpg", nil)
+
+	router := config.SetupRouterTest()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("image", "sayuran.jpg")
+	part.Write(categoryImageTest)
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/categories/"+schema_mock.Category.Id.Hex()+"/image", body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Add("X-Admin-Key", categoryAdminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.CategoryRepository.Mock.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(category schema.Category) bool {
+		return category.MainImage != nil && category.MainImage.URL == "https://image.com/sayuran.jpg" && len(category.MainImage.Variants) == 3 && category.MainImage.BlurHash != ""
+	}))
+	config.ImageRepository.Mock.AssertCalled(t, "UploadImage", mock.Anything, mock.MatchedBy(func(filename string) bool {
+		return strings.HasSuffix(filename, "-sayuran-thumbnail")
+	}), mock.Anything)
+}
+
+// Test Delete Category
+
+func TestDeleteCategory_Success(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", categoryAdminKeyTest)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.SubCategory, nil)
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{
+		schema_mock.Category,
+		schema_mock.SubCategory,
+	}, nil)
+	config.ProductRepository.Mock.On("PullCategoryIdFromProduct", mock.Anything, mock.Anything).Return(nil)
+	config.CategoryRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/categories/"+schema_mock.SubCategory.Id.Hex(), nil)
+	request.Header.Add("X-Admin-Key", categoryAdminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "PullCategoryIdFromProduct", mock.Anything, schema_mock.SubCategory.Id.Hex())
+	config.CategoryRepository.Mock.AssertCalled(t, "Delete", mock.Anything, schema_mock.SubCategory.Id.Hex())
+}
+
+func TestDeleteCategoryHasChildren_Failed(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", categoryAdminKeyTest)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{
+		schema_mock.Category,
+		schema_mock.SubCategory,
+	}, nil)
+	config.CategoryRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/categories/"+schema_mock.Category.Id.Hex(), nil)
+	request.Header.Add("X-Admin-Key", categoryAdminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+	config.CategoryRepository.Mock.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
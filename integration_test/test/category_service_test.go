@@ -0,0 +1,25 @@
+package test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+	"weplant-backend/exception"
+	"weplant-backend/repository"
+	"weplant-backend/service"
+)
+
+// Test Find All Category
+
+func TestFindAllCategoryMadeUpCursor_Failed(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("made up cursor", func(mt *mtest.T) {
+		categoryService := service.NewCategoryService(repository.NewCategoryRepository(mt.Coll), nil, nil)
+
+		_, err := categoryService.FindAll(mtest.Background, "not-a-cursor", 10)
+		assert.ErrorAs(t, err, &exception.ValidationError{})
+		assert.Equal(t, 0, len(mt.GetAllStartedEvents()))
+	})
+}
@@ -2,7 +2,6 @@ package test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/assert"
@@ -11,17 +10,22 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"weplant-backend/helper"
 	"weplant-backend/integration_test/config"
 	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
+	"weplant-backend/repository"
+	"weplant-backend/service"
 )
 
 // Test Create Customer
 
 func TestCreateCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("Create", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.RefreshTokenRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.RefreshToken, nil)
 
 	router := config.SetupRouterTest()
 
@@ -29,7 +33,7 @@ func TestCreateCustomer_Success(t *testing.T) {
 		CreatedAt: helper.GetTimeNow(),
 		UpdatedAt: helper.GetTimeNow(),
 		Email:     "ilham@gmail.com",
-		Password:  "12345",
+		Password:  "rahasia123",
 		UserName:  "ilham8725",
 		Phone:     "081234567890",
 	}
@@ -54,7 +58,7 @@ func TestCreateCustomer_Success(t *testing.T) {
 }
 
 func TestCreateCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("Create", context.Background(), mock.Anything).Return(nil, errors.New("error"))
+	config.CustomerRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
 
 	router := config.SetupRouterTest()
 
@@ -62,7 +66,7 @@ func TestCreateCustomer_Failed(t *testing.T) {
 		CreatedAt: helper.GetTimeNow(),
 		UpdatedAt: helper.GetTimeNow(),
 		Email:     "ilham@gmail.com",
-		Password:  "12345",
+		Password:  "rahasia123",
 		UserName:  "ilham8725",
 		Phone:     "081234567890",
 	}
@@ -83,14 +87,53 @@ func TestCreateCustomer_Failed(t *testing.T) {
 	assert.Equal(t, 500, response.StatusCode)
 }
 
+func TestCreateCustomerValidation_Failed(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	requestBody := web.CustomerCreateRequest{
+		Email:    "ilham",
+		Password: "12345",
+		UserName: "ilham8725",
+		Phone:    "12345",
+	}
+	data, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers", bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	var webResponse web.WebResponse
+	err = json.NewDecoder(response.Body).Decode(&webResponse)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var fields []string
+	for _, e := range webResponse.Errors {
+		fields = append(fields, e.Field)
+	}
+
+	assert.Equal(t, 400, response.StatusCode)
+	assert.Equal(t, "VALIDATION_ERROR", webResponse.ErrorCode)
+	assert.ElementsMatch(t, []string{"email", "password", "phone"}, fields)
+}
+
 // Test FindById Customer
 
 func TestFindByIdCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex(), nil)
 
 	recorder := httptest.NewRecorder()
 
@@ -102,11 +145,11 @@ func TestFindByIdCustomer_Success(t *testing.T) {
 }
 
 func TestFindByIdCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex(), nil)
 
 	recorder := httptest.NewRecorder()
 
@@ -120,12 +163,12 @@ func TestFindByIdCustomer_Failed(t *testing.T) {
 // Test FindCartById Customer
 
 func TestFindCartByIdCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/carts", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/carts", nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -138,12 +181,12 @@ func TestFindCartByIdCustomer_Success(t *testing.T) {
 }
 
 func TestFindCartByIdCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/carts", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/carts", nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -157,12 +200,12 @@ func TestFindCartByIdCustomer_Failed(t *testing.T) {
 }
 
 func TestFindCartByIdCustomer_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/carts", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/carts", nil)
 
 	recorder := httptest.NewRecorder()
 
@@ -176,12 +219,16 @@ func TestFindCartByIdCustomer_FailedUnauthorized(t *testing.T) {
 // Test FindTransactionById Customer
 
 func TestFindTransactionByIdCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.TransactionRepository.Mock.On("FindByCustomerId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Transaction{schema_mock.Transaction}, repository.PageInfo{NextCursor: "next-cursor"}, nil)
+	config.TransactionRepository.Mock.On("CountByCustomerId", mock.Anything, mock.Anything).Return(6, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/transactions", nil)
+	cursor := helper.EncodeCursor(helper.Cursor{Value: schema_mock.Transaction.CreatedAt, Id: schema_mock.Transaction.Id.Hex()})
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/transactions?cursor="+cursor+"&perPage=5", nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -191,15 +238,25 @@ func TestFindTransactionByIdCustomer_Success(t *testing.T) {
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.TransactionRepository.Mock.AssertCalled(t, "FindByCustomerId", mock.Anything, schema_mock.Customer.Id.Hex(), repository.Page{Cursor: cursor, Limit: 5})
+
+	var webResponse struct {
+		Data web.TransactionResponse `json:"data"`
+	}
+	err := json.NewDecoder(response.Body).Decode(&webResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(webResponse.Data.Transactions))
+	assert.Equal(t, 6, webResponse.Data.Metadata.TotalData)
+	assert.Equal(t, "next-cursor", webResponse.Data.Metadata.NextCursor)
 }
 
 func TestFindTransactionByIdCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/transactions", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/transactions", nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -212,12 +269,12 @@ func TestFindTransactionByIdCustomer_Failed(t *testing.T) {
 }
 
 func TestFindTransactionByIdCustomer_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/transactions", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/transactions", nil)
 
 	recorder := httptest.NewRecorder()
 
@@ -228,15 +285,35 @@ func TestFindTransactionByIdCustomer_FailedUnauthorized(t *testing.T) {
 	assert.Equal(t, 401, response.StatusCode)
 }
 
+func TestFindTransactionByIdCustomer_FailedForbidden(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+primitive.NewObjectID().Hex()+"/transactions", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 403, response.StatusCode)
+}
+
 // Test FindOrderById Customer
 
 func TestFindOrderByIdCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.OrderRepository.Mock.On("FindByCustomerId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Order{schema_mock.Order, schema_mock.Order}, repository.PageInfo{}, nil)
+	config.OrderRepository.Mock.On("CountByCustomerId", mock.Anything, mock.Anything).Return(2, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/orders", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders", nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -246,15 +323,31 @@ func TestFindOrderByIdCustomer_Success(t *testing.T) {
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertCalled(t, "FindByCustomerId", mock.Anything, schema_mock.Customer.Id.Hex(), repository.Page{Limit: 10})
+
+	var webResponse struct {
+		Data web.OrderResponse `json:"data"`
+	}
+	err := json.NewDecoder(response.Body).Decode(&webResponse)
+	assert.Nil(t, err)
+	if !assert.Equal(t, 2, len(webResponse.Data.Orders)) {
+		return
+	}
+	assert.Equal(t, schema.OrderStatusPaid, webResponse.Data.Orders[0].Status)
+	assert.Equal(t, schema_mock.Order.Total, webResponse.Data.Orders[0].Total)
+	assert.Equal(t, 2, webResponse.Data.Metadata.TotalData)
+	if assert.Equal(t, 1, len(webResponse.Data.Orders[0].Items)) {
+		assert.Equal(t, schema_mock.Product.Name, webResponse.Data.Orders[0].Items[0].Name)
+	}
 }
 
 func TestFindOrderByIdCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/orders", nil)
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders", nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -267,12 +360,142 @@ func TestFindOrderByIdCustomer_Failed(t *testing.T) {
 }
 
 func TestFindOrderByIdCustomer_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders", nil)
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+// Test ConfirmOrder Customer
+
+func TestConfirmOrderCustomer_Success(t *testing.T) {
+	order := schema_mock.Order
+	order.Status = schema.OrderStatusShipped
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.LedgerRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.LedgerEntry, nil)
+	config.MerchantRepository.Mock.On("UpdateBalance", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders/"+order.Id.Hex()+"/confirm", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, order.Id.Hex(), schema.OrderStatusShipped, mock.MatchedBy(func(history schema.OrderHistory) bool {
+		return history.Status == schema.OrderStatusCompleted
+	}), (*schema.OrderShipping)(nil))
+
+	total := int64(order.Total)
+	commission := int64(order.Subtotal) * service.CommissionPercent / 100
+	config.LedgerRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(entry schema.LedgerEntry) bool {
+		return entry.Type == schema.LedgerTypeSale && entry.Amount == total && entry.MerchantId == order.MerchantId
+	}))
+	config.LedgerRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(entry schema.LedgerEntry) bool {
+		return entry.Type == schema.LedgerTypeCommission && entry.Amount == -commission
+	}))
+	config.MerchantRepository.Mock.AssertCalled(t, "UpdateBalance", mock.Anything, schema.Merchant{
+		Id:      schema_mock.Merchant.Id,
+		Balance: total - commission,
+	})
+}
+
+func TestConfirmOrderCustomer_Failed(t *testing.T) {
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders/"+schema_mock.Order.Id.Hex()+"/confirm", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+	config.OrderRepository.Mock.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmOrderCustomerConcurrent_Failed(t *testing.T) {
+	order := schema_mock.Order
+	order.Status = schema.OrderStatusDelivered
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrOrderStatusChanged)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/34/orders", nil)
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders/"+order.Id.Hex()+"/confirm", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+}
+
+// Test RequestRefund Customer
+
+func TestRequestRefundCustomer_Success(t *testing.T) {
+	order := schema_mock.Order
+	order.Status = schema.OrderStatusDelivered
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.OrderRefundRequest{
+		Note: "tanamannya layu saat sampai",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders/"+order.Id.Hex()+"/refund", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, order.Id.Hex(), schema.OrderStatusDelivered, mock.MatchedBy(func(history schema.OrderHistory) bool {
+		return history.Status == schema.OrderStatusRefundRequested && history.Note == "tanamannya layu saat sampai"
+	}), (*schema.OrderShipping)(nil))
+}
+
+func TestRequestRefundCustomer_FailedUnauthorized(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/orders/"+schema_mock.Order.Id.Hex()+"/refund", strings.NewReader(`{"note":"rusak"}`))
 
 	recorder := httptest.NewRecorder()
 
@@ -286,8 +509,8 @@ func TestFindOrderByIdCustomer_FailedUnauthorized(t *testing.T) {
 // Test Update Customer
 
 func TestUpdateCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CustomerRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
 
 	router := config.SetupRouterTest()
 
@@ -295,14 +518,14 @@ func TestUpdateCustomer_Success(t *testing.T) {
 		Id:        primitive.NewObjectID().Hex(),
 		UpdatedAt: helper.GetTimeNow(),
 		UserName:  "yanuarnauval",
-		Phone:     "098765432123",
+		Phone:     "081298765432",
 	}
 	body, err := json.Marshal(requestBody)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/34", bytes.NewReader(body))
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(body))
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -315,8 +538,8 @@ func TestUpdateCustomer_Success(t *testing.T) {
 }
 
 func TestUpdateCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CustomerRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.CustomerRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
 
 	router := config.SetupRouterTest()
 
@@ -324,14 +547,14 @@ func TestUpdateCustomer_Failed(t *testing.T) {
 		Id:        primitive.NewObjectID().Hex(),
 		UpdatedAt: helper.GetTimeNow(),
 		UserName:  "yanuarnauval",
-		Phone:     "098765432123",
+		Phone:     "081298765432",
 	}
 	body, err := json.Marshal(requestBody)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/34", bytes.NewReader(body))
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(body))
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
 	recorder := httptest.NewRecorder()
@@ -344,8 +567,8 @@ func TestUpdateCustomer_Failed(t *testing.T) {
 }
 
 func TestUpdateCustomer_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CustomerRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
 
 	router := config.SetupRouterTest()
 
@@ -353,14 +576,14 @@ func TestUpdateCustomer_FailedUnauthorized(t *testing.T) {
 		Id:        primitive.NewObjectID().Hex(),
 		UpdatedAt: helper.GetTimeNow(),
 		UserName:  "yanuarnauval",
-		Phone:     "098765432123",
+		Phone:     "081298765432",
 	}
 	body, err := json.Marshal(requestBody)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/34", bytes.NewReader(body))
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(body))
 
 	recorder := httptest.NewRecorder()
 
@@ -374,10 +597,10 @@ func TestUpdateCustomer_FailedUnauthorized(t *testing.T) {
 // Test UpdateMainImage Customer
 
 func TestUpdateMainImageCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CustomerRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.CustomerRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -390,7 +613,7 @@ func TestUpdateMainImageCustomer_Success(t *testing.T) {
 	file.Write(uploadImageTest)
 	writer.Close()
 
-	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/3/image", body)
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/image", body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 	recorder := httptest.NewRecorder()
@@ -403,10 +626,10 @@ func TestUpdateMainImageCustomer_Success(t *testing.T) {
 }
 
 func TestUpdateMainImageCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CustomerRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.CustomerRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -419,7 +642,7 @@ func TestUpdateMainImageCustomer_Failed(t *testing.T) {
 	file.Write(uploadImageTest)
 	writer.Close()
 
-	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/3/image", body)
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/image", body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 	recorder := httptest.NewRecorder()
@@ -432,10 +655,10 @@ func TestUpdateMainImageCustomer_Failed(t *testing.T) {
 }
 
 func TestUpdateMainImageCustomer_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CustomerRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.CustomerRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -448,7 +671,7 @@ func TestUpdateMainImageCustomer_FailedUnauthorized(t *testing.T) {
 	file.Write(uploadImageTest)
 	writer.Close()
 
-	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/3/image", body)
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/image", body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	recorder := httptest.NewRecorder()
 
@@ -459,15 +682,77 @@ func TestUpdateMainImageCustomer_FailedUnauthorized(t *testing.T) {
 	assert.Equal(t, 401, response.StatusCode)
 }
 
+func TestUpdateMainImageCustomerType_Failed(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+
+	router := config.SetupRouterTest()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	file, err := writer.CreateFormFile("image", "elonmusk.jpg")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	file.Write([]byte("<html><script>alert(1)</script></html>"))
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/image", body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.ImageRepository.Mock.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything)
+
+	var webResponse web.WebResponse
+	err = json.NewDecoder(response.Body).Decode(&webResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, "image must be a JPEG, PNG or WebP image", webResponse.Data)
+}
+
+func TestUpdateMainImageCustomerSize_Failed(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+
+	router := config.SetupRouterTest()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	file, err := writer.CreateFormFile("image", "elonmusk.jpg")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	file.Write(uploadImageTest)
+	file.Write(make([]byte, helper.CustomerImageLimit.MaxSize))
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/image", body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.ImageRepository.Mock.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test Delete Customer
 
 func TestDeleteCustomer_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CustomerRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/customers/10", nil)
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex(), nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 	recorder := httptest.NewRecorder()
 
@@ -478,12 +763,12 @@ func TestDeleteCustomer_Success(t *testing.T) {
 }
 
 func TestDeleteCustomer_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CustomerRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.CustomerRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/customers/10", nil)
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex(), nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 	recorder := httptest.NewRecorder()
 
@@ -494,12 +779,12 @@ func TestDeleteCustomer_Failed(t *testing.T) {
 }
 
 func TestDeleteCustomer_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.CustomerRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/customers/10", nil)
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/customers/"+schema_mock.Customer.Id.Hex(), nil)
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
@@ -0,0 +1,161 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"weplant-backend/helper"
+)
+
+func encodeJPEGTest(t *testing.T, width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 120, A: 255})
+		}
+	}
+	var buffer bytes.Buffer
+	err := jpeg.Encode(&buffer, img, nil)
+	assert.Nil(t, err)
+	return buffer.Bytes()
+}
+
+// withOrientationTest puts an EXIF segment holding orientation right after the start of the JPEG
+func withOrientationTest(data []byte, orientation byte) []byte {
+	tiff := []byte{
+		'M', 'M', 0, 42, 0, 0, 0, 8, // big endian header, first IFD at 8
+		0, 1, // one entry
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0, // orientation, SHORT, count 1
+		0, 0, 0, 0, // no next IFD
+	}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+	app1 := append([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}, segment...)
+	return append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+}
+
+// Test Process Image
+
+func TestProcessImageVariants_Success(t *testing.T) {
+	processed, err := helper.ProcessImage(encodeJPEGTest(t, 1600, 800), helper.ProductImageLimit)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1600, processed.Width)
+	assert.Equal(t, 800, processed.Height)
+	assert.Equal(t, "image/jpeg", http.DetectContentType(processed.Content))
+	assert.NotEmpty(t, processed.BlurHash)
+
+	assert.Equal(t, 3, len(processed.Variants))
+	sizes := map[string][2]int{"thumbnail": {150, 75}, "medium": {600, 300}, "large": {1200, 600}}
+	for _, variant := range processed.Variants {
+		assert.Equal(t, sizes[variant.Name], [2]int{variant.Width, variant.Height})
+		config, err := jpeg.DecodeConfig(bytes.NewReader(variant.Content))
+		assert.Nil(t, err)
+		assert.Equal(t, variant.Width, config.Width)
+		assert.Equal(t, variant.Height, config.Height)
+	}
+}
+
+func TestProcessImageOrientation_Success(t *testing.T) {
+	data := withOrientationTest(encodeJPEGTest(t, 400, 200), 6)
+
+	processed, err := helper.ProcessImage(data, helper.CustomerImageLimit)
+	assert.Nil(t, err)
+
+	// turned upright and the EXIF segment is gone
+	assert.Equal(t, 200, processed.Width)
+	assert.Equal(t, 400, processed.Height)
+	assert.False(t, bytes.Contains(processed.Content, []byte("Exif")))
+}
+
+func TestProcessImageTransparent_Success(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 300, 300))
+	img.Set(10, 10, color.NRGBA{R: 255, A: 128})
+	var buffer bytes.Buffer
+	assert.Nil(t, png.Encode(&buffer, img))
+
+	processed, err := helper.ProcessImage(buffer.Bytes(), helper.CategoryImageLimit)
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", http.DetectContentType(processed.Content))
+	assert.Equal(t, "image/png", http.DetectContentType(processed.Variants[0].Content))
+}
+
+func TestProcessImageType_Failed(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Nil(t, gif.Encode(&buffer, image.NewPaletted(image.Rect(0, 0, 300, 300), color.Palette{color.Black}), nil))
+
+	_, err := helper.ProcessImage(buffer.Bytes(), helper.ProductImageLimit)
+	assert.Equal(t, "must be a JPEG, PNG or WebP image", err.Error())
+
+	_, err = helper.ProcessImage([]byte("<svg></svg>"), helper.ProductImageLimit)
+	assert.Equal(t, "must be a JPEG, PNG or WebP image", err.Error())
+}
+
+func TestProcessImageDimension_Failed(t *testing.T) {
+	_, err := helper.ProcessImage(encodeJPEGTest(t, 250, 400), helper.ProductImageLimit)
+	assert.Equal(t, "must be at least 300x300 pixels", err.Error())
+
+	_, err = helper.ProcessImage(encodeJPEGTest(t, 5000, 120), helper.CustomerImageLimit)
+	assert.Equal(t, "must be at most 4096x4096 pixels", err.Error())
+}
+
+func TestProcessImageWebP_Failed(t *testing.T) {
+	// a 1x1 lossless WebP is sniffed and decoded, then turned down for its size
+	webp, err := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	assert.Nil(t, err)
+
+	_, err = helper.ProcessImage(webp, helper.CustomerImageLimit)
+	assert.Equal(t, "must be at least 100x100 pixels", err.Error())
+}
+
+func TestBlurHashSolidColor_Success(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+
+	hash := helper.BlurHash(img, 4, 3)
+	// size flag for 4x3 components, the quantised maximum, the average colour then two characters per AC component
+	assert.Equal(t, 1+1+4+2*11, len(hash))
+	assert.Equal(t, "L", hash[:1])
+	assert.Equal(t, "TI:j", hash[2:6])
+}
+
+// Test Read Form Images
+
+func TestReadFormImagesSameName_Success(t *testing.T) {
+	// phones upload every photo as image.jpg
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i := 0; i < 2; i++ {
+		part, err := writer.CreateFormFile("images", "image.jpg")
+		assert.Nil(t, err)
+		part.Write(encodeJPEGTest(t, 400, 300))
+	}
+	writer.Close()
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/products", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	assert.Nil(t, request.ParseMultipartForm(32<<20))
+
+	images := helper.ReadFormImages(request, "images", helper.ProductImageLimit)
+	if !assert.Equal(t, 2, len(images)) {
+		return
+	}
+	assert.NotEqual(t, images[0].FileName, images[1].FileName)
+	for _, image := range images {
+		assert.Equal(t, "image.jpg", image.Name)
+		assert.True(t, strings.HasSuffix(image.FileName, "-image"), image.FileName)
+	}
+}
@@ -0,0 +1,233 @@
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"weplant-backend/app"
+	"weplant-backend/repository"
+)
+
+// Test Local Image Repository
+
+func TestLocalImageRepository_Success(t *testing.T) {
+	dir := t.TempDir()
+	imageRepository := repository.NewLocalImageRepository(dir, "https://test.com/")
+
+	url, err := imageRepository.UploadImage(context.Background(), "1650000000-bayam", strings.NewReader("bayam image"))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://test.com/images/1650000000-bayam", url)
+
+	router := httprouter.New()
+	app.ServeLocalImages(router, dir)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "https://test.com/images/1650000000-bayam", nil))
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "bayam image", recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "https://test.com/images/", nil))
+	assert.Equal(t, 404, recorder.Code)
+
+	err = imageRepository.DeleteImage(context.Background(), "1650000000-bayam")
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "1650000000-bayam"))
+	assert.True(t, os.IsNotExist(err))
+
+	// deleting twice is fine, the image is gone either way
+	err = imageRepository.DeleteImage(context.Background(), "1650000000-bayam")
+	assert.Nil(t, err)
+}
+
+func TestLocalImageRepositoryList_Success(t *testing.T) {
+	dir := t.TempDir()
+	imageRepository := repository.NewLocalImageRepository(dir, "")
+
+	images, err := repository.NewLocalImageRepository(filepath.Join(dir, "nothing yet"), "").ListImages(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(images))
+
+	_, err = imageRepository.UploadImage(context.Background(), "1650000000-bayam", strings.NewReader("bayam image"))
+	assert.Nil(t, err)
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "tmp"), 0755))
+
+	images, err = imageRepository.ListImages(context.Background())
+	assert.Nil(t, err)
+	if !assert.Equal(t, 1, len(images)) {
+		return
+	}
+	assert.Equal(t, "1650000000-bayam", images[0].FileName)
+	assert.False(t, images[0].CreatedAt.IsZero())
+}
+
+func TestLocalImageRepositoryFilename_Failed(t *testing.T) {
+	dir := t.TempDir()
+	imageRepository := repository.NewLocalImageRepository(filepath.Join(dir, "images"), "")
+
+	_, err := imageRepository.UploadImage(context.Background(), "../bayam", strings.NewReader("bayam image"))
+	assert.NotNil(t, err)
+	_, err = os.Stat(filepath.Join(dir, "bayam"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = imageRepository.UploadImage(context.Background(), "bayam", "https://test.com/bayam.jpg")
+	assert.Equal(t, repository.ErrImageSource, err)
+}
+
+// Test S3 Image Repository
+
+// s3StandIn keeps the objects of the buckets in memory like a MinIO server would
+type s3StandIn struct {
+	sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newS3StandIn(accessKey string) (*s3StandIn, *httptest.Server) {
+	standIn := &s3StandIn{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		standIn.Lock()
+		defer standIn.Unlock()
+
+		if request.Method != http.MethodGet {
+			authorization := request.Header.Get("Authorization")
+			if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential="+accessKey+"/") || !strings.Contains(authorization, "/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") || request.Header.Get("X-Amz-Date") == "" {
+				writer.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+		body, _ := ioutil.ReadAll(request.Body)
+		sum := sha256.Sum256(body)
+		if request.Method == http.MethodPut && request.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch request.Method {
+		case http.MethodPut:
+			standIn.objects[request.URL.Path] = body
+			standIn.types[request.URL.Path] = request.Header.Get("Content-Type")
+		case http.MethodDelete:
+			delete(standIn.objects, request.URL.Path)
+			writer.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			if request.URL.Query().Get("list-type") == "2" {
+				standIn.list(writer, request)
+				return
+			}
+			object, ok := standIn.objects[request.URL.Path]
+			if !ok {
+				writer.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writer.Write(object)
+		}
+	}))
+	return standIn, server
+}
+
+// list answers ListObjectsV2 one key per page, so continuing from a token is exercised
+func (standIn *s3StandIn) list(writer http.ResponseWriter, request *http.Request) {
+	prefix := request.URL.Path + "/"
+	var keys []string
+	for path := range standIn.objects {
+		if strings.HasPrefix(path, prefix) {
+			keys = append(keys, strings.TrimPrefix(path, prefix))
+		}
+	}
+	sort.Strings(keys)
+	token := request.URL.Query().Get("continuation-token")
+	for len(keys) > 0 && keys[0] <= token {
+		keys = keys[1:]
+	}
+	result := "<ListBucketResult>"
+	if len(keys) > 0 {
+		result += "<Contents><Key>" + keys[0] + "</Key><LastModified>2022-04-15T05:20:00.000Z</LastModified></Contents>"
+	}
+	if len(keys) > 1 {
+		result += "<IsTruncated>true</IsTruncated><NextContinuationToken>" + keys[0] + "</NextContinuationToken>"
+	}
+	writer.Write([]byte(result + "</ListBucketResult>"))
+}
+
+func TestS3ImageRepository_Success(t *testing.T) {
+	standIn, server := newS3StandIn("minio")
+	defer server.Close()
+
+	imageRepository := repository.NewS3ImageRepository(repository.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "weplant",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+	})
+
+	url, err := imageRepository.UploadImage(context.Background(), "1650000000-bayam hijau", strings.NewReader("bayam image"))
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/weplant/1650000000-bayam%20hijau", url)
+	assert.Equal(t, "bayam image", string(standIn.objects["/weplant/1650000000-bayam hijau"]))
+	assert.Equal(t, "text/plain; charset=utf-8", standIn.types["/weplant/1650000000-bayam hijau"])
+
+	response, err := http.Get(url)
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	response.Body.Close()
+
+	err = imageRepository.DeleteImage(context.Background(), "1650000000-bayam hijau")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(standIn.objects))
+}
+
+func TestS3ImageRepositoryList_Success(t *testing.T) {
+	standIn, server := newS3StandIn("minio")
+	defer server.Close()
+	standIn.objects["/weplant/1650000000-bayam"] = []byte("bayam image")
+	standIn.objects["/weplant/1650000000-bayam-thumbnail"] = []byte("bayam thumbnail")
+	standIn.objects["/weplant/1650000001-kangkung"] = []byte("kangkung image")
+	standIn.objects["/another/1650000002-sawi"] = []byte("sawi image")
+
+	imageRepository := repository.NewS3ImageRepository(repository.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "weplant",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+	})
+
+	images, err := imageRepository.ListImages(context.Background())
+	assert.Nil(t, err)
+	if !assert.Equal(t, 3, len(images)) {
+		return
+	}
+	assert.Equal(t, "1650000000-bayam", images[0].FileName)
+	assert.Equal(t, "1650000000-bayam-thumbnail", images[1].FileName)
+	assert.Equal(t, "1650000001-kangkung", images[2].FileName)
+	assert.Equal(t, int64(1650000000), images[0].CreatedAt.Unix())
+}
+
+func TestS3ImageRepositoryCredential_Failed(t *testing.T) {
+	standIn, server := newS3StandIn("minio")
+	defer server.Close()
+
+	imageRepository := repository.NewS3ImageRepository(repository.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "weplant",
+		AccessKey: "someone-else",
+		SecretKey: "minio-secret",
+		PublicURL: "https://cdn.test.com",
+	})
+
+	_, err := imageRepository.UploadImage(context.Background(), "1650000000-bayam", strings.NewReader("bayam image"))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "403")
+	assert.Equal(t, 0, len(standIn.objects))
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"weplant-backend/helper"
+	"weplant-backend/integration_test/repository_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+	"weplant-backend/service"
+)
+
+var referencedImageTest = schema.Image{
+	FileName: "1650000000-bayam",
+	URL:      "https://test.com/images/1650000000-bayam",
+	Variants: []schema.ImageVariant{
+		{Name: "thumbnail", FileName: "1650000000-bayam-thumbnail"},
+	},
+}
+
+func storedImageTest(filename string, age time.Duration) repository.StoredImage {
+	return repository.StoredImage{
+		FileName:  filename,
+		CreatedAt: time.Unix(int64(helper.GetTimeNow()), 0).Add(-age),
+	}
+}
+
+// setupImageServiceTest gives every test its own mocks, the shared ones would answer with the expectations of an
+// earlier test
+func setupImageServiceTest() (*repository_mock.ImageRepositoryMock, *repository_mock.ImageReferenceRepositoryMock) {
+	return &repository_mock.ImageRepositoryMock{Mock: mock.Mock{}}, &repository_mock.ImageReferenceRepositoryMock{Mock: mock.Mock{}}
+}
+
+// Test Reconcile Images
+
+func TestReconcileImagesOrphan_Success(t *testing.T) {
+	imageRepository, imageReferenceRepository := setupImageServiceTest()
+	imageRepository.Mock.On("ListImages", mock.Anything).Return([]repository.StoredImage{
+		storedImageTest("1650000000-bayam", 72*time.Hour),
+		storedImageTest("1650000000-bayam-thumbnail", 72*time.Hour),
+		storedImageTest("1650000001-kangkung", 72*time.Hour),
+		storedImageTest("1650000002-sawi", time.Hour),
+	}, nil)
+	imageReferenceRepository.Mock.On("FindAll", mock.Anything).Return([]repository.ImageReference{
+		{Collection: "product", DocumentId: "62590fd6d7fa4b5a9f6b1a1a", Image: referencedImageTest},
+	}, nil)
+	imageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
+
+	imageService := service.NewImageService(imageRepository, imageReferenceRepository)
+	report, err := imageService.Reconcile(context.Background(), web.ImageReconcileRequest{
+		GracePeriod:   int((24 * time.Hour).Seconds()),
+		DeleteOrphans: true,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, report.StoredImages)
+	assert.Equal(t, 2, report.ReferencedImages)
+	assert.Equal(t, 1, report.DeletedOrphans)
+	assert.Equal(t, 0, len(report.MissingImages))
+
+	// the fresh orphan may still be waiting for its document
+	if !assert.Equal(t, 2, len(report.Orphans)) {
+		return
+	}
+	assert.Equal(t, "1650000001-kangkung", report.Orphans[0].FileName)
+	assert.True(t, report.Orphans[0].Deleted)
+	assert.Equal(t, "1650000002-sawi", report.Orphans[1].FileName)
+	assert.False(t, report.Orphans[1].Deleted)
+	imageRepository.Mock.AssertCalled(t, "DeleteImage", mock.Anything, "1650000001-kangkung")
+	imageRepository.Mock.AssertNotCalled(t, "DeleteImage", mock.Anything, "1650000002-sawi")
+	imageRepository.Mock.AssertNotCalled(t, "DeleteImage", mock.Anything, "1650000000-bayam-thumbnail")
+}
+
+func TestReconcileImagesReportOnly_Success(t *testing.T) {
+	imageRepository, imageReferenceRepository := setupImageServiceTest()
+	imageRepository.Mock.On("ListImages", mock.Anything).Return([]repository.StoredImage{
+		storedImageTest("1650000001-kangkung", 72*time.Hour),
+	}, nil)
+	imageReferenceRepository.Mock.On("FindAll", mock.Anything).Return([]repository.ImageReference{
+		{Collection: "merchant", DocumentId: "62590fd6d7fa4b5a9f6b1a1b", Image: referencedImageTest},
+	}, nil)
+
+	imageService := service.NewImageService(imageRepository, imageReferenceRepository)
+	report, err := imageService.Reconcile(context.Background(), web.ImageReconcileRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(report.Orphans))
+	assert.Equal(t, 0, report.DeletedOrphans)
+	imageRepository.Mock.AssertNotCalled(t, "DeleteImage", mock.Anything, mock.Anything)
+
+	// both the original and its variant are gone from the storage
+	if !assert.Equal(t, 2, len(report.MissingImages)) {
+		return
+	}
+	assert.Equal(t, web.MissingImageResponse{
+		Collection: "merchant",
+		DocumentId: "62590fd6d7fa4b5a9f6b1a1b",
+		FileName:   "1650000000-bayam-thumbnail",
+		URL:        "https://test.com/images/1650000000-bayam",
+	}, report.MissingImages[1])
+}
+
+func TestReconcileImagesNoReference_Failed(t *testing.T) {
+	imageRepository, imageReferenceRepository := setupImageServiceTest()
+	imageRepository.Mock.On("ListImages", mock.Anything).Return([]repository.StoredImage{
+		storedImageTest("1650000001-kangkung", 72*time.Hour),
+	}, nil)
+	imageReferenceRepository.Mock.On("FindAll", mock.Anything).Return(nil, nil)
+
+	imageService := service.NewImageService(imageRepository, imageReferenceRepository)
+	_, err := imageService.Reconcile(context.Background(), web.ImageReconcileRequest{DeleteOrphans: true})
+
+	assert.Equal(t, service.ErrNoImageReferences, err)
+	imageRepository.Mock.AssertNotCalled(t, "DeleteImage", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weplant-backend/helper"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// Test Find Ledger Merchant
+
+func TestFindLedgerMerchant_Success(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.LedgerRepository.Mock.On("FindByMerchantId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.LedgerEntry{schema_mock.LedgerEntry}, repository.PageInfo{NextCursor: "next-cursor"}, nil)
+	config.LedgerRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(11, nil)
+
+	router := config.SetupRouterTest()
+
+	cursor := helper.EncodeCursor(helper.Cursor{Value: schema_mock.LedgerEntry.CreatedAt, Id: schema_mock.LedgerEntry.Id.Hex()})
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/ledger?cursor="+cursor, nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	body, _ := io.ReadAll(response.Body)
+	var webResponse struct {
+		Data web.LedgerResponse `json:"data"`
+	}
+	err := json.Unmarshal(body, &webResponse)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, schema_mock.Merchant.Balance, webResponse.Data.Balance)
+	assert.Equal(t, 1, len(webResponse.Data.Entries))
+	assert.Equal(t, 11, webResponse.Data.Metadata.TotalData)
+	assert.Equal(t, "next-cursor", webResponse.Data.Metadata.NextCursor)
+	config.LedgerRepository.Mock.AssertCalled(t, "FindByMerchantId", mock.Anything, schema_mock.Merchant.Id.Hex(), repository.Page{Cursor: cursor, Limit: 10})
+}
+
+func TestFindLedgerMerchant_FailedUnauthorized(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/ledger", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/repository_mock"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/service"
+)
+
+// retriedSessionTest runs every transaction twice like a transaction retried after a transient error
+type retriedSessionTest struct{}
+
+func (session retriedSessionTest) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// Test Reconcile Ledger
+
+func TestReconcileLedger_Success(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Merchant{schema_mock.Merchant}, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("SetBalance", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.LedgerRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(3, nil)
+	config.LedgerRepository.Mock.On("SumByMerchantId", mock.Anything, mock.Anything).Return(int64(150000), nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	ledgerService := service.NewLedgerService(&config.LedgerRepository, &config.MerchantRepository, &config.SessionRepository)
+	fixed, err := ledgerService.Reconcile(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fixed)
+	config.MerchantRepository.Mock.AssertCalled(t, "SetBalance", mock.Anything, schema_mock.Merchant.Id.Hex(), int64(150000))
+	config.LedgerRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReconcileLedgerOpeningBalance_Success(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Merchant{schema_mock.Merchant}, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.LedgerRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(0, nil)
+	config.LedgerRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.LedgerEntry, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	ledgerService := service.NewLedgerService(&config.LedgerRepository, &config.MerchantRepository, &config.SessionRepository)
+	fixed, err := ledgerService.Reconcile(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fixed)
+	config.LedgerRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(entry schema.LedgerEntry) bool {
+		return entry.Type == schema.LedgerTypeOpeningBalance && entry.Amount == schema_mock.Merchant.Balance
+	}))
+	config.MerchantRepository.Mock.AssertNotCalled(t, "SetBalance", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReconcileLedgerRetried_Success(t *testing.T) {
+	merchantRepository := &repository_mock.MerchantRepositoryMock{Mock: mock.Mock{}}
+	ledgerRepository := &repository_mock.LedgerRepositoryMock{Mock: mock.Mock{}}
+	merchantRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Merchant{schema_mock.Merchant}, nil)
+	merchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	merchantRepository.Mock.On("SetBalance", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	ledgerRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(3, nil)
+	ledgerRepository.Mock.On("SumByMerchantId", mock.Anything, mock.Anything).Return(int64(150000), nil)
+
+	ledgerService := service.NewLedgerService(ledgerRepository, merchantRepository, retriedSessionTest{})
+	fixed, err := ledgerService.Reconcile(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fixed)
+	merchantRepository.Mock.AssertNumberOfCalls(t, "SetBalance", 2)
+}
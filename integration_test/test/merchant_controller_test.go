@@ -2,7 +2,6 @@ package test
 
 import (
 	"bytes"
-	"context"
 	_ "embed"
 	"encoding/json"
 	"errors"
@@ -18,6 +17,8 @@ import (
 	"weplant-backend/integration_test/schema_mock"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
+	"weplant-backend/repository"
+	"weplant-backend/service"
 )
 
 //go:embed "elonmusk.jpg"
@@ -26,22 +27,23 @@ var uploadImageTest []byte
 // Test Create Merchant
 
 func TestCreateMerchant_Success(t *testing.T) {
-	config.MerchantRepository.Mock.On("Create", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.RefreshTokenRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.RefreshToken, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
 	writer.WriteField("email", "ilham@gmail.com")
-	writer.WriteField("password", "12345")
+	writer.WriteField("password", "rahasia123")
 	writer.WriteField("name", "toko ilham")
 	writer.WriteField("phone", "081234567890")
 	writer.WriteField("address", "Sudimoro")
 	writer.WriteField("city", "Kudus")
 	writer.WriteField("province", "Jawa Tengah")
-	writer.WriteField("postal_code", "873623")
+	writer.WriteField("postal_code", "59312")
 
 	file, err := writer.CreateFormFile("image", "elonmusk.jpg")
 	if err != nil {
@@ -65,22 +67,22 @@ func TestCreateMerchant_Success(t *testing.T) {
 }
 
 func TestCreateMerchant_Failed(t *testing.T) {
-	config.MerchantRepository.Mock.On("Create", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background()).Return(nil)
+	config.MerchantRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
 	writer.WriteField("email", "ilham@gmail.com")
-	writer.WriteField("password", "12345")
+	writer.WriteField("password", "rahasia123")
 	writer.WriteField("name", "toko ilham")
 	writer.WriteField("phone", "081234567890")
 	writer.WriteField("address", "Sudimoro")
 	writer.WriteField("city", "Kudus")
 	writer.WriteField("province", "Jawa Tengah")
-	writer.WriteField("postal_code", "873623")
+	writer.WriteField("postal_code", "59312")
 
 	file, err := writer.CreateFormFile("image", "elonmusk.jpg")
 	if err != nil {
@@ -106,33 +108,35 @@ func TestCreateMerchant_Failed(t *testing.T) {
 // Test FindById Merchant
 
 func TestFindByIdMerchant_Success(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
 
-	config.ProductRepository.Mock.On("FindByMerchantId", context.Background(), mock.Anything).Return([]schema.Product{
+	config.ProductRepository.Mock.On("FindPageByMerchantId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Product{
 		schema_mock.Product,
 		schema_mock.Product,
-	}, nil)
+	}, repository.PageInfo{}, nil)
+	config.ProductRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(2, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/10", nil)
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"?perPage=2", nil)
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "FindPageByMerchantId", mock.Anything, schema_mock.Merchant.Id.Hex(), repository.Page{Limit: 2})
 
 	//bytes, _ := io.ReadAll(response.Body)
 	//fmt.Println(string(bytes))
 }
 
 func TestFindByIdMerchant_Failed(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/1", nil)
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex(), nil)
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
@@ -147,13 +151,17 @@ func TestFindByIdMerchant_Failed(t *testing.T) {
 // Test FindManageOrderById Merchant
 
 func TestFindManageOrderByIdMerchant_Success(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
 
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	config.OrderRepository.Mock.On("FindByMerchantId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Order{schema_mock.Order}, repository.PageInfo{}, nil)
+	config.OrderRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(21, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/10/orders", nil)
+	cursor := helper.EncodeCursor(helper.Cursor{Value: schema_mock.Order.CreatedAt, Id: schema_mock.Order.Id.Hex()})
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders?cursor="+cursor, nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
 
@@ -161,19 +169,20 @@ func TestFindManageOrderByIdMerchant_Success(t *testing.T) {
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertCalled(t, "FindByMerchantId", mock.Anything, schema_mock.Merchant.Id.Hex(), repository.Page{Cursor: cursor, Limit: 10})
 
 	//bytes, _ := io.ReadAll(response.Body)
 	//fmt.Println(string(bytes))
 }
 
 func TestFindManageOrderByIdMerchant_Failed(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
 
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/10/orders", nil)
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders", nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
 
@@ -187,13 +196,13 @@ func TestFindManageOrderByIdMerchant_Failed(t *testing.T) {
 }
 
 func TestFindManageOrderByIdMerchant_FailedUnauthorized(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
 
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/10/orders", nil)
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders", nil)
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
@@ -205,11 +214,230 @@ func TestFindManageOrderByIdMerchant_FailedUnauthorized(t *testing.T) {
 	//fmt.Println(string(bytes))
 }
 
+// Test UpdateOrderStatus Merchant
+
+func TestUpdateOrderStatusMerchant_Success(t *testing.T) {
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.ManageOrderUpdateStatusRequest{
+		Status: schema.OrderStatusProcessing,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders/"+schema_mock.Order.Id.Hex()+"/status", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, schema_mock.Order.Id.Hex(), schema.OrderStatusPaid, mock.MatchedBy(func(history schema.OrderHistory) bool {
+		return history.Status == schema.OrderStatusProcessing
+	}), (*schema.OrderShipping)(nil))
+
+	var webResponse struct {
+		Data web.ManageOrderDetailResponse `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&webResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, schema.OrderStatusProcessing, webResponse.Data.Status)
+	assert.Equal(t, 2, len(webResponse.Data.History))
+}
+
+func TestUpdateOrderStatusMerchantCancelled_Success(t *testing.T) {
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.ManageOrderUpdateStatusRequest{
+		Status: schema.OrderStatusCancelled,
+		Note:   "stok habis",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders/"+schema_mock.Order.Id.Hex()+"/status", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "UpdateQuantity", mock.Anything, schema.Product{
+		Id:    helper.ObjectIDFromHex(schema_mock.OrderItem.ProductId),
+		Stock: schema_mock.OrderItem.Quantity,
+	})
+	config.LedgerRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateOrderStatusMerchantRefunded_Success(t *testing.T) {
+	order := schema_mock.Order
+	order.Status = schema.OrderStatusRefundRequested
+	order.History = []schema.OrderHistory{
+		schema_mock.OrderHistory,
+		{CreatedAt: helper.GetTimeNow(), Status: schema.OrderStatusCompleted},
+		{CreatedAt: helper.GetTimeNow(), Status: schema.OrderStatusRefundRequested},
+	}
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+	config.LedgerRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.LedgerEntry, nil)
+	config.MerchantRepository.Mock.On("UpdateBalance", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.ManageOrderUpdateStatusRequest{
+		Status: schema.OrderStatusCancelled,
+		Note:   "barang rusak",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders/"+order.Id.Hex()+"/status", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	earning := int64(order.Total) - int64(order.Subtotal)*service.CommissionPercent/100
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.LedgerRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(entry schema.LedgerEntry) bool {
+		return entry.Type == schema.LedgerTypeRefund && entry.Amount == -earning && entry.ReferenceId == order.Id.Hex()
+	}))
+	config.MerchantRepository.Mock.AssertCalled(t, "UpdateBalance", mock.Anything, schema.Merchant{
+		Id:      schema_mock.Merchant.Id,
+		Balance: -earning,
+	})
+}
+
+func TestUpdateOrderStatusMerchantTransition_Failed(t *testing.T) {
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.ManageOrderUpdateStatusRequest{
+		Status: schema.OrderStatusDelivered,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders/"+schema_mock.Order.Id.Hex()+"/status", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+	config.OrderRepository.Mock.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateOrderStatusMerchantNotOwned_Failed(t *testing.T) {
+	order := schema_mock.Order
+	order.MerchantId = primitive.NewObjectID().Hex()
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.ManageOrderUpdateStatusRequest{
+		Status: schema.OrderStatusProcessing,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders/"+order.Id.Hex()+"/status", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+// Test UpdateOrderShipping Merchant
+
+func TestUpdateOrderShippingMerchant_Success(t *testing.T) {
+	order := schema_mock.Order
+	order.Status = schema.OrderStatusProcessing
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+	config.OrderRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.ManageOrderUpdateShippingRequest{
+		Courier:        "jne",
+		TrackingNumber: "JNE0123456789",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders/"+order.Id.Hex()+"/shipping", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, order.Id.Hex(), schema.OrderStatusProcessing, mock.MatchedBy(func(history schema.OrderHistory) bool {
+		return history.Status == schema.OrderStatusShipped
+	}), &schema.OrderShipping{
+		Courier:        "jne",
+		TrackingNumber: "JNE0123456789",
+	})
+}
+
+func TestUpdateOrderShippingMerchant_Failed(t *testing.T) {
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.ManageOrderUpdateShippingRequest{
+		Courier: "jne",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/orders/"+schema_mock.Order.Id.Hex()+"/shipping", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+}
+
 // Test Update Merchant
 
 func TestUpdateMerchant_Success(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.MerchantRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
 
 	router := config.SetupRouterTest()
 
@@ -217,12 +445,12 @@ func TestUpdateMerchant_Success(t *testing.T) {
 		Id:        primitive.NewObjectID().Hex(),
 		UpdatedAt: helper.GetTimeNow(),
 		Name:      "toko yanuar",
-		Phone:     "098765432123",
+		Phone:     "081298765432",
 		Address: &web.AddressUpdateRequest{
 			Address:    "wonoketingal",
 			City:       "kudus",
 			Province:   "jawa tengah",
-			PostalCode: "837454",
+			PostalCode: "59312",
 		},
 	}
 
@@ -231,7 +459,7 @@ func TestUpdateMerchant_Success(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/merchants/10", bytes.NewReader(body))
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex(), bytes.NewReader(body))
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
 
@@ -242,8 +470,8 @@ func TestUpdateMerchant_Success(t *testing.T) {
 }
 
 func TestUpdateMerchant_Failed(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.MerchantRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.MerchantRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
 
 	router := config.SetupRouterTest()
 
@@ -251,12 +479,12 @@ func TestUpdateMerchant_Failed(t *testing.T) {
 		Id:        primitive.NewObjectID().Hex(),
 		UpdatedAt: helper.GetTimeNow(),
 		Name:      "toko yanuar",
-		Phone:     "098765432123",
+		Phone:     "081298765432",
 		Address: &web.AddressUpdateRequest{
 			Address:    "wonoketingal",
 			City:       "kudus",
 			Province:   "jawa tengah",
-			PostalCode: "837454",
+			PostalCode: "59312",
 		},
 	}
 
@@ -265,7 +493,7 @@ func TestUpdateMerchant_Failed(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/merchants/10", bytes.NewReader(body))
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex(), bytes.NewReader(body))
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
 
@@ -276,8 +504,8 @@ func TestUpdateMerchant_Failed(t *testing.T) {
 }
 
 func TestUpdateMerchant_FailedUnauthorized(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.MerchantRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
 
 	router := config.SetupRouterTest()
 
@@ -285,12 +513,12 @@ func TestUpdateMerchant_FailedUnauthorized(t *testing.T) {
 		Id:        primitive.NewObjectID().Hex(),
 		UpdatedAt: helper.GetTimeNow(),
 		Name:      "toko yanuar",
-		Phone:     "098765432123",
+		Phone:     "081298765432",
 		Address: &web.AddressUpdateRequest{
 			Address:    "wonoketingal",
 			City:       "kudus",
 			Province:   "jawa tengah",
-			PostalCode: "837454",
+			PostalCode: "59312",
 		},
 	}
 
@@ -299,7 +527,7 @@ func TestUpdateMerchant_FailedUnauthorized(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/merchants/10", bytes.NewReader(body))
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex(), bytes.NewReader(body))
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
@@ -308,13 +536,45 @@ func TestUpdateMerchant_FailedUnauthorized(t *testing.T) {
 	assert.Equal(t, 401, response.StatusCode)
 }
 
+func TestUpdateMerchant_FailedForbidden(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := web.MerchantUpdateRequest{
+		Name:  "toko yanuar",
+		Phone: "081298765432",
+		Address: &web.AddressUpdateRequest{
+			Address:    "wonoketingal",
+			City:       "kudus",
+			Province:   "jawa tengah",
+			PostalCode: "59312",
+		},
+	}
+
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/merchants/"+primitive.NewObjectID().Hex(), bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 403, response.StatusCode)
+}
+
 // Test UpdateMainImage Merchant
 
 func TestUpdateMainImage_Success(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.MerchantRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -327,7 +587,7 @@ func TestUpdateMainImage_Success(t *testing.T) {
 	file.Write(uploadImageTest)
 	writer.Close()
 
-	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/3/image", body)
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/image", body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
@@ -343,10 +603,10 @@ func TestUpdateMainImage_Success(t *testing.T) {
 }
 
 func TestUpdateMainImage_Failed(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.MerchantRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.MerchantRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -359,7 +619,7 @@ func TestUpdateMainImage_Failed(t *testing.T) {
 	file.Write(uploadImageTest)
 	writer.Close()
 
-	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/3/image", body)
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/image", body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
@@ -372,10 +632,10 @@ func TestUpdateMainImage_Failed(t *testing.T) {
 }
 
 func TestUpdateMainImage_FailedUnauthorized(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.MerchantRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -388,7 +648,7 @@ func TestUpdateMainImage_FailedUnauthorized(t *testing.T) {
 	file.Write(uploadImageTest)
 	writer.Close()
 
-	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/3/image", body)
+	request := httptest.NewRequest(http.MethodPatch, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/image", body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	recorder := httptest.NewRecorder()
 
@@ -402,14 +662,14 @@ func TestUpdateMainImage_FailedUnauthorized(t *testing.T) {
 // Test Delete Merchant
 
 func TestDeleteMerchant_Success(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.ProductRepository.Mock.On("FindByMerchantId", context.Background(), mock.Anything).Return(nil, nil)
-	config.MerchantRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ProductRepository.Mock.On("FindByMerchantId", mock.Anything, mock.Anything).Return(nil, nil)
+	config.MerchantRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/merchants/10", nil)
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex(), nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
 
@@ -423,14 +683,14 @@ func TestDeleteMerchant_Success(t *testing.T) {
 }
 
 func TestDeleteMerchant_Failed(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindByMerchantId", context.Background(), mock.Anything).Return(nil, nil)
-	config.MerchantRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindByMerchantId", mock.Anything, mock.Anything).Return(nil, nil)
+	config.MerchantRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/merchants/10", nil)
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex(), nil)
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
 	recorder := httptest.NewRecorder()
 
@@ -444,14 +704,14 @@ func TestDeleteMerchant_Failed(t *testing.T) {
 }
 
 func TestDeleteMerchant_FailedUnauthorized(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.ProductRepository.Mock.On("FindByMerchantId", context.Background(), mock.Anything).Return(nil, nil)
-	config.MerchantRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ProductRepository.Mock.On("FindByMerchantId", mock.Anything, mock.Anything).Return(nil, nil)
+	config.MerchantRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/merchants/10", nil)
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/merchants/"+schema_mock.Merchant.Id.Hex(), nil)
 	recorder := httptest.NewRecorder()
 
 	router.ServeHTTP(recorder, request)
@@ -0,0 +1,168 @@
+package test
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"weplant-backend/app"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/middleware"
+	"weplant-backend/pkg"
+	"weplant-backend/repository"
+)
+
+// histogramCount is the number of observations of one histogram series
+func histogramCount(t *testing.T, observer prometheus.Observer) uint64 {
+	var metric dto.Metric
+	err := observer.(prometheus.Metric).Write(&metric)
+	assert.Nil(t, err)
+	return metric.GetHistogram().GetSampleCount()
+}
+
+// Test Metrics
+
+func TestMetricsEndpoint_Success(t *testing.T) {
+	router := config.SetupRouterTest()
+	handler := middleware.MetricsMiddleware(router)
+	requests := testutil.ToFloat64(pkg.HTTPRequests.WithLabelValues("GET", "/api/v1/merchants/:merchantId/orders", "401"))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/merchants/62590fd6d7fa4b5a9f6b1a1a/orders", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, 401, recorder.Code)
+	assert.Equal(t, requests+1, testutil.ToFloat64(pkg.HTTPRequests.WithLabelValues("GET", "/api/v1/merchants/:merchantId/orders", "401")))
+	assert.Equal(t, uint64(requests+1), histogramCount(t, pkg.HTTPRequestDuration.WithLabelValues("GET", "/api/v1/merchants/:merchantId/orders", "401")))
+
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8080/metrics", nil)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	body, _ := ioutil.ReadAll(recorder.Body)
+	assert.Contains(t, string(body), "# TYPE weplant_http_requests_total counter\n")
+	assert.Contains(t, string(body), `weplant_http_requests_total{method="GET",route="/api/v1/merchants/:merchantId/orders",status="401"} `)
+	assert.Contains(t, string(body), "# TYPE weplant_http_request_duration_seconds histogram\n")
+	assert.Contains(t, string(body), `weplant_http_request_duration_seconds_bucket{method="GET",route="/api/v1/merchants/:merchantId/orders",status="401",le="+Inf"} `)
+	assert.Contains(t, string(body), "# TYPE weplant_orders_paid_total counter\n")
+}
+
+// routeRequests sums the requests counted for method and route over every status
+func routeRequests(t *testing.T, method string, route string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.Nil(t, err)
+	var requests float64
+	for _, family := range families {
+		if family.GetName() != "weplant_http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["method"] == method && labels["route"] == route {
+				requests += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return requests
+}
+
+func TestMetricsRoutePattern_Success(t *testing.T) {
+	handler := middleware.MetricsMiddleware(config.SetupRouterTest())
+
+	patterns := map[string]string{
+		"/api/v1/products":                          "/api/v1/products",
+		"/api/v1/products/62590fd6d7fa4b5a9f6b1a1a": "/api/v1/products/:productId",
+		// a parameter holding the same value as a fixed segment is still told apart
+		"/api/v1/products/v1":                            "/api/v1/products/:productId",
+		"/api/v1/merchants/api/vouchers/v1":              "/api/v1/merchants/:merchantId/vouchers/:voucherId",
+		"/docs/index.html":                               "/docs/*filepath",
+		"/api/v1/nothing/here":                           middleware.UnmatchedRoute,
+		"/api/v1/products/62590fd6d7fa4b5a9f6b1a1a/more": middleware.UnmatchedRoute,
+	}
+	for path, pattern := range patterns {
+		method := http.MethodGet
+		if strings.Contains(path, "vouchers") {
+			method = http.MethodPut
+		}
+		requests := routeRequests(t, method, pattern)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "http://localhost:8080"+path, nil))
+		assert.Equal(t, requests+1, routeRequests(t, method, pattern), path)
+	}
+}
+
+func TestMetricsToken_Failed(t *testing.T) {
+	t.Setenv("METRICS_TOKEN", "scrape-secret")
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/metrics", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, 401, recorder.Code)
+
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8080/metrics", nil)
+	request.Header.Add("Authorization", "Bearer scrape-secret")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, 200, recorder.Code)
+}
+
+func TestMetricsExternalCall_Failed(t *testing.T) {
+	_, server := newS3StandIn("minio")
+	defer server.Close()
+	imageRepository := repository.NewS3ImageRepository(repository.S3Config{
+		Endpoint:  server.URL,
+		Bucket:    "weplant",
+		AccessKey: "someone-else",
+		SecretKey: "minio-secret",
+	})
+	failed := testutil.ToFloat64(pkg.ExternalCalls.WithLabelValues("s3", "upload", "error"))
+
+	_, err := imageRepository.UploadImage(context.Background(), "1650000000-bayam", strings.NewReader("bayam image"))
+	assert.NotNil(t, err)
+	assert.Equal(t, failed+1, testutil.ToFloat64(pkg.ExternalCalls.WithLabelValues("s3", "upload", "error")))
+}
+
+func TestMetricsMongoCommand_Success(t *testing.T) {
+	clientOptions := options.Client()
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock).ClientOptions(clientOptions))
+	defer mt.Close()
+
+	mt.Run("repository method", func(mt *mtest.T) {
+		// a client over the mock deployment of mt, timed by the monitor of the app
+		client, err := mongo.NewClient(&options.ClientOptions{Deployment: clientOptions.Deployment, Monitor: app.RepositoryMonitor()})
+		if !assert.Nil(t, err) {
+			return
+		}
+		productRepository := repository.NewProductRepository(client.Database(mt.DB.Name()).Collection(mt.Coll.Name()))
+		finds := histogramCount(t, pkg.MongoOperationDuration.WithLabelValues("ProductRepository", "FindById", "find", "success"))
+		searches := histogramCount(t, pkg.MongoOperationDuration.WithLabelValues("ProductRepository", "Search", "aggregate", "error"))
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, mt.DB.Name()+"."+mt.Coll.Name(), mtest.FirstBatch, bson.D{{"_id", primitive.NewObjectID()}}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad query"}),
+		)
+		_, err = productRepository.FindById(context.Background(), primitive.NewObjectID().Hex())
+		assert.Nil(t, err)
+		_, err = productRepository.Search(context.Background(), repository.ProductSearch{}, repository.Page{Limit: 10})
+		assert.NotNil(t, err)
+
+		assert.Equal(t, finds+1, histogramCount(t, pkg.MongoOperationDuration.WithLabelValues("ProductRepository", "FindById", "find", "success")))
+		assert.Equal(t, searches+1, histogramCount(t, pkg.MongoOperationDuration.WithLabelValues("ProductRepository", "Search", "aggregate", "error")))
+	})
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+	"weplant-backend/app"
+)
+
+// migrationUpdatesTest collects the update statements sent to the order collection
+func migrationUpdatesTest(mt *mtest.T) []bson.Raw {
+	var updates []bson.Raw
+	for _, e := range mt.GetAllStartedEvents() {
+		if e.CommandName != "update" || e.Command.Lookup("update").StringValue() != "order" {
+			continue
+		}
+		values, _ := e.Command.Lookup("updates").Array().Values()
+		for _, value := range values {
+			updates = append(updates, value.Document())
+		}
+	}
+	return updates
+}
+
+// migrationCountFiltersTest collects the filters the manage orders were matched with
+func migrationCountFiltersTest(mt *mtest.T) []bson.Raw {
+	var filters []bson.Raw
+	for _, e := range mt.GetAllStartedEvents() {
+		if e.CommandName != "aggregate" || e.Command.Lookup("aggregate").StringValue() != "order" {
+			continue
+		}
+		stages, _ := e.Command.Lookup("pipeline").Array().Values()
+		filters = append(filters, stages[0].Document().Lookup("$match").Document())
+	}
+	return filters
+}
+
+// Test Migrate Embedded Orders
+
+func TestMigrateEmbeddedOrdersTwice_Success(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	customerId, merchantId := primitive.NewObjectID(), primitive.NewObjectID()
+	customerOrderId, manageOrderId := primitive.NewObjectID(), primitive.NewObjectID()
+	productId := primitive.NewObjectID().Hex()
+	order := func(id primitive.ObjectID) bson.D {
+		return bson.D{{"_id", id}, {"created_at", 1650000000}, {"product_id", productId}, {"price", 10000}, {"quantity", 2}}
+	}
+	// the customer order is already in the order collection when the manage orders are matched
+	matched := mtest.CreateCursorResponse(0, "weplant-backend.order", mtest.FirstBatch, bson.D{{"_id", 1}, {"n", 1}})
+
+	mt.Run("first run", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "weplant-backend.customer", mtest.FirstBatch, bson.D{{"_id", customerId}, {"orders", bson.A{order(customerOrderId)}}}),
+			mtest.CreateCursorResponse(0, "weplant-backend.product", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateCursorResponse(0, "weplant-backend.merchant", mtest.FirstBatch, bson.D{{"_id", merchantId}, {"orders", bson.A{order(manageOrderId)}}}),
+			matched,
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		err := app.MigrateEmbeddedOrders(mtest.Background, mt.DB)
+		assert.Nil(t, err)
+
+		updates := migrationUpdatesTest(mt)
+		filters := migrationCountFiltersTest(mt)
+		if !assert.Equal(t, 2, len(updates)) || !assert.Equal(t, 1, len(filters)) {
+			return
+		}
+		// the customer order carries the key the manage order is matched on
+		customerOrder := updates[0].Lookup("u").Document()
+		assert.Equal(t, customerOrderId, updates[0].Lookup("q", "_id").ObjectID())
+		assert.Equal(t, productId, customerOrder.Lookup("items").Array().Index(0).Value().Document().Lookup("product_id").StringValue())
+		assert.Equal(t, customerOrder.Lookup("legacy_key").StringValue(), filters[0].Lookup("legacy_key").StringValue())
+		assert.Equal(t, manageOrderId, filters[0].Lookup("_id", "$ne").ObjectID())
+
+		// the product was gone, the manage order lends its merchant instead of being inserted
+		assert.Equal(t, merchantId.Hex(), updates[1].Lookup("u", "$set", "merchant_id").StringValue())
+		assert.Equal(t, false, updates[1].Lookup("upsert").Type == bson.TypeBoolean && updates[1].Lookup("upsert").Boolean())
+	})
+
+	// the first run failed before the merchants were cleaned up, their manage orders are still embedded
+	mt.Run("second run", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "weplant-backend.customer", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, "weplant-backend.merchant", mtest.FirstBatch, bson.D{{"_id", merchantId}, {"orders", bson.A{order(manageOrderId)}}}),
+			matched,
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+		err := app.MigrateEmbeddedOrders(mtest.Background, mt.DB)
+		assert.Nil(t, err)
+
+		// nothing is upserted again
+		for _, update := range migrationUpdatesTest(mt) {
+			upsert := update.Lookup("upsert")
+			assert.False(t, upsert.Type == bson.TypeBoolean && upsert.Boolean())
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+const adminKeyTest = "admin-key-test"
+
+// Test Create Payout
+
+func TestCreatePayout_Success(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("WithdrawBalance", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.PayoutRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Payout, nil)
+	config.LedgerRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.LedgerEntry, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.PayoutCreateRequest{
+		Amount:        500000,
+		BankName:      "BCA",
+		AccountNumber: "1234567890",
+		AccountName:   "ilham",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/payouts", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.MerchantRepository.Mock.AssertCalled(t, "WithdrawBalance", mock.Anything, schema_mock.Merchant.Id.Hex(), int64(500000))
+	config.LedgerRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(entry schema.LedgerEntry) bool {
+		return entry.Type == schema.LedgerTypeWithdrawal && entry.Amount == -500000 && entry.ReferenceId == schema_mock.Payout.Id.Hex()
+	}))
+	config.MerchantRepository.Mock.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything)
+}
+
+func TestCreatePayoutInsufficientBalance_Failed(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.MerchantRepository.Mock.On("WithdrawBalance", mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrInsufficientBalance)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.PayoutCreateRequest{
+		Amount:        5000000,
+		BankName:      "BCA",
+		AccountNumber: "1234567890",
+		AccountName:   "ilham",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/payouts", bytes.NewReader(body))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.PayoutRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// Test Find Payout Merchant
+
+func TestFindPayoutMerchant_Success(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.PayoutRepository.Mock.On("FindByMerchantId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Payout{schema_mock.Payout}, repository.PageInfo{}, nil)
+	config.PayoutRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(1, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/payouts", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.PayoutRepository.Mock.AssertCalled(t, "FindByMerchantId", mock.Anything, schema_mock.Merchant.Id.Hex(), repository.Page{Limit: 10})
+}
+
+// Test Update Status Payout
+
+func TestUpdateStatusPayout_Success(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", adminKeyTest)
+	config.PayoutRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Payout, nil)
+	config.PayoutRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.PayoutUpdateStatusRequest{
+		Status: schema.PayoutStatusProcessing,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/payouts/"+schema_mock.Payout.Id.Hex(), bytes.NewReader(body))
+	request.Header.Add("X-Admin-Key", adminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.PayoutRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, schema_mock.Payout.Id.Hex(), schema.PayoutStatusRequested, mock.MatchedBy(func(history schema.PayoutHistory) bool {
+		return history.Status == schema.PayoutStatusProcessing
+	}))
+	config.LedgerRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateStatusPayoutRejected_Success(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", adminKeyTest)
+	config.PayoutRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Payout, nil)
+	config.PayoutRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.LedgerRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.LedgerEntry, nil)
+	config.MerchantRepository.Mock.On("UpdateBalance", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.PayoutUpdateStatusRequest{
+		Status: schema.PayoutStatusRejected,
+		Note:   "nomor rekening salah",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/payouts/"+schema_mock.Payout.Id.Hex(), bytes.NewReader(body))
+	request.Header.Add("X-Admin-Key", adminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.LedgerRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(entry schema.LedgerEntry) bool {
+		return entry.Type == schema.LedgerTypeWithdrawalReversal && entry.Amount == schema_mock.Payout.Amount
+	}))
+	config.MerchantRepository.Mock.AssertCalled(t, "UpdateBalance", mock.Anything, schema.Merchant{
+		Id:      schema_mock.Merchant.Id,
+		Balance: schema_mock.Payout.Amount,
+	})
+}
+
+func TestUpdateStatusPayoutTransition_Failed(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", adminKeyTest)
+	payout := schema_mock.Payout
+	payout.Status = schema.PayoutStatusPaid
+	config.PayoutRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(payout, nil)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.PayoutUpdateStatusRequest{
+		Status: schema.PayoutStatusRejected,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/payouts/"+payout.Id.Hex(), bytes.NewReader(body))
+	request.Header.Add("X-Admin-Key", adminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+	config.PayoutRepository.Mock.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateStatusPayoutAdminKey_Failed(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", adminKeyTest)
+
+	router := config.SetupRouterTest()
+
+	body, err := json.Marshal(web.PayoutUpdateStatusRequest{
+		Status: schema.PayoutStatusPaid,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "http://localhost:8080/api/v1/payouts/"+schema_mock.Payout.Id.Hex(), bytes.NewReader(body))
+	request.Header.Add("X-Admin-Key", "wrong-key")
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
@@ -2,29 +2,33 @@ package test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"weplant-backend/helper"
 	"weplant-backend/integration_test/config"
 	"weplant-backend/integration_test/schema_mock"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
+	"weplant-backend/pkg"
+	"weplant-backend/repository"
 )
 
 // Test FindById Product
 
 func TestFindByIdProduct_Success(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
 
 	router := config.SetupRouterTest()
 
@@ -38,9 +42,9 @@ func TestFindByIdProduct_Success(t *testing.T) {
 }
 
 func TestFindByIdProduct_Failed(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
 
 	router := config.SetupRouterTest()
 
@@ -56,12 +60,17 @@ func TestFindByIdProduct_Failed(t *testing.T) {
 // Test FindAll Product
 
 func TestFindAllProduct_Success(t *testing.T) {
-	config.ProductRepository.Mock.On("FindAll", context.Background(), mock.Anything, mock.Anything).Return([]schema.Product{
-		schema_mock.Product,
-		schema_mock.Product,
-		schema_mock.Product,
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{schema_mock.Category}, nil)
+	config.ProductRepository.Mock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(repository.ProductSearchResult{
+		Products:  []schema.Product{schema_mock.Product, schema_mock.Product, schema_mock.Product},
+		TotalData: 3,
+		Categories: []repository.ProductCategoryCount{
+			{CategoryId: schema_mock.Category.Id.Hex(), Count: 3},
+		},
+		Prices: []repository.ProductPriceCount{
+			{Min: 25000, Count: 3},
+		},
 	}, nil)
-	config.ProductRepository.Mock.On("CountDocuments", context.Background()).Return(3, nil)
 
 	router := config.SetupRouterTest()
 
@@ -72,11 +81,23 @@ func TestFindAllProduct_Success(t *testing.T) {
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "Search", mock.Anything, mock.MatchedBy(func(search repository.ProductSearch) bool {
+		return search.Sort == repository.ProductSortNewest && len(search.CategoryIds) == 0 && len(search.MerchantIds) == 0
+	}), repository.Page{Limit: 10})
+
+	body, _ := io.ReadAll(response.Body)
+	var webResponse struct {
+		Data web.ProductFindAllResponse `json:"data"`
+	}
+	json.Unmarshal(body, &webResponse)
+	assert.Equal(t, 3, webResponse.Data.Metadata.TotalData)
+	assert.Equal(t, schema_mock.Category.Name, webResponse.Data.Facets.Categories[0].Name)
+	assert.Equal(t, web.ProductPriceFacetResponse{Min: 25000, Max: 50000, Count: 3}, webResponse.Data.Facets.Prices[0])
 }
 
 func TestFindAllProduct_Failed(t *testing.T) {
-	config.ProductRepository.Mock.On("FindAll", context.Background(), mock.Anything, mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("CountDocuments", context.Background()).Return(0, nil)
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{schema_mock.Category}, nil)
+	config.ProductRepository.Mock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("error"))
 
 	router := config.SetupRouterTest()
 
@@ -89,24 +110,123 @@ func TestFindAllProduct_Failed(t *testing.T) {
 	assert.Equal(t, 500, response.StatusCode)
 }
 
+// Test Search Product
+
+func TestSearchProductFilter_Success(t *testing.T) {
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{schema_mock.Category, schema_mock.SubCategory}, nil)
+	config.MerchantRepository.Mock.On("FindByLocation", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Merchant{schema_mock.Merchant}, nil)
+	config.ProductRepository.Mock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(repository.ProductSearchResult{
+		Products:  []schema.Product{schema_mock.Product},
+		TotalData: 1,
+	}, nil)
+
+	router := config.SetupRouterTest()
+
+	cursor := helper.EncodeCursor(helper.Cursor{Value: schema_mock.Product.Price, Id: schema_mock.Product.Id.Hex()})
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/products?search=bayam&category="+schema_mock.Category.Id.Hex()+"&minPrice=1000&maxPrice=50000&inStock=true&city=bandung&sort=price_asc&cursor="+cursor+"&perPage=5", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.MerchantRepository.Mock.AssertCalled(t, "FindByLocation", mock.Anything, "bandung", "")
+	config.ProductRepository.Mock.AssertCalled(t, "Search", mock.Anything, repository.ProductSearch{
+		Search:      "bayam",
+		CategoryIds: []string{schema_mock.Category.Id.Hex(), schema_mock.SubCategory.Id.Hex()},
+		MerchantIds: []string{schema_mock.Merchant.Id.Hex()},
+		MinPrice:    1000,
+		MaxPrice:    50000,
+		InStock:     true,
+		Sort:        repository.ProductSortPriceAsc,
+	}, repository.Page{Cursor: cursor, Limit: 5})
+}
+
+func TestSearchProductRelevance_Success(t *testing.T) {
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{schema_mock.Category}, nil)
+	config.ProductRepository.Mock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(repository.ProductSearchResult{}, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/products?search=bayam", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "Search", mock.Anything, mock.MatchedBy(func(search repository.ProductSearch) bool {
+		return search.Search == "bayam" && search.Sort == repository.ProductSortRelevance
+	}), repository.Page{Limit: 10})
+}
+
+func TestSearchProductLocationEmpty_Success(t *testing.T) {
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{schema_mock.Category}, nil)
+	config.MerchantRepository.Mock.On("FindByLocation", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Merchant{}, nil)
+	config.ProductRepository.Mock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(repository.ProductSearchResult{}, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/products?province=papua", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchProductPriceRange_Failed(t *testing.T) {
+	config.CategoryRepository.Mock.On("FindAll", mock.Anything).Return([]schema.Category{schema_mock.Category}, nil)
+	config.ProductRepository.Mock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(repository.ProductSearchResult{}, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/products?minPrice=50000&maxPrice=1000&sort=cheapest", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.ProductRepository.Mock.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchProductInvalidCursor_Failed(t *testing.T) {
+	config.ProductRepository.Mock.On("Search", mock.Anything, mock.Anything, mock.Anything).Return(repository.ProductSearchResult{}, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "https://test.com/api/v1/products?cursor=not-a-cursor", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.ProductRepository.Mock.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test Create Product
 
 func TestCreateProduct_Success(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
-	config.ProductRepository.Mock.On("Create", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
-	writer.WriteField("merchant_id", primitive.NewObjectID().Hex())
+	writer.WriteField("merchant_id", schema_mock.Merchant.Id.Hex())
 	writer.WriteField("name", "toko ilham")
 	writer.WriteField("description", "lorem dolor sit amet.")
 	writer.WriteField("price", "50000")
 	writer.WriteField("stock", "20")
+	writer.WriteField("weight", "500")
 
 	// main image
 	file, err := writer.CreateFormFile("image", "elonmusk.jpg")
@@ -148,21 +268,22 @@ func TestCreateProduct_Success(t *testing.T) {
 }
 
 func TestCreateProduct_Failed(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
-	config.ProductRepository.Mock.On("Create", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
-	writer.WriteField("merchant_id", primitive.NewObjectID().Hex())
+	writer.WriteField("merchant_id", schema_mock.Merchant.Id.Hex())
 	writer.WriteField("name", "toko ilham")
 	writer.WriteField("description", "lorem dolor sit amet.")
 	writer.WriteField("price", "50000")
 	writer.WriteField("stock", "20")
+	writer.WriteField("weight", "500")
 
 	// main image
 	file, err := writer.CreateFormFile("image", "elonmusk.jpg")
@@ -204,21 +325,22 @@ func TestCreateProduct_Failed(t *testing.T) {
 }
 
 func TestCreateProduct_FailedUnauthorized(t *testing.T) {
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
-	config.ProductRepository.Mock.On("Create", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
-	writer.WriteField("merchant_id", primitive.NewObjectID().Hex())
+	writer.WriteField("merchant_id", schema_mock.Merchant.Id.Hex())
 	writer.WriteField("name", "toko ilham")
 	writer.WriteField("description", "lorem dolor sit amet.")
 	writer.WriteField("price", "50000")
 	writer.WriteField("stock", "20")
+	writer.WriteField("weight", "500")
 
 	// main image
 	file, err := writer.CreateFormFile("image", "elonmusk.jpg")
@@ -261,9 +383,9 @@ func TestCreateProduct_FailedUnauthorized(t *testing.T) {
 // Test Update Product
 
 func TestUpdateProduct_Success(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
-	config.ProductRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
@@ -274,6 +396,7 @@ func TestUpdateProduct_Success(t *testing.T) {
 		Description: "bunga anggrek terbaik se indonesia",
 		Price:       90000,
 		Stock:       24,
+		Weight:      750,
 		Categories: []web.ProductCategoryUpdateRequest{
 			{
 				CategoryId: primitive.NewObjectID().Hex(),
@@ -300,9 +423,9 @@ func TestUpdateProduct_Success(t *testing.T) {
 }
 
 func TestUpdateProduct_Failed(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
-	config.ProductRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
@@ -313,6 +436,7 @@ func TestUpdateProduct_Failed(t *testing.T) {
 		Description: "bunga anggrek terbaik se indonesia",
 		Price:       90000,
 		Stock:       24,
+		Weight:      750,
 		Categories: []web.ProductCategoryUpdateRequest{
 			{
 				CategoryId: primitive.NewObjectID().Hex(),
@@ -339,9 +463,9 @@ func TestUpdateProduct_Failed(t *testing.T) {
 }
 
 func TestUpdateProduct_FailedUnauthorized(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CategoryRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Category, nil)
-	config.ProductRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CategoryRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Category, nil)
+	config.ProductRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
 
 	router := config.SetupRouterTest()
 
@@ -352,6 +476,7 @@ func TestUpdateProduct_FailedUnauthorized(t *testing.T) {
 		Description: "bunga anggrek terbaik se indonesia",
 		Price:       90000,
 		Stock:       24,
+		Weight:      750,
 		Categories: []web.ProductCategoryUpdateRequest{
 			{
 				CategoryId: primitive.NewObjectID().Hex(),
@@ -379,10 +504,10 @@ func TestUpdateProduct_FailedUnauthorized(t *testing.T) {
 // Test UpdateMainImage Product
 
 func TestUpdateMainImageProduct_Success(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.ProductRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ProductRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -408,10 +533,10 @@ func TestUpdateMainImageProduct_Success(t *testing.T) {
 }
 
 func TestUpdateMainImageProduct_Failed(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.ProductRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ProductRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -437,10 +562,10 @@ func TestUpdateMainImageProduct_Failed(t *testing.T) {
 }
 
 func TestUpdateMainImageProduct_FailedUnauthorized(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.ProductRepository.Mock.On("Update", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ProductRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -467,9 +592,9 @@ func TestUpdateMainImageProduct_FailedUnauthorized(t *testing.T) {
 // Test PushImageIntoImages Product
 
 func TestPushImageIntoImagesProduct_Success(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.ProductRepository.Mock.On("PushImageIntoImages", context.Background(), mock.Anything, mock.Anything).Return([]schema.Image{
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ProductRepository.Mock.On("PushImageIntoImages", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Image{
 		schema_mock.Image,
 		schema_mock.Image,
 	}, nil)
@@ -507,9 +632,9 @@ func TestPushImageIntoImagesProduct_Success(t *testing.T) {
 }
 
 func TestPushImageIntoImagesProduct_Failed(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.ProductRepository.Mock.On("PushImageIntoImages", context.Background(), mock.Anything, mock.Anything).Return([]schema.Image{
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ProductRepository.Mock.On("PushImageIntoImages", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Image{
 		schema_mock.Image,
 		schema_mock.Image,
 	}, nil)
@@ -547,9 +672,9 @@ func TestPushImageIntoImagesProduct_Failed(t *testing.T) {
 }
 
 func TestPushImageIntoImagesProduct_FailedUnauthorized(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CloudinaryRepository.Mock.On("UploadImage", context.Background(), mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
-	config.ProductRepository.Mock.On("PushImageIntoImages", context.Background(), mock.Anything, mock.Anything).Return([]schema.Image{
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ProductRepository.Mock.On("PushImageIntoImages", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Image{
 		schema_mock.Image,
 		schema_mock.Image,
 	}, nil)
@@ -588,9 +713,9 @@ func TestPushImageIntoImagesProduct_FailedUnauthorized(t *testing.T) {
 // Test PullImageFromImages Product
 
 func TestPullImageFromImagesProduct_Success(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.ProductRepository.Mock.On("PullImageFromImages", context.Background(), mock.Anything, mock.Anything).Return(schema_mock.Image, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("PullImageFromImages", mock.Anything, mock.Anything, mock.Anything).Return(schema_mock.Image, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -606,9 +731,9 @@ func TestPullImageFromImagesProduct_Success(t *testing.T) {
 }
 
 func TestPullImageFromImagesProduct_Failed(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("PullImageFromImages", context.Background(), mock.Anything, mock.Anything).Return(schema_mock.Image, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("PullImageFromImages", mock.Anything, mock.Anything, mock.Anything).Return(schema_mock.Image, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -625,9 +750,9 @@ func TestPullImageFromImagesProduct_Failed(t *testing.T) {
 }
 
 func TestPullImageFromImagesProduct_FailedUnauthorized(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.ProductRepository.Mock.On("PullImageFromImages", context.Background(), mock.Anything, mock.Anything).Return(schema_mock.Image, nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("PullImageFromImages", mock.Anything, mock.Anything, mock.Anything).Return(schema_mock.Image, nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -644,10 +769,11 @@ func TestPullImageFromImagesProduct_FailedUnauthorized(t *testing.T) {
 // Test Delete Product
 
 func TestDeleteProduct_Success(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PullProductFromAllCart", context.Background(), mock.Anything).Return(nil)
-	config.ProductRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PullProductFromAllCart", mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("PullProductFromAllWishlist", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -660,13 +786,15 @@ func TestDeleteProduct_Success(t *testing.T) {
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.CustomerRepository.Mock.AssertCalled(t, "PullProductFromAllWishlist", mock.Anything, schema_mock.Product.Id.Hex())
 }
 
 func TestDeleteProduct_Failed(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.CustomerRepository.Mock.On("PullProductFromAllCart", context.Background(), mock.Anything).Return(nil)
-	config.ProductRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.CustomerRepository.Mock.On("PullProductFromAllCart", mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("PullProductFromAllWishlist", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -682,10 +810,11 @@ func TestDeleteProduct_Failed(t *testing.T) {
 }
 
 func TestDeleteProduct_FailedUnauthorized(t *testing.T) {
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("PullProductFromAllCart", context.Background(), mock.Anything).Return(nil)
-	config.ProductRepository.Mock.On("Delete", context.Background(), mock.Anything).Return(nil)
-	config.CloudinaryRepository.Mock.On("DeleteImage", context.Background(), mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PullProductFromAllCart", mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("PullProductFromAllWishlist", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
@@ -698,3 +827,203 @@ func TestDeleteProduct_FailedUnauthorized(t *testing.T) {
 
 	assert.Equal(t, 401, response.StatusCode)
 }
+
+func TestDeleteProduct_FailedForbidden(t *testing.T) {
+	product := schema_mock.Product
+	product.MerchantId = primitive.NewObjectID().Hex()
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.CustomerRepository.Mock.On("PullProductFromAllCart", mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("PullProductFromAllWishlist", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/products/4", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 403, response.StatusCode)
+}
+
+// Test Create Variant Product
+
+func TestCreateVariantProduct_Success(t *testing.T) {
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.ProductRepository.Mock.On("PushVariant", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants", strings.NewReader(`{"sku":"MLT-20CM","options":[{"name":"pot","value":"20cm"}],"price":45000,"stock":5,"image_id":"`+schema_mock.Image.Id.Hex()+`"}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	body, _ := io.ReadAll(response.Body)
+	var webResponse struct {
+		Data web.ProductVariantResponse `json:"data"`
+	}
+	err := json.Unmarshal(body, &webResponse)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, 45000, webResponse.Data.Price)
+	assert.Equal(t, schema_mock.Image.URL, webResponse.Data.Image.URL)
+	config.ProductRepository.Mock.AssertCalled(t, "PushVariant", mock.Anything, schema_mock.Product.Id.Hex(), mock.MatchedBy(func(variant schema.ProductVariant) bool {
+		return variant.Sku == "MLT-20CM" && variant.Stock == 5 && variant.Options[0].Value == "20cm"
+	}))
+}
+
+func TestCreateVariantProductDuplicate_Failed(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants", strings.NewReader(`{"sku":"MLT-10CM-B","options":[{"name":"Pot","value":"10CM"}],"price":45000,"stock":5}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+	config.ProductRepository.Mock.AssertNotCalled(t, "PushVariant", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateVariantProductOptions_Failed(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants", strings.NewReader(`{"sku":"MLT-PLANTER","options":[{"name":"planter","value":"with"}],"price":45000,"stock":5}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.ProductRepository.Mock.AssertNotCalled(t, "PushVariant", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateVariantProduct_FailedUnauthorized(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants", strings.NewReader(`{"sku":"MLT-20CM","options":[{"name":"pot","value":"20cm"}],"price":45000,"stock":5}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+// Test Update Variant Product
+
+func TestUpdateVariantProduct_Success(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.ProductRepository.Mock.On("UpdateVariant", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants/"+schema_mock.ProductVariant.Id.Hex(), strings.NewReader(`{"sku":"MLT-10CM","options":[{"name":"pot","value":"10cm"}],"price":32000,"stock":12}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "UpdateVariant", mock.Anything, schema_mock.Product.Id.Hex(), schema_mock.ProductVariant, mock.MatchedBy(func(variant schema.ProductVariant) bool {
+		return variant.Id == schema_mock.ProductVariant.Id && variant.Price == 32000 && variant.Stock == 12
+	}))
+}
+
+func TestUpdateVariantProductSoldOut_Success(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.ProductRepository.Mock.On("UpdateVariant", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	stockOuts := testutil.ToFloat64(pkg.StockOuts.WithLabelValues(pkg.StockOutSetToZero))
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants/"+schema_mock.ProductVariant.Id.Hex(), strings.NewReader(`{"sku":"MLT-10CM","options":[{"name":"pot","value":"10cm"}],"price":32000,"stock":0}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, stockOuts+1, testutil.ToFloat64(pkg.StockOuts.WithLabelValues(pkg.StockOutSetToZero)))
+}
+
+func TestUpdateVariantProductChanged_Failed(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.ProductRepository.Mock.On("UpdateVariant", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrVariantChanged)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants/"+schema_mock.ProductVariant.Id.Hex(), strings.NewReader(`{"sku":"MLT-10CM","options":[{"name":"pot","value":"10cm"}],"price":32000,"stock":12}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+}
+
+func TestUpdateVariantProduct_Failed(t *testing.T) {
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants/"+primitive.NewObjectID().Hex(), strings.NewReader(`{"sku":"MLT-10CM","options":[{"name":"pot","value":"10cm"}],"price":32000,"stock":12}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+// Test Delete Variant Product
+
+func TestDeleteVariantProduct_Success(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.ProductRepository.Mock.On("PullVariant", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("PullVariantFromAllCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "https://test.com/api/v1/products/"+schema_mock.Product.Id.Hex()+"/variants/"+schema_mock.ProductVariant.Id.Hex(), nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "PullVariant", mock.Anything, schema_mock.Product.Id.Hex(), schema_mock.ProductVariant)
+	config.CustomerRepository.Mock.AssertCalled(t, "PullVariantFromAllCart", mock.Anything, schema_mock.Product.Id.Hex(), schema_mock.ProductVariant.Id.Hex())
+}
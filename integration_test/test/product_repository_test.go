@@ -0,0 +1,151 @@
+package test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"testing"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/repository"
+)
+
+// pushVariantUpdatesTest collects the update statements PushVariant sent
+func pushVariantUpdatesTest(mt *mtest.T) []bson.Raw {
+	var updates []bson.Raw
+	for _, e := range mt.GetAllStartedEvents() {
+		if e.CommandName != "update" {
+			continue
+		}
+		values, _ := e.Command.Lookup("updates").Array().Values()
+		for _, value := range values {
+			updates = append(updates, value.Document())
+		}
+	}
+	return updates
+}
+
+// Test Push Variant
+
+func TestPushVariantFirst_Success(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	variant := schema.ProductVariant{Id: primitive.NewObjectID(), Sku: "BAYAM-M", Price: 20000, Stock: 3}
+
+	mt.Run("first variant", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		productRepository := repository.NewProductRepository(mt.Coll)
+
+		err := productRepository.PushVariant(mtest.Background, primitive.NewObjectID().Hex(), variant)
+		assert.Nil(t, err)
+
+		// the stock the product had without variants is replaced by the stock of the variant
+		updates := pushVariantUpdatesTest(mt)
+		if !assert.Equal(t, 1, len(updates)) {
+			return
+		}
+		assert.Equal(t, int32(3), updates[0].Lookup("u", "$set", "stock").Int32())
+		assert.Equal(t, bson.TypeEmbeddedDocument, updates[0].Lookup("q", "variants.0").Type)
+	})
+
+	mt.Run("next variant", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		productRepository := repository.NewProductRepository(mt.Coll)
+
+		err := productRepository.PushVariant(mtest.Background, primitive.NewObjectID().Hex(), variant)
+		assert.Nil(t, err)
+
+		updates := pushVariantUpdatesTest(mt)
+		if !assert.Equal(t, 2, len(updates)) {
+			return
+		}
+		assert.Equal(t, int32(3), updates[1].Lookup("u", "$inc", "stock").Int32())
+	})
+}
+
+// searchPipelinesTest collects the pipelines Search sent
+func searchPipelinesTest(mt *mtest.T) []bson.Raw {
+	var pipelines []bson.Raw
+	for _, e := range mt.GetAllStartedEvents() {
+		if e.CommandName == "aggregate" {
+			pipelines = append(pipelines, e.Command.Lookup("pipeline").Array())
+		}
+	}
+	return pipelines
+}
+
+// Test Search Product
+
+func TestSearchProductCursor_Success(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	search := repository.ProductSearch{MerchantIds: []string{primitive.NewObjectID().Hex()}, Sort: repository.ProductSortBestSelling}
+	sold, unsold := primitive.NewObjectID(), primitive.NewObjectID()
+	var next string
+
+	mt.Run("first page", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "weplant-backend.product", mtest.FirstBatch,
+				bson.D{{"_id", sold}, {"name", "Bayam"}, {"sold", 5}},
+				bson.D{{"_id", unsold}, {"name", "Kangkung"}},
+			),
+			mtest.CreateCursorResponse(0, "weplant-backend.product", mtest.FirstBatch, bson.D{
+				{"total", bson.A{bson.D{{"count", 3}}}},
+				{"categories", bson.A{}},
+				{"prices", bson.A{}},
+			}),
+		)
+		productRepository := repository.NewProductRepository(mt.Coll)
+
+		result, err := productRepository.Search(mtest.Background, search, repository.Page{Limit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, result.TotalData)
+		if !assert.Equal(t, 1, len(result.Products)) {
+			return
+		}
+		assert.Equal(t, sold, result.Products[0].Id)
+		next = result.PageInfo.NextCursor
+
+		// the products are sorted on the indexed field right after the filter
+		pipelines := searchPipelinesTest(mt)
+		if !assert.Equal(t, 2, len(pipelines)) {
+			return
+		}
+		assert.Equal(t, int32(-1), pipelines[0].Index(1).Value().Document().Lookup("$sort", "sold").Int32())
+	})
+
+	mt.Run("next page", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "weplant-backend.product", mtest.FirstBatch,
+			bson.D{{"_id", unsold}, {"name", "Kangkung"}},
+		))
+		productRepository := repository.NewProductRepository(mt.Coll)
+
+		result, err := productRepository.Search(mtest.Background, search, repository.Page{Cursor: next, Limit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, result.TotalData)
+		if !assert.Equal(t, 1, len(result.Products)) {
+			return
+		}
+
+		// the cursor is matched before sorting and nothing else is aggregated
+		pipelines := searchPipelinesTest(mt)
+		if !assert.Equal(t, 1, len(pipelines)) {
+			return
+		}
+		match := pipelines[0].Index(0).Value().Document().Lookup("$match")
+		assert.Equal(t, bson.TypeArray, match.Document().Lookup("$or").Type)
+		assert.Equal(t, "$sort", pipelines[0].Index(1).Value().Document().Index(0).Key())
+
+		// the product that was never sold has no sold, its cursor still pages after it
+		cursor, err := helper.DecodeCursor(result.PageInfo.PrevCursor)
+		assert.Nil(t, err)
+		assert.Nil(t, cursor.Value)
+		assert.Equal(t, unsold.Hex(), cursor.Id)
+	})
+}
@@ -0,0 +1,142 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"weplant-backend/exception"
+	"weplant-backend/helper"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/middleware"
+	"weplant-backend/model/web"
+)
+
+// setupRequestLoggerTest serves a handler that logs through the request scoped logger, it panics with err when given
+func setupRequestLoggerTest(logs *bytes.Buffer, err interface{}) http.Handler {
+	router := httprouter.New()
+	router.PanicHandler = middleware.PanicHandler(exception.ErrorHandler)
+	router.GET("/api/v1/hello", middleware.AuthMiddleware(func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		helper.GetLogger(request.Context()).Info("hello from the service", "answer", 42)
+		if err != nil {
+			panic(err)
+		}
+		helper.WriteToResponseBody(writer, web.WebResponse{Code: 200, Status: "OK"})
+	}, "customer"))
+	return middleware.RequestLoggerMiddleware(router, helper.NewLogger(logs))
+}
+
+func readLogsTest(t *testing.T, logs *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var fields map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &fields))
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+// Test Request Logger
+
+func TestRequestLoggerId_Success(t *testing.T) {
+	var logs bytes.Buffer
+	handler := setupRequestLoggerTest(&logs, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/hello", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, 200, recorder.Code)
+	requestId := recorder.Header().Get("X-Request-ID")
+	assert.Equal(t, 32, len(requestId))
+
+	lines := readLogsTest(t, &logs)
+	if !assert.Equal(t, 2, len(lines)) {
+		return
+	}
+	assert.Equal(t, "hello from the service", lines[0]["message"])
+	assert.Equal(t, requestId, lines[0]["request_id"])
+	assert.Equal(t, float64(42), lines[0]["answer"])
+
+	assert.Equal(t, "info", lines[1]["level"])
+	assert.Equal(t, "request", lines[1]["message"])
+	assert.Equal(t, requestId, lines[1]["request_id"])
+	assert.Equal(t, "GET", lines[1]["method"])
+	assert.Equal(t, "/api/v1/hello", lines[1]["path"])
+	assert.Equal(t, float64(200), lines[1]["status"])
+	assert.Equal(t, schema_mock.Customer.Id.Hex(), lines[1]["user_id"])
+	assert.Equal(t, "customer", lines[1]["role"])
+	assert.NotNil(t, lines[1]["latency_ms"])
+	assert.Nil(t, lines[1]["panic"])
+}
+
+func TestRequestLoggerPropagateId_Success(t *testing.T) {
+	var logs bytes.Buffer
+	handler := setupRequestLoggerTest(&logs, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/hello", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	request.Header.Add("X-Request-ID", "from-the-gateway-1")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, "from-the-gateway-1", recorder.Header().Get("X-Request-ID"))
+	for _, line := range readLogsTest(t, &logs) {
+		assert.Equal(t, "from-the-gateway-1", line["request_id"])
+	}
+
+	// an id that could break the log lines is replaced
+	logs.Reset()
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/hello", nil)
+	request.Header.Add("X-Request-ID", "forged\" id")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, 32, len(recorder.Header().Get("X-Request-ID")))
+}
+
+func TestRequestLoggerPanic_Failed(t *testing.T) {
+	var logs bytes.Buffer
+	handler := setupRequestLoggerTest(&logs, errors.New("database is gone"))
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/hello", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	assert.Equal(t, 500, recorder.Code)
+	lines := readLogsTest(t, &logs)
+	line := lines[len(lines)-1]
+	assert.Equal(t, "error", line["level"])
+	assert.Equal(t, float64(500), line["status"])
+	assert.Equal(t, "database is gone", line["panic"])
+	assert.Contains(t, line["stack"], "request_logger_test.go")
+}
+
+func TestRequestLoggerUnauthorized_Failed(t *testing.T) {
+	var logs bytes.Buffer
+	handler := setupRequestLoggerTest(&logs, nil)
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/hello", nil)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	// handled errors are logged without their stack
+	assert.Equal(t, 401, recorder.Code)
+	lines := readLogsTest(t, &logs)
+	if !assert.Equal(t, 1, len(lines)) {
+		return
+	}
+	assert.Equal(t, "info", lines[0]["level"])
+	assert.Equal(t, float64(401), lines[0]["status"])
+	assert.Equal(t, "auth header is invalid", lines[0]["panic"])
+	assert.Nil(t, lines[0]["stack"])
+	assert.Nil(t, lines[0]["user_id"])
+}
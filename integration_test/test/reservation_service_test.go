@@ -0,0 +1,88 @@
+package test
+
+import (
+	"context"
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"testing"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/repository_mock"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/service"
+)
+
+// Test Release Expired Reservation
+
+func TestReleaseExpiredReservation_Success(t *testing.T) {
+	config.ReservationRepository.Mock.On("FindExpired", mock.Anything, mock.Anything).Return([]schema.Reservation{schema_mock.Reservation}, nil)
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           schema_mock.Reservation.TransactionId,
+		PaymentType:       "gopay",
+		TransactionStatus: "expire",
+	}, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+
+	reservationService := service.NewReservationService(&config.ReservationRepository, &config.ProductRepository, &config.MidtransRepository, &config.SessionRepository)
+	released, err := reservationService.ReleaseExpired(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, released)
+	config.ReservationRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, schema_mock.Reservation.Id.Hex(), schema.ReservationStatusActive, schema.ReservationStatusReleased)
+	config.ProductRepository.Mock.AssertNumberOfCalls(t, "UpdateQuantity", len(schema_mock.Reservation.Products))
+}
+
+func TestReleaseExpiredReservationPaid_Success(t *testing.T) {
+	config.ReservationRepository.Mock.On("FindExpired", mock.Anything, mock.Anything).Return([]schema.Reservation{schema_mock.Reservation}, nil)
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           schema_mock.Reservation.TransactionId,
+		PaymentType:       "gopay",
+		TransactionStatus: "settlement",
+	}, nil)
+
+	reservationService := service.NewReservationService(&config.ReservationRepository, &config.ProductRepository, &config.MidtransRepository, &config.SessionRepository)
+	released, err := reservationService.ReleaseExpired(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, released)
+	config.ReservationRepository.Mock.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	config.ProductRepository.Mock.AssertNotCalled(t, "UpdateQuantity", mock.Anything, mock.Anything)
+}
+
+func TestReleaseExpiredReservationCancel_Failed(t *testing.T) {
+	// midtrans fails to cancel the first reservation, the second one is still released
+	pending := schema_mock.Reservation
+	pending.Id = primitive.NewObjectID()
+	pending.TransactionId = primitive.NewObjectID().Hex()
+	reservationRepository := &repository_mock.ReservationRepositoryMock{Mock: mock.Mock{}}
+	midtransRepository := &repository_mock.MidtransRepositoryMock{Mock: mock.Mock{}}
+	reservationRepository.Mock.On("FindExpired", mock.Anything, mock.Anything).Return([]schema.Reservation{pending, schema_mock.Reservation}, nil)
+	reservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	midtransRepository.Mock.On("CheckTransaction", pending.TransactionId).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           pending.TransactionId,
+		TransactionStatus: "pending",
+	}, nil)
+	midtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           schema_mock.Reservation.TransactionId,
+		TransactionStatus: "expire",
+	}, nil)
+	midtransRepository.Mock.On("CancelTransaction", mock.Anything).Return(nil, &midtrans.Error{
+		Message:    "midtrans is down",
+		StatusCode: 500,
+	})
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+
+	reservationService := service.NewReservationService(reservationRepository, &config.ProductRepository, midtransRepository, &config.SessionRepository)
+	released, err := reservationService.ReleaseExpired(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, released)
+	reservationRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, schema_mock.Reservation.Id.Hex(), schema.ReservationStatusActive, schema.ReservationStatusReleased)
+	reservationRepository.Mock.AssertNotCalled(t, "UpdateStatus", mock.Anything, pending.Id.Hex(), mock.Anything, mock.Anything)
+}
@@ -0,0 +1,341 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"weplant-backend/helper"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+func completedOrderTest() schema.Order {
+	order := schema_mock.Order
+	order.Status = schema.OrderStatusCompleted
+	order.History = append([]schema.OrderHistory{}, order.History...)
+	order.History = append(order.History, schema.OrderHistory{
+		CreatedAt: order.UpdatedAt,
+		Status:    schema.OrderStatusCompleted,
+	})
+	return order
+}
+
+func reviewCreateRequestTest(t *testing.T, order schema.Order, rating string) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("order_id", order.Id.Hex())
+	writer.WriteField("product_id", schema_mock.OrderItem.ProductId)
+	writer.WriteField("rating", rating)
+	writer.WriteField("text", "tanamannya sehat, pengiriman cepat")
+
+	file, err := writer.CreateFormFile("images", "elonmusk.jpg")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	file.Write(uploadImageTest)
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/reviews", body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	return request
+}
+
+// Test Create Review
+
+func TestCreateReview_Success(t *testing.T) {
+	order := completedOrderTest()
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ReviewRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Review, nil)
+	config.ProductRepository.Mock.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := reviewCreateRequestTest(t, order, "4")
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ReviewRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(review schema.Review) bool {
+		return review.OrderId == order.Id.Hex() && review.MerchantId == order.MerchantId && review.Rating == 4 && len(review.Images) == 1
+	}))
+	config.ProductRepository.Mock.AssertCalled(t, "UpdateRating", mock.Anything, schema_mock.Review.ProductId, 1, 4)
+	config.MerchantRepository.Mock.AssertCalled(t, "UpdateRating", mock.Anything, schema_mock.Review.MerchantId, 1, 4)
+}
+
+func TestCreateReviewNotCompleted_Failed(t *testing.T) {
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ReviewRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Review, nil)
+
+	router := config.SetupRouterTest()
+
+	request := reviewCreateRequestTest(t, schema_mock.Order, "4")
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 403, response.StatusCode)
+	config.ImageRepository.Mock.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything)
+	config.ReviewRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateReviewDuplicate_Failed(t *testing.T) {
+	order := completedOrderTest()
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ImageRepository.Mock.On("UploadImage", mock.Anything, mock.Anything, mock.Anything).Return("http://image.com/elonmusk.jpg", nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
+	config.ReviewRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(nil, mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: 11000, Message: "duplicate key"}},
+	})
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := reviewCreateRequestTest(t, order, "4")
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+	config.ImageRepository.Mock.AssertCalled(t, "DeleteImage", mock.Anything, mock.Anything)
+}
+
+func TestCreateReviewRating_Failed(t *testing.T) {
+	order := completedOrderTest()
+	config.OrderRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(order, nil)
+
+	router := config.SetupRouterTest()
+
+	request := reviewCreateRequestTest(t, order, "6")
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+func TestCreateReviewTooLarge_Failed(t *testing.T) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	writer.WriteField("order_id", schema_mock.Order.Id.Hex())
+	// one more photo than a review takes, each as large as a photo may be
+	for i := 0; i <= helper.ReviewImageLimit.MaxCount; i++ {
+		file, err := writer.CreateFormFile("images", "elonmusk.jpg")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		file.Write(make([]byte, helper.ReviewImageLimit.MaxSize))
+	}
+	writer.Close()
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/reviews", body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	responseBody, _ := io.ReadAll(response.Body)
+	var webResponse web.WebResponse
+	json.Unmarshal(responseBody, &webResponse)
+
+	assert.Equal(t, 413, response.StatusCode)
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", webResponse.ErrorCode)
+}
+
+func TestCreateReview_FailedUnauthorized(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/reviews", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+// Test Find Review By Product
+
+func TestFindReviewByProductId_Success(t *testing.T) {
+	product := schema_mock.Product
+	product.RatingCount = 3
+	product.RatingTotal = 13
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.ReviewRepository.Mock.On("FindByProductId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Review{schema_mock.Review}, repository.PageInfo{PrevCursor: "prev-cursor"}, nil)
+	config.ReviewRepository.Mock.On("CountByProductId", mock.Anything, mock.Anything).Return(3, nil)
+
+	router := config.SetupRouterTest()
+
+	cursor := helper.EncodeCursor(helper.Cursor{Value: schema_mock.Review.CreatedAt, Id: schema_mock.Review.Id.Hex()})
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/products/"+product.Id.Hex()+"/reviews?cursor="+cursor+"&perPage=1", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	body, _ := io.ReadAll(response.Body)
+	var responseBody struct {
+		Data web.ReviewFindAllResponse `json:"data"`
+	}
+	json.Unmarshal(body, &responseBody)
+
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, 4.3, responseBody.Data.Rating.Average)
+	assert.Equal(t, 3, responseBody.Data.Rating.Count)
+	assert.Equal(t, 1, len(responseBody.Data.Reviews))
+	assert.Equal(t, "prev-cursor", responseBody.Data.Metadata.PrevCursor)
+	config.ReviewRepository.Mock.AssertCalled(t, "FindByProductId", mock.Anything, product.Id.Hex(), repository.Page{Cursor: cursor, Limit: 1})
+}
+
+// Test Update Review
+
+func TestUpdateReview_Success(t *testing.T) {
+	config.ReviewRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Review, nil)
+	config.ReviewRepository.Mock.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := strings.NewReader(`{"rating": 2, "text": "daunnya layu"}`)
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/reviews/"+schema_mock.Review.Id.Hex(), requestBody)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ReviewRepository.Mock.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(review schema.Review) bool {
+		return review.Rating == 2 && review.Text == "daunnya layu"
+	}), 4)
+	config.ProductRepository.Mock.AssertCalled(t, "UpdateRating", mock.Anything, schema_mock.Review.ProductId, 0, -2)
+	config.MerchantRepository.Mock.AssertCalled(t, "UpdateRating", mock.Anything, schema_mock.Review.MerchantId, 0, -2)
+}
+
+func TestUpdateReviewChanged_Failed(t *testing.T) {
+	config.ReviewRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Review, nil)
+	config.ReviewRepository.Mock.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrReviewChanged)
+	config.ProductRepository.Mock.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := strings.NewReader(`{"rating": 2, "text": "daunnya layu"}`)
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/reviews/"+schema_mock.Review.Id.Hex(), requestBody)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 409, response.StatusCode)
+	config.ProductRepository.Mock.AssertNotCalled(t, "UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Delete Review
+
+func TestDeleteReview_Success(t *testing.T) {
+	review := schema_mock.Review
+	review.Images = []schema.Image{schema_mock.Image}
+	config.ReviewRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(review, nil)
+	config.ReviewRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.MerchantRepository.Mock.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ImageRepository.Mock.On("DeleteImage", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/reviews/"+review.Id.Hex(), nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "UpdateRating", mock.Anything, review.ProductId, -1, -4)
+	config.MerchantRepository.Mock.AssertCalled(t, "UpdateRating", mock.Anything, review.MerchantId, -1, -4)
+	config.ImageRepository.Mock.AssertCalled(t, "DeleteImage", mock.Anything, schema_mock.Image.FileName)
+}
+
+// Test Reply Review
+
+func TestReplyReview_Success(t *testing.T) {
+	config.ReviewRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Review, nil)
+	config.ReviewRepository.Mock.On("UpdateReply", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := strings.NewReader(`{"text": "terima kasih sudah berbelanja"}`)
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/reviews/"+schema_mock.Review.Id.Hex()+"/reply", requestBody)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ReviewRepository.Mock.AssertCalled(t, "UpdateReply", mock.Anything, schema_mock.Review.Id.Hex(), mock.MatchedBy(func(reply schema.ReviewReply) bool {
+		return reply.Text == "terima kasih sudah berbelanja"
+	}))
+}
+
+func TestReplyReviewOtherMerchant_Failed(t *testing.T) {
+	review := schema_mock.Review
+	review.MerchantId = schema_mock.Product.Id.Hex()
+	config.ReviewRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(review, nil)
+	config.ReviewRepository.Mock.On("UpdateReply", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := strings.NewReader(`{"text": "terima kasih sudah berbelanja"}`)
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/reviews/"+review.Id.Hex()+"/reply", requestBody)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 404, response.StatusCode)
+	config.ReviewRepository.Mock.AssertNotCalled(t, "UpdateReply", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// Test Quote Shipping
+
+func TestQuoteShipping_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/shipping-rates?province=Jawa%20Barat", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	body, _ := io.ReadAll(response.Body)
+	var webResponse struct {
+		Data web.ShippingQuoteResponse `json:"data"`
+	}
+	err := json.Unmarshal(body, &webResponse)
+
+	weight := schema_mock.Product.Weight * schema_mock.CartProduct.Quantity * len(schema_mock.Customer.Carts)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 200, response.StatusCode)
+	if !assert.Equal(t, 1, len(webResponse.Data.Packages)) {
+		return
+	}
+	assert.Equal(t, weight, webResponse.Data.Packages[0].Weight)
+	assert.Equal(t, len(schema_mock.ShippingRates), len(webResponse.Data.Packages[0].Rates))
+	config.ShippingRateProvider.Mock.AssertCalled(t, "Rates", mock.Anything, repository.ShippingRateRequest{
+		OriginProvince:      schema_mock.Merchant.Address.Province,
+		DestinationProvince: "Jawa Barat",
+		Weight:              weight,
+	})
+}
+
+func TestQuoteShippingUnavailable_Failed(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(nil, repository.ErrShippingRouteUnavailable)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/shipping-rates?province=Atlantis", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+func TestQuoteShipping_FailedUnauthorized(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/shipping-rates?province=Jawa%20Barat", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+// Test Table Shipping Rate Provider
+
+func TestTableShippingRateProvider_Success(t *testing.T) {
+	provider := repository.NewTableShippingRateProvider([]repository.TableShippingRate{
+		{Courier: "jne", Service: "REG", PerKg: [3]int{9000, 16000, 32000}, Etd: [3]string{"1-2", "2-3", "3-6"}},
+	})
+
+	rates, err := provider.Rates(context.Background(), repository.ShippingRateRequest{
+		OriginProvince:      "Jawa Tengah",
+		DestinationProvince: "jawa tengah",
+		Weight:              300,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 9000, rates[0].Fee)
+
+	rates, err = provider.Rates(context.Background(), repository.ShippingRateRequest{
+		OriginProvince:      "Jawa Tengah",
+		DestinationProvince: "Jakarta",
+		Weight:              1500,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 32000, rates[0].Fee)
+
+	rates, err = provider.Rates(context.Background(), repository.ShippingRateRequest{
+		OriginProvince:      "Jawa Tengah",
+		DestinationProvince: "Papua",
+		Weight:              1000,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 32000, rates[0].Fee)
+	assert.Equal(t, "3-6", rates[0].Etd)
+
+	_, err = provider.Rates(context.Background(), repository.ShippingRateRequest{
+		OriginProvince:      "Jawa Tengah",
+		DestinationProvince: "Atlantis",
+		Weight:              1000,
+	})
+	assert.ErrorIs(t, err, repository.ErrShippingRouteUnavailable)
+}
@@ -2,14 +2,15 @@ package test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -19,15 +20,18 @@ import (
 	"weplant-backend/integration_test/schema_mock"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
+	"weplant-backend/pkg"
+	"weplant-backend/repository"
 )
 
 // Test Create Transaction
 
 func TestCreateTransaction_Success(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CustomerRepository.Mock.On("PullProductFromCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	checkouts := testutil.ToFloat64(pkg.CheckoutsCreated.WithLabelValues(schema.PaymentMethodQris))
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	config.MidtransRepository.Mock.On("CreateTransaction", mock.Anything).Return(&coreapi.ChargeResponse{
 		TransactionID: primitive.NewObjectID().Hex(),
 		OrderID:       primitive.NewObjectID().Hex(),
@@ -35,28 +39,280 @@ func TestCreateTransaction_Success(t *testing.T) {
 		PaymentType:   "qris",
 		Actions: []coreapi.Action{
 			{
-				Name:   "qr-code",
+				Name:   "generate-qr-code",
 				Method: "GET",
 				URL:    "http://google.com",
 			},
 		},
 	}, nil)
-	config.CustomerRepository.Mock.On("CreateTransaction", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ReservationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
 
 	router := config.SetupRouterTest()
 
-	requestBody := web.AddressCreateRequest{
-		Address:    "sudimoro",
-		City:       "kudus",
-		Province:   "jawa tengah",
-		PostalCode: "679234",
+	requestBody := struct {
+		web.AddressCreateRequest
+		web.TransactionShippingRequest
+	}{
+		web.AddressCreateRequest{
+			Address:    "sudimoro",
+			City:       "kudus",
+			Province:   "jawa tengah",
+			PostalCode: "59312",
+		},
+		web.TransactionShippingRequest{
+			Courier: "jne",
+			Service: "REG",
+		},
 	}
 	data, err := json.Marshal(requestBody)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/23", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.MidtransRepository.Mock.AssertCalled(t, "CreateTransaction", mock.MatchedBy(func(charge coreapi.ChargeReq) bool {
+		return charge.PaymentType == coreapi.PaymentTypeQris
+	}))
+	config.MidtransRepository.Mock.AssertCalled(t, "CreateTransaction", mock.MatchedBy(func(charge coreapi.ChargeReq) bool {
+		items := *charge.Items
+		shipping := items[len(items)-1]
+		return shipping.ID == "shipping-"+schema_mock.Product.MerchantId && shipping.Price == 9000 && shipping.Name == "Ongkos kirim JNE REG"
+	}))
+	config.ShippingRateProvider.Mock.AssertCalled(t, "Rates", mock.Anything, repository.ShippingRateRequest{
+		OriginProvince:      schema_mock.Merchant.Address.Province,
+		DestinationProvince: "jawa tengah",
+		Weight:              schema_mock.Product.Weight * schema_mock.CartProduct.Quantity * len(schema_mock.Customer.Carts),
+	})
+	config.TransactionRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(transaction schema.Transaction) bool {
+		return len(transaction.Orders) == 1 &&
+			transaction.Orders[0].MerchantId == schema_mock.Product.MerchantId &&
+			transaction.Orders[0].Courier == "jne" &&
+			transaction.Orders[0].Service == "REG" &&
+			transaction.Orders[0].ShippingFee == 9000
+	}))
+	assert.Equal(t, checkouts+1, testutil.ToFloat64(pkg.CheckoutsCreated.WithLabelValues(schema.PaymentMethodQris)))
+}
+
+func TestCreateTransactionBankTransfer_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.MidtransRepository.Mock.On("CreateTransaction", mock.Anything).Return(&coreapi.ChargeResponse{
+		TransactionID:     primitive.NewObjectID().Hex(),
+		OrderID:           primitive.NewObjectID().Hex(),
+		GrossAmount:       "200000",
+		PaymentType:       "bank_transfer",
+		TransactionStatus: "pending",
+		VaNumbers: []coreapi.VANumber{
+			{
+				Bank:     "bca",
+				VANumber: "12345678901",
+			},
+		},
+	}, nil)
+	config.TransactionRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ReservationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312","courier":"jne","service":"REG","payment_method":"bca_va"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	var webResponse struct {
+		Data web.TransactionCreateRequestResponse `json:"data"`
+	}
+	err := json.NewDecoder(response.Body).Decode(&webResponse)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, schema.PaymentMethodBcaVa, webResponse.Data.Payment.Method)
+	assert.Equal(t, "12345678901", webResponse.Data.Payment.BankTransfer.VANumber)
+	assert.Nil(t, webResponse.Data.Payment.Qris)
+	config.MidtransRepository.Mock.AssertCalled(t, "CreateTransaction", mock.MatchedBy(func(charge coreapi.ChargeReq) bool {
+		return charge.PaymentType == coreapi.PaymentTypeBankTransfer && charge.BankTransfer.Bank == midtrans.BankBca
+	}))
+	config.TransactionRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(transaction schema.Transaction) bool {
+		return transaction.Payment.Bank == "bca" && transaction.Payment.VANumber == "12345678901" && transaction.Payment.ExpiredAt > 0
+	}))
+}
+
+func TestCreateTransactionGopay_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.MidtransRepository.Mock.On("CreateTransaction", mock.Anything).Return(&coreapi.ChargeResponse{
+		TransactionID:     primitive.NewObjectID().Hex(),
+		OrderID:           primitive.NewObjectID().Hex(),
+		GrossAmount:       "200000",
+		PaymentType:       "gopay",
+		TransactionStatus: "pending",
+		Actions: []coreapi.Action{
+			{
+				Name:   "generate-qr-code",
+				Method: "GET",
+				URL:    "https://api.sandbox.midtrans.com/v2/gopay/qr-code",
+			},
+			{
+				Name:   "deeplink-redirect",
+				Method: "GET",
+				URL:    "https://simulator.sandbox.midtrans.com/gopay/ui/checkout",
+			},
+		},
+	}, nil)
+	config.TransactionRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ReservationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312","courier":"jne","service":"REG","payment_method":"gopay","callback_url":"https://weplant.id/orders"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	var webResponse struct {
+		Data web.TransactionCreateRequestResponse `json:"data"`
+	}
+	err := json.NewDecoder(response.Body).Decode(&webResponse)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, "https://simulator.sandbox.midtrans.com/gopay/ui/checkout", webResponse.Data.Payment.EWallet.Deeplink)
+	config.MidtransRepository.Mock.AssertCalled(t, "CreateTransaction", mock.MatchedBy(func(charge coreapi.ChargeReq) bool {
+		return charge.PaymentType == coreapi.PaymentTypeGopay && charge.Gopay.CallbackUrl == "https://weplant.id/orders"
+	}))
+}
+
+func TestCreateTransactionCardToken_Failed(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312","courier":"jne","service":"REG","payment_method":"credit_card"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.MidtransRepository.Mock.AssertNotCalled(t, "CreateTransaction", mock.Anything)
+}
+
+func TestCreateTransactionVariant_Success(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	customer := schema_mock.Customer
+	customer.Carts = []schema.CartProduct{
+		{
+			ProductId: schema_mock.Product.Id.Hex(),
+			VariantId: schema_mock.ProductVariant.Id.Hex(),
+			Quantity:  2,
+		},
+	}
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.MidtransRepository.Mock.On("CreateTransaction", mock.Anything).Return(&coreapi.ChargeResponse{
+		TransactionID: primitive.NewObjectID().Hex(),
+		OrderID:       primitive.NewObjectID().Hex(),
+		GrossAmount:   "79000",
+		PaymentType:   "qris",
+	}, nil)
+	config.TransactionRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveVariantStock", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ReservationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312","courier":"jne","service":"REG"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertCalled(t, "ReserveVariantStock", mock.Anything, schema_mock.Product.Id.Hex(), schema_mock.ProductVariant.Id.Hex(), 2)
+	config.ProductRepository.Mock.AssertNotCalled(t, "ReserveStock", mock.Anything, mock.Anything, mock.Anything)
+	config.MidtransRepository.Mock.AssertCalled(t, "CreateTransaction", mock.MatchedBy(func(charge coreapi.ChargeReq) bool {
+		items := *charge.Items
+		return items[0].Price == int64(schema_mock.ProductVariant.Price) && charge.TransactionDetails.GrossAmt == int64(schema_mock.ProductVariant.Price*2+9000)
+	}))
+	config.ReservationRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(reservation schema.Reservation) bool {
+		return reservation.Products[0].VariantId == schema_mock.ProductVariant.Id.Hex()
+	}))
+	config.TransactionRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(transaction schema.Transaction) bool {
+		return transaction.Products[0].VariantId == schema_mock.ProductVariant.Id.Hex() && transaction.Products[0].VariantName == "pot: 10cm"
+	}))
+}
+
+func TestCreateTransactionVoucher_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.VoucherRepository.Mock.On("FindByCode", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+	config.VoucherUsageRepository.Mock.On("CountByCustomerId", mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	config.MidtransRepository.Mock.On("CreateTransaction", mock.Anything).Return(&coreapi.ChargeResponse{
+		TransactionID: primitive.NewObjectID().Hex(),
+		OrderID:       primitive.NewObjectID().Hex(),
+		GrossAmount:   "859000",
+		PaymentType:   "qris",
+	}, nil)
+	config.TransactionRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ReservationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312","courier":"jne","service":"REG","voucher_code":"hemat10"}`))
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -66,36 +322,133 @@ func TestCreateTransaction_Success(t *testing.T) {
 
 	response := recorder.Result()
 
+	// 10% of the cart is capped at the max discount of the voucher
+	subtotal := schema_mock.Product.Price * schema_mock.CartProduct.Quantity * 2
+	discount := schema_mock.Voucher.MaxDiscount
 	assert.Equal(t, 200, response.StatusCode)
+	config.MidtransRepository.Mock.AssertCalled(t, "CreateTransaction", mock.MatchedBy(func(charge coreapi.ChargeReq) bool {
+		items := *charge.Items
+		last := items[len(items)-1]
+		var sum int64
+		for _, v := range items {
+			sum += v.Price * int64(v.Qty)
+		}
+		return last.Price == -int64(discount) && sum == charge.TransactionDetails.GrossAmt && charge.TransactionDetails.GrossAmt == int64(subtotal+9000-discount)
+	}))
+	config.TransactionRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(transaction schema.Transaction) bool {
+		return transaction.Voucher != nil && transaction.Voucher.Code == schema_mock.Voucher.Code && transaction.Voucher.Discount == discount &&
+			transaction.Orders[0].Discount == discount && transaction.Orders[0].Total == subtotal+9000-discount
+	}))
+	config.VoucherRepository.Mock.AssertNotCalled(t, "IncrementUsage", mock.Anything, mock.Anything)
+}
+
+func TestCreateTransactionVoucherMinSpend_Failed(t *testing.T) {
+	voucher := schema_mock.Voucher
+	voucher.MinSpend = 1000000
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.VoucherRepository.Mock.On("FindByCode", mock.Anything, mock.Anything).Return(voucher, nil)
+	config.VoucherUsageRepository.Mock.On("CountByCustomerId", mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312","courier":"jne","service":"REG","voucher_code":"hemat10"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.MidtransRepository.Mock.AssertNotCalled(t, "CreateTransaction", mock.Anything)
+}
+
+func TestCreateTransactionCourierUnavailable_Failed(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ShippingRateProvider.Mock.On("Rates", mock.Anything, mock.Anything).Return(schema_mock.ShippingRates, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312","courier":"pos","service":"Kilat Khusus"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.ReservationRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	config.MidtransRepository.Mock.AssertNotCalled(t, "CreateTransaction", mock.Anything)
+}
+
+func TestCreateTransactionShippingRequired_Failed(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), strings.NewReader(`{"address":"sudimoro","city":"kudus","province":"jawa tengah","postal_code":"59312"}`))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
 }
 
 func TestCreateTransaction_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CustomerRepository.Mock.On("PullProductFromCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	config.MidtransRepository.Mock.On("CreateTransaction", mock.Anything).Return(&coreapi.ChargeResponse{
 		TransactionID: primitive.NewObjectID().Hex(),
 		OrderID:       primitive.NewObjectID().Hex(),
 		GrossAmount:   "200000",
 		PaymentType:   "gopay",
 	}, nil)
-	config.CustomerRepository.Mock.On("CreateTransaction", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
 
 	router := config.SetupRouterTest()
 
-	requestBody := web.AddressCreateRequest{
-		Address:    "sudimoro",
-		City:       "kudus",
-		Province:   "jawa tengah",
-		PostalCode: "679234",
+	requestBody := struct {
+		web.AddressCreateRequest
+		web.TransactionShippingRequest
+	}{
+		web.AddressCreateRequest{
+			Address:    "sudimoro",
+			City:       "kudus",
+			Province:   "jawa tengah",
+			PostalCode: "59312",
+		},
+		web.TransactionShippingRequest{
+			Courier: "jne",
+			Service: "REG",
+		},
 	}
 	data, err := json.Marshal(requestBody)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/23", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -108,33 +461,92 @@ func TestCreateTransaction_Failed(t *testing.T) {
 	assert.Equal(t, 404, response.StatusCode)
 }
 
+func TestCreateTransactionOutOfStock_Failed(t *testing.T) {
+	stockOuts := testutil.ToFloat64(pkg.StockOuts.WithLabelValues(pkg.StockOutInsufficient))
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrInsufficientStock)
+
+	router := config.SetupRouterTest()
+
+	requestBody := struct {
+		web.AddressCreateRequest
+		web.TransactionShippingRequest
+	}{
+		web.AddressCreateRequest{
+			Address:    "sudimoro",
+			City:       "kudus",
+			Province:   "jawa tengah",
+			PostalCode: "59312",
+		},
+		web.TransactionShippingRequest{
+			Courier: "jne",
+			Service: "REG",
+		},
+	}
+	data, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(data))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	var webResponse web.WebResponse
+	err = json.NewDecoder(response.Body).Decode(&webResponse)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assert.Equal(t, 422, response.StatusCode)
+	assert.Equal(t, "OUT_OF_STOCK", webResponse.ErrorCode)
+	assert.Equal(t, stockOuts+1, testutil.ToFloat64(pkg.StockOuts.WithLabelValues(pkg.StockOutInsufficient)))
+}
+
 func TestCreateTransaction_FailedUnauthorized(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.MerchantRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Merchant, nil)
-	config.CustomerRepository.Mock.On("PullProductFromCart", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.CustomerRepository.Mock.On("PullProductFromCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	config.MidtransRepository.Mock.On("CreateTransaction", mock.Anything).Return(&coreapi.ChargeResponse{
 		TransactionID: primitive.NewObjectID().Hex(),
 		OrderID:       primitive.NewObjectID().Hex(),
 		GrossAmount:   "200000",
 		PaymentType:   "gopay",
 	}, nil)
-	config.CustomerRepository.Mock.On("CreateTransaction", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
 
 	router := config.SetupRouterTest()
 
-	requestBody := web.AddressCreateRequest{
-		Address:    "sudimoro",
-		City:       "kudus",
-		Province:   "jawa tengah",
-		PostalCode: "679234",
+	requestBody := struct {
+		web.AddressCreateRequest
+		web.TransactionShippingRequest
+	}{
+		web.AddressCreateRequest{
+			Address:    "sudimoro",
+			City:       "kudus",
+			Province:   "jawa tengah",
+			PostalCode: "59312",
+		},
+		web.TransactionShippingRequest{
+			Courier: "jne",
+			Service: "REG",
+		},
 	}
 	data, err := json.Marshal(requestBody)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
 
-	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/23", bytes.NewReader(data))
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex(), bytes.NewReader(data))
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -149,28 +561,34 @@ func TestCreateTransaction_FailedUnauthorized(t *testing.T) {
 // Test Cancel Transaction
 
 func TestCancelTransaction_Success(t *testing.T) {
-	customer := schema_mock.Customer
-	customer.Transactions = []schema.Transaction{
-		{
-			Id:          helper.ObjectIDFromHex("621d9b2b5256a3aa8353dc08"),
-			CreatedAt:   helper.GetTimeNow(),
-			UpdatedAt:   helper.GetTimeNow(),
-			PaymentType: "gopay",
-			Status:      "pending",
+	transaction := schema.Transaction{
+		Id:          helper.ObjectIDFromHex("621d9b2b5256a3aa8353dc08"),
+		CreatedAt:   helper.GetTimeNow(),
+		UpdatedAt:   helper.GetTimeNow(),
+		CustomerId:  schema_mock.Customer.Id.Hex(),
+		PaymentType: "gopay",
+		Status:      "pending",
+		Payment: &schema.TransactionPayment{
+			Method: schema.PaymentMethodGopay,
 			QRCode: "https://google.com",
-			Products: []schema.TransactionProduct{
-				schema_mock.TransactionProduct,
-				schema_mock.TransactionProduct,
-			},
-			Address: &schema_mock.Address,
 		},
+		Products: []schema.TransactionProduct{
+			schema_mock.TransactionProduct,
+			schema_mock.TransactionProduct,
+		},
+		Address: &schema_mock.Address,
 	}
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(customer, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(transaction, nil)
 	config.MidtransRepository.Mock.On("CancelTransaction", mock.Anything).Return(&coreapi.CancelResponse{}, nil)
+	config.ReservationRepository.Mock.On("FindByTransactionId", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/transactions/23/transactions/621d9b2b5256a3aa8353dc08", nil)
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex()+"/transactions/621d9b2b5256a3aa8353dc08", nil)
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -181,15 +599,39 @@ func TestCancelTransaction_Success(t *testing.T) {
 	response := recorder.Result()
 
 	assert.Equal(t, 200, response.StatusCode)
+	config.ReservationRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, schema_mock.Reservation.Id.Hex(), schema.ReservationStatusActive, schema.ReservationStatusReleased)
+	config.ProductRepository.Mock.AssertNumberOfCalls(t, "UpdateQuantity", len(schema_mock.Reservation.Products))
 }
 
 func TestCancelTransaction_Failed(t *testing.T) {
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(nil, errors.New("error"))
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(nil, errors.New("error"))
+	config.MidtransRepository.Mock.On("CancelTransaction", mock.Anything).Return(&coreapi.CancelResponse{}, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex()+"/transactions/621d9b2b5256a3aa8353dc08", nil)
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 404, response.StatusCode)
+}
+
+func TestCancelTransactionNotOwned_Failed(t *testing.T) {
+	transaction := schema_mock.Transaction
+	transaction.CustomerId = primitive.NewObjectID().Hex()
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(transaction, nil)
 	config.MidtransRepository.Mock.On("CancelTransaction", mock.Anything).Return(&coreapi.CancelResponse{}, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/transactions/23/transactions/621d9b2b5256a3aa8353dc08", nil)
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex()+"/transactions/"+transaction.Id.Hex(), nil)
 	request.Header.Add("Content-Type", "application/json")
 	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
 
@@ -200,31 +642,34 @@ func TestCancelTransaction_Failed(t *testing.T) {
 	response := recorder.Result()
 
 	assert.Equal(t, 404, response.StatusCode)
+	config.MidtransRepository.Mock.AssertNotCalled(t, "CancelTransaction", mock.Anything)
 }
 
 func TestCancelTransaction_FailedUnauthorized(t *testing.T) {
-	customer := schema_mock.Customer
-	customer.Transactions = []schema.Transaction{
-		{
-			Id:          helper.ObjectIDFromHex("621d9b2b5256a3aa8353dc08"),
-			CreatedAt:   helper.GetTimeNow(),
-			UpdatedAt:   helper.GetTimeNow(),
-			PaymentType: "gopay",
-			Status:      "pending",
+	transaction := schema.Transaction{
+		Id:          helper.ObjectIDFromHex("621d9b2b5256a3aa8353dc08"),
+		CreatedAt:   helper.GetTimeNow(),
+		UpdatedAt:   helper.GetTimeNow(),
+		CustomerId:  schema_mock.Customer.Id.Hex(),
+		PaymentType: "gopay",
+		Status:      "pending",
+		Payment: &schema.TransactionPayment{
+			Method: schema.PaymentMethodGopay,
 			QRCode: "https://google.com",
-			Products: []schema.TransactionProduct{
-				schema_mock.TransactionProduct,
-				schema_mock.TransactionProduct,
-			},
-			Address: &schema_mock.Address,
 		},
+		Products: []schema.TransactionProduct{
+			schema_mock.TransactionProduct,
+			schema_mock.TransactionProduct,
+		},
+		Address: &schema_mock.Address,
 	}
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(customer, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(transaction, nil)
 	config.MidtransRepository.Mock.On("CancelTransaction", mock.Anything).Return(&coreapi.CancelResponse{}, nil)
 
 	router := config.SetupRouterTest()
 
-	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/transactions/23/transactions/621d9b2b5256a3aa8353dc08", nil)
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/transactions/"+schema_mock.Customer.Id.Hex()+"/transactions/621d9b2b5256a3aa8353dc08", nil)
 	request.Header.Add("Content-Type", "application/json")
 
 	recorder := httptest.NewRecorder()
@@ -238,6 +683,26 @@ func TestCancelTransaction_FailedUnauthorized(t *testing.T) {
 
 // Test Callback Transaction
 
+func callbackRequestBody(t *testing.T, orderId string, statusCode string, grossAmount string, transactionStatus string, signatureKey string) string {
+	data, err := json.Marshal(coreapi.TransactionStatusResponse{
+		TransactionTime:   "2020-01-09 18:27:19",
+		TransactionStatus: transactionStatus,
+		TransactionID:     "57d5293c-e65f-4a29-95e4-5959c3fa335b",
+		StatusMessage:     "midtrans payment notification",
+		StatusCode:        statusCode,
+		SignatureKey:      signatureKey,
+		PaymentType:       "qris",
+		OrderID:           orderId,
+		GrossAmount:       grossAmount,
+		FraudStatus:       "accept",
+		Currency:          "IDR",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return string(data)
+}
+
 func TestCallbackTransaction_Success(t *testing.T) {
 	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
 		OrderID:           primitive.NewObjectID().Hex(),
@@ -245,36 +710,20 @@ func TestCallbackTransaction_Success(t *testing.T) {
 		TransactionID:     primitive.NewObjectID().Hex(),
 		TransactionStatus: "success",
 	}, nil)
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("CreateOrder", context.Background(), mock.Anything, mock.Anything).Return(nil)
-	config.MerchantRepository.Mock.On("PushProductToManageOrders", context.Background(), mock.Anything, mock.Anything).Return(nil)
-	config.ProductRepository.Mock.On("UpdateQuantity", context.Background(), mock.Anything).Return(nil)
-	config.CustomerRepository.Mock.On("DeleteTransaction", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.OrderRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("IncrementSold", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.PaymentNotificationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.PaymentNotification, nil)
+	config.ReservationRepository.Mock.On("FindByTransactionId", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	requestBody := `{
-  "transaction_time": "2020-01-09 18:27:19",
-  "transaction_status": "capture",
-  "transaction_id": "57d5293c-e65f-4a29-95e4-5959c3fa335b",
-  "status_message": "midtrans payment notification",
-  "status_code": "200",
-  "signature_key": "16d6f84b2fb0468e2a9cf99a8ac4e5d803d42180347aaa70cb2a7abb13b5c6130458ca9c71956a962c0827637cd3bc7d40b21a8ae9fab12c7c3efe351b18d00a",
-  "payment_type": "credit_card",
-  "order_id": "Postman-1578568851",
-  "merchant_id": "G141532850",
-  "masked_card": "481111-1114",
-  "gross_amount": "10000.00",
-  "fraud_status": "accept",
-  "eci": "05",
-  "currency": "IDR",
-  "channel_response_message": "Approved",
-  "channel_response_code": "00",
-  "card_type": "credit",
-  "bank": "bni",
-  "approval_code": "1578569243927"
-}`
+	requestBody := callbackRequestBody(t, "Postman-1578568851", "200", "10000.00", "capture", helper.MidtransSignatureKey("Postman-1578568851", "200", "10000.00", config.MidtransServerKey))
 
 	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
 	request.Header.Add("Content-Type", "application/json")
@@ -296,36 +745,106 @@ func TestCallbackTransaction_Failed(t *testing.T) {
 		Message:        "error yaaaa",
 		StatusCode:     500,
 	})
-	config.CustomerRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Customer, nil)
-	config.ProductRepository.Mock.On("FindById", context.Background(), mock.Anything).Return(schema_mock.Product, nil)
-	config.CustomerRepository.Mock.On("CreateOrder", context.Background(), mock.Anything, mock.Anything).Return(nil)
-	config.MerchantRepository.Mock.On("PushProductToManageOrders", context.Background(), mock.Anything, mock.Anything).Return(nil)
-	config.ProductRepository.Mock.On("UpdateQuantity", context.Background(), mock.Anything).Return(nil)
-	config.CustomerRepository.Mock.On("DeleteTransaction", context.Background(), mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.OrderRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("IncrementSold", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := callbackRequestBody(t, "Postman-1578568851", "200", "10000.00", "capture", helper.MidtransSignatureKey("Postman-1578568851", "200", "10000.00", config.MidtransServerKey))
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 502, response.StatusCode)
+}
+
+func TestCallbackTransactionTampered_Failed(t *testing.T) {
+	orderId := primitive.NewObjectID().Hex()
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "settlement",
+	}, nil)
+
+	router := config.SetupRouterTest()
+
+	// signed for 10000.00 but the amount was changed afterwards
+	signatureKey := helper.MidtransSignatureKey(orderId, "200", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "200", "1.00", "settlement", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 403, response.StatusCode)
+	config.MidtransRepository.Mock.AssertNotCalled(t, "CheckTransaction", orderId)
+}
+
+func TestCallbackTransactionReplayed_Failed(t *testing.T) {
+	orderId := primitive.NewObjectID().Hex()
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "settlement",
+	}, nil)
+
+	router := config.SetupRouterTest()
+
+	// signature captured from the pending notification, replayed on a settlement notification
+	signatureKey := helper.MidtransSignatureKey(orderId, "201", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "200", "10000.00", "settlement", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 403, response.StatusCode)
+	config.MidtransRepository.Mock.AssertNotCalled(t, "CheckTransaction", orderId)
+}
+
+func TestCallbackTransactionSettlement_Success(t *testing.T) {
+	orderId := schema_mock.Transaction.Id.Hex()
+	callbacks := testutil.ToFloat64(pkg.PaymentCallbacks.WithLabelValues("settlement"))
+	ordersPaid := testutil.ToFloat64(pkg.OrdersPaid)
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "settlement",
+		CustomField1:      schema_mock.Customer.Id.Hex(),
+	}, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
+	config.OrderRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("IncrementSold", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.PaymentNotificationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.PaymentNotification, nil)
+	config.ReservationRepository.Mock.On("FindByTransactionId", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	router := config.SetupRouterTest()
 
-	requestBody := `{
-  "transaction_time": "2020-01-09 18:27:19",
-  "transaction_status": "capture",
-  "transaction_id": "57d5293c-e65f-4a29-95e4-5959c3fa335b",
-  "status_message": "midtrans payment notification",
-  "status_code": "200",
-  "signature_key": "16d6f84b2fb0468e2a9cf99a8ac4e5d803d42180347aaa70cb2a7abb13b5c6130458ca9c71956a962c0827637cd3bc7d40b21a8ae9fab12c7c3efe351b18d00a",
-  "payment_type": "credit_card",
-  "order_id": "Postman-1578568851",
-  "merchant_id": "G141532850",
-  "masked_card": "481111-1114",
-  "gross_amount": "10000.00",
-  "fraud_status": "accept",
-  "eci": "05",
-  "currency": "IDR",
-  "channel_response_message": "Approved",
-  "channel_response_code": "00",
-  "card_type": "credit",
-  "bank": "bni",
-  "approval_code": "1578569243927"
-}`
+	signatureKey := helper.MidtransSignatureKey(orderId, "200", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "200", "10000.00", "settlement", signatureKey)
 
 	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
 	request.Header.Add("Content-Type", "application/json")
@@ -336,5 +855,264 @@ func TestCallbackTransaction_Failed(t *testing.T) {
 
 	response := recorder.Result()
 
-	assert.Equal(t, 500, response.StatusCode)
+	assert.Equal(t, 200, response.StatusCode)
+	config.ReservationRepository.Mock.AssertCalled(t, "UpdateStatus", mock.Anything, schema_mock.Reservation.Id.Hex(), schema.ReservationStatusActive, schema.ReservationStatusCommitted)
+	config.ProductRepository.Mock.AssertNotCalled(t, "UpdateQuantity", mock.Anything, mock.Anything)
+	config.ProductRepository.Mock.AssertCalled(t, "IncrementSold", mock.Anything, schema_mock.TransactionProductOtherMerchant.ProductId, schema_mock.TransactionProductOtherMerchant.Quantity)
+	config.TransactionRepository.Mock.AssertNumberOfCalls(t, "Delete", 1)
+	config.OrderRepository.Mock.AssertNumberOfCalls(t, "Create", len(schema_mock.Transaction.Orders))
+	config.OrderRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(order schema.Order) bool {
+		return order.MerchantId == schema_mock.TransactionProductOtherMerchant.MerchantId &&
+			len(order.Items) == 1 &&
+			order.Items[0].ProductId == schema_mock.TransactionProductOtherMerchant.ProductId &&
+			order.ShippingFee == 10000 &&
+			order.Total == 40000
+	}))
+	config.MerchantRepository.Mock.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything)
+	assert.Equal(t, callbacks+1, testutil.ToFloat64(pkg.PaymentCallbacks.WithLabelValues("settlement")))
+	assert.Equal(t, ordersPaid+float64(len(schema_mock.Transaction.Orders)), testutil.ToFloat64(pkg.OrdersPaid))
+}
+
+func TestCallbackTransactionOversold_Success(t *testing.T) {
+	// the reservation expired before the payment arrived and the stock of one product was sold in the meantime
+	orderId := schema_mock.Transaction.Id.Hex()
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "settlement",
+		CustomField1:      schema_mock.Customer.Id.Hex(),
+	}, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Transaction, nil)
+	config.OrderRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, schema_mock.TransactionProductOtherMerchant.ProductId, mock.Anything).Return(repository.ErrInsufficientStock)
+	config.ProductRepository.Mock.On("ReserveStock", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("IncrementSold", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.PaymentNotificationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.PaymentNotification, nil)
+	config.ReservationRepository.Mock.On("FindByTransactionId", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrReservationStatusChanged)
+
+	router := config.SetupRouterTest()
+
+	signatureKey := helper.MidtransSignatureKey(orderId, "200", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "200", "10000.00", "settlement", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.ProductRepository.Mock.AssertNotCalled(t, "UpdateQuantity", mock.Anything, mock.Anything)
+	config.ProductRepository.Mock.AssertCalled(t, "ReserveStock", mock.Anything, schema_mock.TransactionProduct.ProductId, schema_mock.TransactionProduct.Quantity)
+	config.OrderRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(order schema.Order) bool {
+		return order.MerchantId == schema_mock.TransactionProduct.MerchantId && order.Status == schema.OrderStatusPaid
+	}))
+	config.OrderRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(order schema.Order) bool {
+		return order.MerchantId == schema_mock.TransactionProductOtherMerchant.MerchantId &&
+			order.Status == schema.OrderStatusRefundRequested &&
+			len(order.History) == 2
+	}))
+}
+
+func TestCallbackTransactionVoucher_Success(t *testing.T) {
+	transaction := schema_mock.Transaction
+	transaction.Voucher = &schema_mock.TransactionVoucher
+	transaction.Orders = append([]schema.TransactionOrder{}, transaction.Orders...)
+	transaction.Orders[0].Discount = schema_mock.TransactionVoucher.Discount
+	transaction.Orders[0].Total -= schema_mock.TransactionVoucher.Discount
+	orderId := transaction.Id.Hex()
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "settlement",
+		CustomField1:      schema_mock.Customer.Id.Hex(),
+	}, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(transaction, nil)
+	config.OrderRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.VoucherRepository.Mock.On("IncrementUsage", mock.Anything, mock.Anything).Return(nil)
+	config.VoucherUsageRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema.VoucherUsage{}, nil)
+	config.ProductRepository.Mock.On("IncrementSold", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.PaymentNotificationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.PaymentNotification, nil)
+	config.ReservationRepository.Mock.On("FindByTransactionId", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	signatureKey := helper.MidtransSignatureKey(orderId, "200", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "200", "10000.00", "settlement", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.VoucherRepository.Mock.AssertCalled(t, "IncrementUsage", mock.Anything, schema_mock.Voucher.Id.Hex())
+	config.VoucherUsageRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(usage schema.VoucherUsage) bool {
+		return usage.VoucherId == schema_mock.Voucher.Id.Hex() && usage.CustomerId == schema_mock.Customer.Id.Hex() && usage.TransactionId == orderId
+	}))
+	config.OrderRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(order schema.Order) bool {
+		return order.MerchantId == schema_mock.Voucher.MerchantId && order.Discount == schema_mock.TransactionVoucher.Discount && order.Total == 82000
+	}))
+}
+
+func TestCallbackTransactionVoucherExpired_Success(t *testing.T) {
+	transaction := schema_mock.Transaction
+	transaction.Voucher = &schema_mock.TransactionVoucher
+	orderId := transaction.Id.Hex()
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "expire",
+		CustomField1:      schema_mock.Customer.Id.Hex(),
+	}, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(transaction, nil)
+	config.TransactionRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.PaymentNotificationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.PaymentNotification, nil)
+	config.ReservationRepository.Mock.On("FindByTransactionId", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("UpdateQuantity", mock.Anything, mock.Anything).Return(nil)
+	config.ProductRepository.Mock.On("IncrementSold", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.VoucherRepository.Mock.On("IncrementUsage", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	signatureKey := helper.MidtransSignatureKey(orderId, "407", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "407", "10000.00", "expire", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.VoucherRepository.Mock.AssertNotCalled(t, "IncrementUsage", mock.Anything, mock.Anything)
+}
+
+func TestCallbackTransactionLegacy_Success(t *testing.T) {
+	// transactions created before orders were split per merchant have no merchant on their products
+	transaction := schema_mock.Transaction
+	transaction.Orders = nil
+	transaction.Products = []schema.TransactionProduct{
+		{ProductId: schema_mock.TransactionProduct.ProductId, Price: 20000, Quantity: 4},
+		{ProductId: schema_mock.TransactionProductOtherMerchant.ProductId, Price: 15000, Quantity: 2},
+	}
+	orderId := transaction.Id.Hex()
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "settlement",
+		CustomField1:      schema_mock.Customer.Id.Hex(),
+	}, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.TransactionRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(transaction, nil)
+	config.OrderRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Order, nil)
+	config.ProductRepository.Mock.On("IncrementSold", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.TransactionRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.PaymentNotificationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.PaymentNotification, nil)
+	config.ReservationRepository.Mock.On("FindByTransactionId", mock.Anything, mock.Anything).Return(schema_mock.Reservation, nil)
+	config.ReservationRepository.Mock.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	signatureKey := helper.MidtransSignatureKey(orderId, "200", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "200", "10000.00", "settlement", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertNumberOfCalls(t, "Create", 1)
+	config.OrderRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(order schema.Order) bool {
+		return order.MerchantId == schema_mock.Product.MerchantId && len(order.Items) == 2 && order.ShippingFee == 0 && order.Total == 110000
+	}))
+}
+
+func TestCallbackTransactionDuplicate_Success(t *testing.T) {
+	orderId := schema_mock.Transaction.Id.Hex()
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "settlement",
+		CustomField1:      schema_mock.Customer.Id.Hex(),
+	}, nil)
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+	config.PaymentNotificationRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(nil, mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: 11000, Message: "duplicate key"}},
+	})
+
+	router := config.SetupRouterTest()
+
+	signatureKey := helper.MidtransSignatureKey(orderId, "200", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "200", "10000.00", "settlement", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.OrderRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	config.ProductRepository.Mock.AssertNotCalled(t, "UpdateQuantity", mock.Anything, mock.Anything)
+	config.TransactionRepository.Mock.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestCallbackTransactionPending_Success(t *testing.T) {
+	// bank transfers and e-wallets are notified as pending before they are paid
+	orderId := schema_mock.Transaction.Id.Hex()
+	callbacks := testutil.ToFloat64(pkg.PaymentCallbacks.WithLabelValues("pending"))
+	config.MidtransRepository.Mock.On("CheckTransaction", mock.Anything).Return(&coreapi.TransactionStatusResponse{
+		OrderID:           orderId,
+		TransactionStatus: "pending",
+		CustomField1:      schema_mock.Customer.Id.Hex(),
+	}, nil)
+
+	router := config.SetupRouterTest()
+
+	signatureKey := helper.MidtransSignatureKey(orderId, "201", "10000.00", config.MidtransServerKey)
+	requestBody := callbackRequestBody(t, orderId, "201", "10000.00", "pending", signatureKey)
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/callback", strings.NewReader(requestBody))
+	request.Header.Add("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, callbacks+1, testutil.ToFloat64(pkg.PaymentCallbacks.WithLabelValues("pending")))
+	config.PaymentNotificationRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	config.OrderRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	config.ReservationRepository.Mock.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	config.TransactionRepository.Mock.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
 }
@@ -0,0 +1,269 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"weplant-backend/helper"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+func voucherRequestBodyTest(code string, value int) string {
+	startAt := strconv.Itoa(helper.GetTimeNow())
+	endAt := strconv.Itoa(helper.GetTimeNow() + 86400)
+	return `{"code":"` + code + `","type":"percentage","value":` + strconv.Itoa(value) + `,"min_spend":100000,"max_discount":50000,"start_at":` + startAt + `,"end_at":` + endAt + `,"usage_limit":100,"per_customer_limit":1}`
+}
+
+// Test Create Voucher
+
+func TestCreateVoucherMerchant_Success(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.VoucherRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/vouchers", strings.NewReader(voucherRequestBodyTest("hemat10", 10)))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.VoucherRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(voucher schema.Voucher) bool {
+		return voucher.Code == "HEMAT10" && voucher.MerchantId == schema_mock.Merchant.Id.Hex() && voucher.UsedCount == 0
+	}))
+}
+
+func TestCreateVoucherPercentage_Failed(t *testing.T) {
+	config.MerchantRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Merchant, nil)
+	config.VoucherRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/vouchers", strings.NewReader(voucherRequestBodyTest("hemat150", 150)))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.VoucherRepository.Mock.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateVoucherPlatform_Success(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", adminKeyTest)
+	config.VoucherRepository.Mock.On("Create", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/vouchers", strings.NewReader(voucherRequestBodyTest("ramadhan", 10)))
+	request.Header.Add("X-Admin-Key", adminKeyTest)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.VoucherRepository.Mock.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(voucher schema.Voucher) bool {
+		return voucher.Code == "RAMADHAN" && voucher.MerchantId == ""
+	}))
+}
+
+func TestCreateVoucherPlatform_FailedUnauthorized(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", adminKeyTest)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/vouchers", strings.NewReader(voucherRequestBodyTest("ramadhan", 10)))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+// Test Find Voucher
+
+func TestFindVoucherMerchant_Success(t *testing.T) {
+	config.VoucherRepository.Mock.On("FindByMerchantId", mock.Anything, mock.Anything, mock.Anything).Return([]schema.Voucher{schema_mock.Voucher}, repository.PageInfo{}, nil)
+	config.VoucherRepository.Mock.On("CountByMerchantId", mock.Anything, mock.Anything).Return(1, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/vouchers", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.VoucherRepository.Mock.AssertCalled(t, "FindByMerchantId", mock.Anything, schema_mock.Merchant.Id.Hex(), repository.Page{Limit: 10})
+}
+
+// Test Update Voucher
+
+func TestUpdateVoucherMerchant_Success(t *testing.T) {
+	config.VoucherRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+	config.VoucherRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/vouchers/"+schema_mock.Voucher.Id.Hex(), strings.NewReader(voucherRequestBodyTest("hemat20", 20)))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.VoucherRepository.Mock.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(voucher schema.Voucher) bool {
+		return voucher.Code == "HEMAT20" && voucher.Value == 20 && voucher.UsedCount == schema_mock.Voucher.UsedCount
+	}))
+}
+
+func TestUpdateVoucherOtherMerchant_Failed(t *testing.T) {
+	voucher := schema_mock.Voucher
+	voucher.MerchantId = schema_mock.TransactionProductOtherMerchant.MerchantId
+	config.VoucherRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(voucher, nil)
+	config.VoucherRepository.Mock.On("Update", mock.Anything, mock.Anything).Return(voucher, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPut, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/vouchers/"+voucher.Id.Hex(), strings.NewReader(voucherRequestBodyTest("hemat20", 20)))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 404, response.StatusCode)
+	config.VoucherRepository.Mock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// Test Delete Voucher
+
+func TestDeleteVoucherMerchant_Success(t *testing.T) {
+	config.VoucherRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+	config.VoucherRepository.Mock.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/merchants/"+schema_mock.Merchant.Id.Hex()+"/vouchers/"+schema_mock.Voucher.Id.Hex(), nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("merchant"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.VoucherRepository.Mock.AssertCalled(t, "Delete", mock.Anything, schema_mock.Voucher.Id.Hex())
+}
+
+// Test Validate Voucher
+
+func TestValidateVoucherCart_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.VoucherRepository.Mock.On("FindByCode", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+	config.VoucherUsageRepository.Mock.On("CountByCustomerId", mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/vouchers/validate", strings.NewReader(`{"code":"hemat10"}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	body, _ := io.ReadAll(response.Body)
+	var responseBody struct {
+		Data web.VoucherValidateResponse `json:"data"`
+	}
+	json.Unmarshal(body, &responseBody)
+
+	// 10% of the cart is over the max discount
+	assert.Equal(t, 200, response.StatusCode)
+	assert.Equal(t, schema_mock.Product.Price*schema_mock.CartProduct.Quantity*2, responseBody.Data.Subtotal)
+	assert.Equal(t, schema_mock.Voucher.MaxDiscount, responseBody.Data.Discount)
+	config.VoucherRepository.Mock.AssertCalled(t, "FindByCode", mock.Anything, "HEMAT10")
+}
+
+func TestValidateVoucherCartExpired_Failed(t *testing.T) {
+	voucher := schema_mock.Voucher
+	voucher.EndAt = helper.GetTimeNow() - 60
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.VoucherRepository.Mock.On("FindByCode", mock.Anything, mock.Anything).Return(voucher, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/vouchers/validate", strings.NewReader(`{"code":"hemat10"}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+func TestValidateVoucherCartUsedUp_Failed(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.VoucherRepository.Mock.On("FindByCode", mock.Anything, mock.Anything).Return(schema_mock.Voucher, nil)
+	config.VoucherUsageRepository.Mock.On("CountByCustomerId", mock.Anything, mock.Anything, mock.Anything).Return(1, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/vouchers/validate", strings.NewReader(`{"code":"hemat10"}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.VoucherUsageRepository.Mock.AssertCalled(t, "CountByCustomerId", mock.Anything, schema_mock.Voucher.Id.Hex(), schema_mock.Customer.Id.Hex())
+}
+
+func TestValidateVoucherCartNotFound_Failed(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.VoucherRepository.Mock.On("FindByCode", mock.Anything, mock.Anything).Return(nil, errors.New("mongo: no documents in result"))
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/vouchers/validate", strings.NewReader(`{"code":"hemat10"}`))
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+}
@@ -0,0 +1,195 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"weplant-backend/integration_test/config"
+	"weplant-backend/integration_test/schema_mock"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+)
+
+func wishlistCustomerTest() schema.Customer {
+	customer := schema_mock.Customer
+	customer.Wishlist = []schema.WishlistProduct{
+		{
+			CreatedAt: customer.CreatedAt,
+			ProductId: schema_mock.Product.Id.Hex(),
+		},
+	}
+	return customer
+}
+
+// Test Find Wishlist
+
+func TestFindWishlistCustomer_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(wishlistCustomerTest(), nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	body, _ := io.ReadAll(response.Body)
+	var responseBody struct {
+		Data web.WishlistResponse `json:"data"`
+	}
+	json.Unmarshal(body, &responseBody)
+
+	assert.Equal(t, 200, response.StatusCode)
+	if !assert.Equal(t, 1, len(responseBody.Data.Products)) {
+		return
+	}
+	assert.Equal(t, schema_mock.Product.Price, responseBody.Data.Products[0].Price)
+	assert.Equal(t, schema_mock.Product.Stock, responseBody.Data.Products[0].Stock)
+}
+
+func TestFindWishlistCustomer_FailedUnauthorized(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist", nil)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+// Test Push Product To Wishlist
+
+func TestPushProductToWishlist_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PushProductToWishlist", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	requestBody := strings.NewReader(`{"product_id": "` + schema_mock.Product.Id.Hex() + `"}`)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist", requestBody)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.CustomerRepository.Mock.AssertCalled(t, "PushProductToWishlist", mock.Anything, schema_mock.Customer.Id.Hex(), mock.MatchedBy(func(product schema.WishlistProduct) bool {
+		return product.ProductId == schema_mock.Product.Id.Hex()
+	}))
+}
+
+func TestPushProductToWishlistValidation_Failed(t *testing.T) {
+	router := config.SetupRouterTest()
+
+	requestBody := strings.NewReader(`{"product_id": "4"}`)
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist", requestBody)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+}
+
+// Test Pull Product From Wishlist
+
+func TestPullProductFromWishlist_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(wishlistCustomerTest(), nil)
+	config.CustomerRepository.Mock.On("PullProductFromWishlist", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist/"+schema_mock.Product.Id.Hex(), nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.CustomerRepository.Mock.AssertCalled(t, "PullProductFromWishlist", mock.Anything, schema_mock.Customer.Id.Hex(), schema_mock.Product.Id.Hex())
+}
+
+func TestPullProductFromWishlistNotFound_Failed(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Customer, nil)
+	config.CustomerRepository.Mock.On("PullProductFromWishlist", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodDelete, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist/"+schema_mock.Product.Id.Hex(), nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 404, response.StatusCode)
+	config.CustomerRepository.Mock.AssertNotCalled(t, "PullProductFromWishlist", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Move Wishlist To Cart
+
+func TestMoveWishlistToCart_Success(t *testing.T) {
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(wishlistCustomerTest(), nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(schema_mock.Product, nil)
+	config.CustomerRepository.Mock.On("PushProductToCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.CustomerRepository.Mock.On("PullProductFromWishlist", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist/"+schema_mock.Product.Id.Hex()+"/cart", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 200, response.StatusCode)
+	config.CustomerRepository.Mock.AssertCalled(t, "PushProductToCart", mock.Anything, schema_mock.Customer.Id.Hex(), schema.CartProduct{
+		ProductId: schema_mock.Product.Id.Hex(),
+		Quantity:  1,
+	})
+	config.CustomerRepository.Mock.AssertCalled(t, "PullProductFromWishlist", mock.Anything, schema_mock.Customer.Id.Hex(), schema_mock.Product.Id.Hex())
+}
+
+func TestMoveWishlistToCartVariantRequired_Failed(t *testing.T) {
+	product := schema_mock.Product
+	product.Variants = []schema.ProductVariant{schema_mock.ProductVariant}
+	config.CustomerRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(wishlistCustomerTest(), nil)
+	config.ProductRepository.Mock.On("FindById", mock.Anything, mock.Anything).Return(product, nil)
+	config.CustomerRepository.Mock.On("PushProductToCart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	config.SessionRepository.Mock.On("WithTransaction", mock.Anything).Return(nil)
+
+	router := config.SetupRouterTest()
+
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:8080/api/v1/customers/"+schema_mock.Customer.Id.Hex()+"/wishlist/"+product.Id.Hex()+"/cart", nil)
+	request.Header.Add("Authorization", "Bearer "+config.GetJWTTokenTest("customer"))
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+
+	assert.Equal(t, 400, response.StatusCode)
+	config.CustomerRepository.Mock.AssertNotCalled(t, "PushProductToCart", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"embed"
 	_ "embed"
-	"fmt"
+	"encoding/json"
+	"flag"
 	"github.com/rs/cors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -12,9 +13,12 @@ import (
 	"io/fs"
 	"net/http"
 	"os"
+	"time"
 	"weplant-backend/app"
 	"weplant-backend/controller"
 	"weplant-backend/helper"
+	"weplant-backend/middleware"
+	"weplant-backend/model/web"
 	"weplant-backend/pkg"
 	"weplant-backend/repository"
 	"weplant-backend/service"
@@ -24,6 +28,11 @@ import (
 var spec embed.FS
 
 func main() {
+	migrate := flag.Bool("migrate", false, "move the transactions and orders embedded in customers and merchants into their own collections, convert single product orders into orders with items, then exit")
+	reconcileImages := flag.Bool("reconcile-images", false, "report the stored images no document references and the documents whose image files are missing, then exit")
+	deleteOrphans := flag.Bool("delete-orphans", false, "with -reconcile-images, delete the orphaned images older than -orphan-grace")
+	orphanGrace := flag.Duration("orphan-grace", 24*time.Hour, "with -reconcile-images, how old an orphaned image must be before it is deleted")
+	flag.Parse()
 
 	swagger, err := fs.Sub(spec, "swagger")
 	helper.PanicIfError(err)
@@ -34,8 +43,13 @@ func main() {
 	defer app.CloseConnection(client)
 	database := client.Database("weplant-backend")
 
-	// cloudinary get cloud
-	cloud := app.GetCloud()
+	if *migrate {
+		err = app.MigrateEmbeddedOrders(context.Background(), database)
+		helper.PanicIfError(err)
+		err = app.MigrateOrderItems(context.Background(), database)
+		helper.PanicIfError(err)
+		return
+	}
 
 	// get xendit key
 	midtransKey := app.GetMidtransKey()
@@ -57,8 +71,25 @@ func main() {
 		Keys:    bson.D{{Key: "slug", Value: 1}},
 		Options: options.Index().SetUnique(true),
 	})
+	// a collection holds a single text index, the one on the name alone is replaced
+	productCollection.Indexes().DropOne(context.Background(), "name_text")
+	productCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{"name", "text"}, {"description", "text"}, {"categories.name", "text"}},
+		Options: options.Index().SetName("search_text").SetWeights(bson.D{{"name", 10}, {"categories.name", 5}, {"description", 1}}),
+	})
+	// the search pages on these, _id breaks the ties
+	productCollection.Indexes().DropOne(context.Background(), "sold_-1")
+	productCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "sold", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	productCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "variants.sku", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{Key: "variants.sku", Value: bson.D{{Key: "$exists", Value: true}}}}),
+	})
 	productCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
-		Keys: bson.D{{"name", "text"}},
+		Keys: bson.D{{Key: "categories.category_id", Value: 1}},
 	})
 	categoryCollection := database.Collection("category")
 	categoryCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
@@ -71,41 +102,228 @@ func main() {
 		Options: options.Index().SetUnique(true),
 	})
 
+	refreshTokenCollection := database.Collection("refresh_token")
+	refreshTokenCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "family", Value: 1}},
+		},
+	})
+
+	paymentNotificationCollection := database.Collection("payment_notification")
+	paymentNotificationCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "order_id", Value: 1}, {Key: "transaction_status", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	reservationCollection := database.Collection("reservation")
+	reservationCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "transaction_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "expired_at", Value: 1}},
+		},
+	})
+
+	transactionCollection := database.Collection("transaction")
+	transactionCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "customer_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+	})
+
+	orderCollection := database.Collection("order")
+	orderCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "customer_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+	})
+
+	ledgerCollection := database.Collection("ledger")
+	ledgerCollection.Indexes().DropOne(context.Background(), "merchant_id_1_created_at_-1")
+	ledgerCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "type", Value: 1}, {Key: "reference_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+	})
+
+	payoutCollection := database.Collection("payout")
+	payoutCollection.Indexes().DropOne(context.Background(), "merchant_id_1_created_at_-1")
+	payoutCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+	})
+
+	reviewCollection := database.Collection("review")
+	reviewCollection.Indexes().DropOne(context.Background(), "product_id_1_created_at_-1")
+	reviewCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "order_id", Value: 1}, {Key: "product_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+	})
+
+	voucherCollection := database.Collection("voucher")
+	voucherCollection.Indexes().DropOne(context.Background(), "merchant_id_1_created_at_-1")
+	voucherCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "merchant_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+	})
+
+	voucherUsageCollection := database.Collection("voucher_usage")
+	voucherUsageCollection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "transaction_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "voucher_id", Value: 1}, {Key: "customer_id", Value: 1}},
+		},
+	})
+
 	// repository
 	merchantRepository := repository.NewMerchantRepository(merchantCollection)
 	productRepository := repository.NewProductRepository(productCollection)
 	categoryRepository := repository.NewCategoryRepository(categoryCollection)
 	customerRepository := repository.NewCustomerRepository(customerCollection)
-	cloudinaryRepository := repository.NewCloudinaryRepository(cloud)
+	imageRepository := app.GetImageRepository()
 	midtransRepository := repository.NewMidtransRepository(midtransKey)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(refreshTokenCollection)
+	paymentNotificationRepository := repository.NewPaymentNotificationRepository(paymentNotificationCollection)
+	reservationRepository := repository.NewReservationRepository(reservationCollection)
+	sessionRepository := repository.NewSessionRepository(client)
+	transactionRepository := repository.NewTransactionRepository(transactionCollection)
+	orderRepository := repository.NewOrderRepository(orderCollection)
+	ledgerRepository := repository.NewLedgerRepository(ledgerCollection)
+	payoutRepository := repository.NewPayoutRepository(payoutCollection)
+	shippingRateProvider := repository.NewTableShippingRateProvider(nil)
+	reviewRepository := repository.NewReviewRepository(reviewCollection)
+	voucherRepository := repository.NewVoucherRepository(voucherCollection)
+	voucherUsageRepository := repository.NewVoucherUsageRepository(voucherUsageCollection)
+	imageReferenceRepository := repository.NewImageReferenceRepository(productCollection, merchantCollection, customerCollection, categoryCollection, reviewCollection)
 
 	// service
-	authService := service.NewAuthService(merchantRepository, customerRepository)
-	merchantService := service.NewMerchantService(merchantRepository, cloudinaryRepository, productRepository)
-	productService := service.NewProductService(productRepository, cloudinaryRepository, categoryRepository, merchantRepository, customerRepository)
-	categoryService := service.NewCategoryService(categoryRepository, productRepository)
-	customerService := service.NewCustomerService(customerRepository, productRepository, cloudinaryRepository)
+	authService := service.NewAuthService(merchantRepository, customerRepository, refreshTokenRepository)
+	merchantService := service.NewMerchantService(merchantRepository, imageRepository, productRepository, refreshTokenRepository, orderRepository, sessionRepository, ledgerRepository)
+	productService := service.NewProductService(productRepository, imageRepository, categoryRepository, merchantRepository, customerRepository)
+	categoryService := service.NewCategoryService(categoryRepository, productRepository, imageRepository)
+	customerService := service.NewCustomerService(customerRepository, productRepository, imageRepository, refreshTokenRepository, transactionRepository, orderRepository, ledgerRepository, merchantRepository, sessionRepository)
 	cartService := service.NewCartService(customerRepository, productRepository)
-	transactionService := service.NewTransactionService(customerRepository, productRepository, midtransRepository, merchantRepository)
+	transactionService := service.NewTransactionService(customerRepository, productRepository, midtransRepository, merchantRepository, paymentNotificationRepository, reservationRepository, sessionRepository, transactionRepository, orderRepository, shippingRateProvider, voucherRepository, voucherUsageRepository)
+	reservationService := service.NewReservationService(reservationRepository, productRepository, midtransRepository, sessionRepository)
+	ledgerService := service.NewLedgerService(ledgerRepository, merchantRepository, sessionRepository)
+	payoutService := service.NewPayoutService(payoutRepository, ledgerRepository, merchantRepository, sessionRepository)
+	shippingService := service.NewShippingService(customerRepository, productRepository, merchantRepository, shippingRateProvider)
+	voucherService := service.NewVoucherService(voucherRepository, voucherUsageRepository, merchantRepository, categoryRepository, customerRepository, productRepository)
+	wishlistService := service.NewWishlistService(customerRepository, productRepository, sessionRepository)
+	reviewService := service.NewReviewService(reviewRepository, orderRepository, productRepository, merchantRepository, customerRepository, imageRepository, sessionRepository)
+	imageService := service.NewImageService(imageRepository, imageReferenceRepository)
+
+	if *reconcileImages {
+		report, err := imageService.Reconcile(context.Background(), web.ImageReconcileRequest{
+			GracePeriod:   int(orphanGrace.Seconds()),
+			DeleteOrphans: *deleteOrphans,
+		})
+		helper.PanicIfError(err)
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+		helper.PanicIfError(err)
+		return
+	}
+
+	// background job
+	app.Schedule(context.Background(), time.Minute, func(ctx context.Context) {
+		released, err := reservationService.ReleaseExpired(ctx)
+		if err != nil {
+			helper.DefaultLogger.Error("release expired reservations", "error", err)
+		} else if released > 0 {
+			helper.DefaultLogger.Info("released expired reservations", "count", released)
+		}
+	})
+
+	app.Schedule(context.Background(), time.Hour, func(ctx context.Context) {
+		fixed, err := ledgerService.Reconcile(ctx)
+		if err != nil {
+			helper.DefaultLogger.Error("reconcile merchant balances", "error", err)
+		} else if fixed > 0 {
+			helper.DefaultLogger.Info("reconciled merchant balances with their ledger", "count", fixed)
+		}
+	})
+
+	// only reports, orphans are deleted by hand with -reconcile-images -delete-orphans
+	app.Schedule(context.Background(), 24*time.Hour, func(ctx context.Context) {
+		report, err := imageService.Reconcile(ctx, web.ImageReconcileRequest{})
+		if err != nil {
+			helper.DefaultLogger.Error("reconcile images", "error", err)
+			return
+		}
+		if len(report.Orphans) > 0 {
+			helper.DefaultLogger.Info("found orphaned images in storage", "count", len(report.Orphans))
+		}
+		for _, missing := range report.MissingImages {
+			helper.DefaultLogger.Error("document references a missing image", "collection", missing.Collection, "document_id", missing.DocumentId, "file_name", missing.FileName)
+		}
+	})
+
+	// validator
+	validate := pkg.NewValidator()
 
 	// controller
-	authController := controller.NewAuthController(authService)
-	merchantController := controller.NewMerchantController(merchantService)
-	productController := controller.NewProductController(productService)
-	categoryController := controller.NewCategoryController(categoryService)
-	customerController := controller.NewCustomerController(customerService)
-	cartController := controller.NewCartController(cartService)
-	transactionController := controller.NewTransactionController(transactionService)
+	authController := controller.NewAuthController(authService, validate)
+	merchantController := controller.NewMerchantController(merchantService, validate)
+	productController := controller.NewProductController(productService, validate)
+	categoryController := controller.NewCategoryController(categoryService, validate)
+	customerController := controller.NewCustomerController(customerService, validate)
+	cartController := controller.NewCartController(cartService, validate)
+	transactionController := controller.NewTransactionController(transactionService, validate, midtransKey)
+	ledgerController := controller.NewLedgerController(ledgerService)
+	payoutController := controller.NewPayoutController(payoutService, validate)
+	shippingController := controller.NewShippingController(shippingService, validate)
+	reviewController := controller.NewReviewController(reviewService, validate)
+	wishlistController := controller.NewWishlistController(wishlistService, validate)
+	voucherController := controller.NewVoucherController(voucherService, validate)
 
-	router := app.NewRouter(swagger, authController, merchantController, productController, categoryController, customerController, cartController, transactionController)
+	router := app.NewRouter(swagger, authController, merchantController, productController, categoryController, customerController, cartController, transactionController, ledgerController, payoutController, shippingController, reviewController, wishlistController, voucherController, productRepository)
+	app.ServeImages(router)
 
-	handler := cors.Default().Handler(router)
+	handler := middleware.RequestLoggerMiddleware(cors.Default().Handler(middleware.MetricsMiddleware(router)), helper.DefaultLogger)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
-	fmt.Println(fmt.Sprintf("app listening on port %s", port))
+	helper.DefaultLogger.Info("app listening", "port", port)
 
 	server := http.Server{
 		Addr:    ":" + port,
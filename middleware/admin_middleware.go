@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"os"
+	"weplant-backend/exception"
+)
+
+// AdminMiddleware only lets requests through when the X-Admin-Key header matches ADMIN_API_KEY,
+// every request is rejected while the key is not configured
+func AdminMiddleware(handle httprouter.Handle) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		adminKey := os.Getenv("ADMIN_API_KEY")
+		header := request.Header.Get("X-Admin-Key")
+		if adminKey == "" || subtle.ConstantTimeCompare([]byte(header), []byte(adminKey)) != 1 {
+			panic(exception.NewUnauthorizedError("you don't have permission to access this resource"))
+		}
+		handle(writer, request, params)
+	}
+}
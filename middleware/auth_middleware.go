@@ -5,43 +5,32 @@ import (
 	"net/http"
 	"strings"
 	"weplant-backend/exception"
+	"weplant-backend/helper"
 	"weplant-backend/pkg"
 )
 
-
-func  AuthMiddleware(handle httprouter.Handle, role string) httprouter.Handle {
+func AuthMiddleware(handle httprouter.Handle, role string) httprouter.Handle {
 	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		header := request.Header.Get("Authorization")
 		if len(strings.Split(header, " ")) != 2 {
 			panic(exception.NewUnauthorizedError("auth header is invalid"))
-			return
 		}
 		token := strings.Split(header, " ")[1]
 
 		payload, err := pkg.ValidateToken(token)
 		if err != nil {
 			panic(exception.NewUnauthorizedError(err.Error()))
-			return
 		}
 
-		switch role {
-		case "merchant":
-			if payload.Role != "merchant" {
-				panic(exception.NewUnauthorizedError("you don't have permission to access this resource"))
-				return
-			} else {
-				handle(writer, request, params)
-			}
-		case "customer":
-			if payload.Role != "customer" {
-				panic(exception.NewUnauthorizedError("you don't have permission to access this resource"))
-				return
-			} else {
-				handle(writer, request, params)
-			}
-		default:
+		if payload.Role != role {
 			panic(exception.NewUnauthorizedError("you don't have permission to access this resource"))
-			return
 		}
+
+		if requestLog, ok := helper.GetRequestLog(request.Context()); ok {
+			requestLog.UserId = payload.Id
+			requestLog.Role = payload.Role
+		}
+		ctx := helper.SetJWTPayload(request.Context(), payload)
+		handle(writer, request.WithContext(ctx), params)
 	}
 }
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"weplant-backend/exception"
+	"weplant-backend/pkg"
+)
+
+// UnmatchedRoute labels the requests no route matches, so unknown paths don't each get their own series
+const UnmatchedRoute = "unmatched"
+
+type routePatternKey struct{}
+
+// MetricsMiddleware counts the requests served by handler and their latency by route pattern and status, the
+// pattern is the one RouteMiddleware recorded
+func MetricsMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		route := UnmatchedRoute
+		request = request.WithContext(context.WithValue(request.Context(), routePatternKey{}, &route))
+		recorder := &statusRecorder{ResponseWriter: writer, status: http.StatusOK}
+		handler.ServeHTTP(recorder, request)
+
+		status := strconv.Itoa(recorder.status)
+		pkg.HTTPRequests.WithLabelValues(request.Method, route, status).Inc()
+		pkg.HTTPRequestDuration.WithLabelValues(request.Method, route, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// RouteMiddleware records pattern, the path handle was registered with such as /api/v1/products/:productId, for
+// MetricsMiddleware. httprouter only hands out the parameter values.
+func RouteMiddleware(handle httprouter.Handle, pattern string) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		if route, ok := request.Context().Value(routePatternKey{}).(*string); ok {
+			*route = pattern
+		}
+		handle(writer, request, params)
+	}
+}
+
+// MetricsTokenMiddleware asks for the METRICS_TOKEN as a bearer token once it is configured
+func MetricsTokenMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		token := os.Getenv("METRICS_TOKEN")
+		if token != "" {
+			header := request.Header.Get("Authorization")
+			if subtle.ConstantTimeCompare([]byte(header), []byte("Bearer "+token)) != 1 {
+				panic(exception.NewUnauthorizedError("you don't have permission to access this resource"))
+			}
+		}
+		handler.ServeHTTP(writer, request)
+	})
+}
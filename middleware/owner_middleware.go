@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"weplant-backend/exception"
+	"weplant-backend/helper"
+	"weplant-backend/repository"
+)
+
+// OwnerMiddleware must be wrapped by AuthMiddleware, it compares the path param with the id inside the token
+func OwnerMiddleware(handle httprouter.Handle, param string) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		payload, ok := helper.GetJWTPayload(request.Context())
+		if !ok || params.ByName(param) != payload.Id {
+			panic(exception.NewForbiddenError("you don't have permission to access this resource"))
+		}
+		handle(writer, request, params)
+	}
+}
+
+// FormOwnerMiddleware is the same as OwnerMiddleware but reads the id from a form field
+func FormOwnerMiddleware(handle httprouter.Handle, field string) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		payload, ok := helper.GetJWTPayload(request.Context())
+		if !ok || request.PostFormValue(field) != payload.Id {
+			panic(exception.NewForbiddenError("you don't have permission to access this resource"))
+		}
+		handle(writer, request, params)
+	}
+}
+
+type ProductOwnerMiddleware struct {
+	ProductRepository repository.ProductRepository
+}
+
+func NewProductOwnerMiddleware(productRepository repository.ProductRepository) *ProductOwnerMiddleware {
+	return &ProductOwnerMiddleware{
+		ProductRepository: productRepository,
+	}
+}
+
+// Handle must be wrapped by AuthMiddleware, it only lets the merchant who owns :productId through
+func (middleware *ProductOwnerMiddleware) Handle(handle httprouter.Handle) httprouter.Handle {
+	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		payload, ok := helper.GetJWTPayload(request.Context())
+		if !ok {
+			panic(exception.NewForbiddenError("you don't have permission to access this resource"))
+		}
+
+		product, err := middleware.ProductRepository.FindById(request.Context(), params.ByName("productId"))
+		helper.PanicIfErrorNotFound(err)
+
+		if product.MerchantId != payload.Id {
+			panic(exception.NewForbiddenError("you don't have permission to access this resource"))
+		}
+		handle(writer, request, params)
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"runtime/debug"
+	"time"
+	"weplant-backend/helper"
+)
+
+const RequestIdHeader = "X-Request-ID"
+
+// RequestLoggerMiddleware gives every request an id, taken from the X-Request-ID header when the caller sent a usable
+// one, puts a logger carrying it in the context and logs the request once it is done
+func RequestLoggerMiddleware(handler http.Handler, logger *helper.Logger) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+
+		requestId := request.Header.Get(RequestIdHeader)
+		if !validRequestId(requestId) {
+			requestId = newRequestId()
+		}
+		writer.Header().Set(RequestIdHeader, requestId)
+
+		requestLogger := logger.With("request_id", requestId)
+		requestLog := &helper.RequestLog{}
+		ctx := helper.SetLogger(request.Context(), requestLogger)
+		ctx = helper.SetRequestLog(ctx, requestLog)
+
+		recorder := &statusRecorder{ResponseWriter: writer, status: http.StatusOK}
+		handler.ServeHTTP(recorder, request.WithContext(ctx))
+
+		fields := []interface{}{
+			"method", request.Method,
+			"path", request.URL.Path,
+			"status", recorder.status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", recorder.bytes,
+		}
+		if requestLog.UserId != "" {
+			fields = append(fields, "user_id", requestLog.UserId, "role", requestLog.Role)
+		}
+		if requestLog.Panic != nil {
+			fields = append(fields, "panic", requestLog.Panic)
+		}
+		if recorder.status >= http.StatusInternalServerError {
+			if requestLog.Stack != nil {
+				fields = append(fields, "stack", string(requestLog.Stack))
+			}
+			requestLogger.Error("request", fields...)
+		} else {
+			requestLogger.Info("request", fields...)
+		}
+	})
+}
+
+// PanicHandler keeps the recovered panic and its stack for the request log before handing it to handle
+func PanicHandler(handle func(http.ResponseWriter, *http.Request, interface{})) func(http.ResponseWriter, *http.Request, interface{}) {
+	return func(writer http.ResponseWriter, request *http.Request, err interface{}) {
+		if requestLog, ok := helper.GetRequestLog(request.Context()); ok {
+			requestLog.Panic = err
+			requestLog.Stack = debug.Stack()
+		}
+		handle(writer, request, err)
+	}
+}
+
+// validRequestId accepts ids of up to 128 printable ASCII characters so a caller can't break the log lines
+func validRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > 128 {
+		return false
+	}
+	for _, c := range requestId {
+		if c < 0x21 || c > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestId() string {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	helper.PanicIfError(err)
+	return hex.EncodeToString(id)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if !recorder.wroteHeader {
+		recorder.status = status
+		recorder.wroteHeader = true
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Write(data []byte) (int, error) {
+	recorder.wroteHeader = true
+	n, err := recorder.ResponseWriter.Write(data)
+	recorder.bytes += n
+	return n, err
+}
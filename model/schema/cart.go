@@ -2,5 +2,6 @@ package schema
 
 type CartProduct struct {
 	ProductId string `bson:"product_id,omitempty"`
+	VariantId string `bson:"variant_id,omitempty"`
 	Quantity  int    `bson:"quantity,omitempty"`
 }
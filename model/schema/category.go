@@ -6,6 +6,8 @@ type Category struct {
 	Id        primitive.ObjectID `bson:"_id,omitempty"`
 	CreatedAt int                `bson:"created_at,omitempty"`
 	UpdatedAt int                `bson:"updated_at,omitempty"`
+	ParentId  string             `bson:"parent_id,omitempty"`
 	Name      string             `bson:"name,omitempty"`
 	Slug      string             `bson:"slug"`
+	MainImage *Image             `bson:"main_image,omitempty"`
 }
@@ -4,6 +4,20 @@ import "go.mongodb.org/mongo-driver/bson/primitive"
 
 type Image struct {
 	Id       primitive.ObjectID `bson:"_id,omitempty"`
+	Name     string             `bson:"name,omitempty"`
 	FileName string             `bson:"file_name,omitempty"`
 	URL      string             `bson:"url,omitempty"`
+	Width    int                `bson:"width,omitempty"`
+	Height   int                `bson:"height,omitempty"`
+	BlurHash string             `bson:"blur_hash,omitempty"`
+	Variants []ImageVariant     `bson:"variants,omitempty"`
+}
+
+// ImageVariant is a resized copy of the image, stored as the image file name followed by -Name
+type ImageVariant struct {
+	Name     string `bson:"name"`
+	FileName string `bson:"file_name"`
+	URL      string `bson:"url"`
+	Width    int    `bson:"width"`
+	Height   int    `bson:"height"`
 }
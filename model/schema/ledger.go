@@ -0,0 +1,23 @@
+package schema
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	LedgerTypeSale               = "sale"
+	LedgerTypeCommission         = "commission"
+	LedgerTypeRefund             = "refund"
+	LedgerTypeWithdrawal         = "withdrawal"
+	LedgerTypeWithdrawalReversal = "withdrawal_reversal"
+	LedgerTypeOpeningBalance     = "opening_balance"
+)
+
+// LedgerEntry is never updated or deleted, the merchant balance is the sum of its entries
+type LedgerEntry struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt   int                `bson:"created_at,omitempty"`
+	MerchantId  string             `bson:"merchant_id,omitempty"`
+	Type        string             `bson:"type,omitempty"`
+	Amount      int64              `bson:"amount,omitempty"`
+	ReferenceId string             `bson:"reference_id,omitempty"`
+	Note        string             `bson:"note,omitempty"`
+}
@@ -2,12 +2,51 @@ package schema
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
-type OrderProduct struct {
-	Id        primitive.ObjectID `bson:"_id,omitempty"`
-	CreatedAt int                `bson:"created_at,omitempty"`
-	UpdatedAt int                `bson:"updated_at,omitempty"`
-	ProductId string             `bson:"product_id,omitempty"`
-	Price     int                `bson:"price,omitempty"`
-	Quantity  int                `bson:"quantity,omitempty"`
-	Address   *Address           `bson:"address,omitempty"`
+const (
+	OrderStatusPaid            = "paid"
+	OrderStatusProcessing      = "processing"
+	OrderStatusShipped         = "shipped"
+	OrderStatusDelivered       = "delivered"
+	OrderStatusCompleted       = "completed"
+	OrderStatusCancelled       = "cancelled"
+	OrderStatusRefundRequested = "refund_requested"
+)
+
+type OrderShipping struct {
+	Courier        string `bson:"courier,omitempty"`
+	Service        string `bson:"service,omitempty"`
+	TrackingNumber string `bson:"tracking_number,omitempty"`
+}
+
+type OrderHistory struct {
+	CreatedAt int    `bson:"created_at,omitempty"`
+	Status    string `bson:"status,omitempty"`
+	Note      string `bson:"note,omitempty"`
+}
+
+type OrderItem struct {
+	ProductId   string `bson:"product_id,omitempty"`
+	VariantId   string `bson:"variant_id,omitempty"`
+	VariantName string `bson:"variant_name,omitempty"`
+	Price       int    `bson:"price,omitempty"`
+	Quantity    int    `bson:"quantity,omitempty"`
+}
+
+// Order is the part of a transaction one merchant fulfils, it is shipped as one package
+type Order struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt     int                `bson:"created_at,omitempty"`
+	UpdatedAt     int                `bson:"updated_at,omitempty"`
+	TransactionId string             `bson:"transaction_id,omitempty"`
+	CustomerId    string             `bson:"customer_id,omitempty"`
+	MerchantId    string             `bson:"merchant_id,omitempty"`
+	Items         []OrderItem        `bson:"items,omitempty"`
+	Subtotal      int                `bson:"subtotal,omitempty"`
+	ShippingFee   int                `bson:"shipping_fee,omitempty"`
+	Discount      int                `bson:"discount,omitempty"`
+	Total         int                `bson:"total,omitempty"`
+	Status        string             `bson:"status,omitempty"`
+	Address       *Address           `bson:"address,omitempty"`
+	Shipping      *OrderShipping     `bson:"shipping,omitempty"`
+	History       []OrderHistory     `bson:"history,omitempty"`
 }
@@ -0,0 +1,11 @@
+package schema
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type PaymentNotification struct {
+	Id                primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt         int                `bson:"created_at,omitempty"`
+	OrderId           string             `bson:"order_id,omitempty"`
+	TransactionStatus string             `bson:"transaction_status,omitempty"`
+	CustomerId        string             `bson:"customer_id,omitempty"`
+}
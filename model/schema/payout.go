@@ -0,0 +1,29 @@
+package schema
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	PayoutStatusRequested  = "requested"
+	PayoutStatusProcessing = "processing"
+	PayoutStatusPaid       = "paid"
+	PayoutStatusRejected   = "rejected"
+)
+
+type PayoutHistory struct {
+	CreatedAt int    `bson:"created_at,omitempty"`
+	Status    string `bson:"status,omitempty"`
+	Note      string `bson:"note,omitempty"`
+}
+
+type Payout struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt     int                `bson:"created_at,omitempty"`
+	UpdatedAt     int                `bson:"updated_at,omitempty"`
+	MerchantId    string             `bson:"merchant_id,omitempty"`
+	Amount        int64              `bson:"amount,omitempty"`
+	Status        string             `bson:"status,omitempty"`
+	BankName      string             `bson:"bank_name,omitempty"`
+	AccountNumber string             `bson:"account_number,omitempty"`
+	AccountName   string             `bson:"account_name,omitempty"`
+	History       []PayoutHistory    `bson:"history,omitempty"`
+}
@@ -2,10 +2,29 @@ package schema
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// ProductCategory keeps a copy of the category name for the text search
 type ProductCategory struct {
 	CategoryId string `bson:"category_id,omitempty"`
+	Name       string `bson:"name,omitempty"`
 }
 
+type ProductVariantOption struct {
+	Name  string `bson:"name,omitempty"`
+	Value string `bson:"value,omitempty"`
+}
+
+// ProductVariant is one combination of options of a product, it is sold at its own price from its own stock
+type ProductVariant struct {
+	Id      primitive.ObjectID     `bson:"_id,omitempty"`
+	Sku     string                 `bson:"sku,omitempty"`
+	Options []ProductVariantOption `bson:"options,omitempty"`
+	Price   int                    `bson:"price,omitempty"`
+	Stock   int                    `bson:"stock"`
+	ImageId string                 `bson:"image_id,omitempty"`
+}
+
+// Product weight is in grams and its dimensions are in centimeters, couriers charge the packed size when it weighs more.
+// The stock of a product with variants is the sum of the stock of its variants.
 type Product struct {
 	Id          primitive.ObjectID `bson:"_id,omitempty"`
 	CreatedAt   int                `bson:"created_at,omitempty"`
@@ -16,7 +35,15 @@ type Product struct {
 	Description string             `bson:"description,omitempty"`
 	Price       int                `bson:"price,omitempty"`
 	Stock       int                `bson:"stock,omitempty"`
+	Weight      int                `bson:"weight,omitempty"`
+	Length      int                `bson:"length,omitempty"`
+	Width       int                `bson:"width,omitempty"`
+	Height      int                `bson:"height,omitempty"`
 	MainImage   *Image             `bson:"main_image,omitempty"`
 	Images      []Image            `bson:"images,omitempty"`
 	Categories  []ProductCategory  `bson:"categories,omitempty"`
+	Variants    []ProductVariant   `bson:"variants,omitempty"`
+	RatingCount int                `bson:"rating_count,omitempty"`
+	RatingTotal int                `bson:"rating_total,omitempty"`
+	Sold        int                `bson:"sold,omitempty"`
 }
@@ -0,0 +1,16 @@
+package schema
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type RefreshToken struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt  int                `bson:"created_at,omitempty"`
+	UpdatedAt  int                `bson:"updated_at,omitempty"`
+	ExpiredAt  int                `bson:"expired_at,omitempty"`
+	UserId     string             `bson:"user_id,omitempty"`
+	Role       string             `bson:"role,omitempty"`
+	Family     string             `bson:"family,omitempty"`
+	TokenHash  string             `bson:"token_hash,omitempty"`
+	Revoked    bool               `bson:"revoked"`
+	ReplacedBy string             `bson:"replaced_by,omitempty"`
+}
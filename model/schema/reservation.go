@@ -0,0 +1,26 @@
+package schema
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	ReservationStatusActive    = "active"
+	ReservationStatusCommitted = "committed"
+	ReservationStatusReleased  = "released"
+)
+
+type ReservationProduct struct {
+	ProductId string `bson:"product_id,omitempty"`
+	VariantId string `bson:"variant_id,omitempty"`
+	Quantity  int    `bson:"quantity,omitempty"`
+}
+
+type Reservation struct {
+	Id            primitive.ObjectID   `bson:"_id,omitempty"`
+	CreatedAt     int                  `bson:"created_at,omitempty"`
+	UpdatedAt     int                  `bson:"updated_at,omitempty"`
+	ExpiredAt     int                  `bson:"expired_at,omitempty"`
+	TransactionId string               `bson:"transaction_id,omitempty"`
+	CustomerId    string               `bson:"customer_id,omitempty"`
+	Status        string               `bson:"status,omitempty"`
+	Products      []ReservationProduct `bson:"products,omitempty"`
+}
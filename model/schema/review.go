@@ -0,0 +1,25 @@
+package schema
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type ReviewReply struct {
+	CreatedAt int    `bson:"created_at,omitempty"`
+	UpdatedAt int    `bson:"updated_at,omitempty"`
+	Text      string `bson:"text,omitempty"`
+}
+
+// Review is written by a customer for a product of one of their completed orders, one review per product of an order
+type Review struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt    int                `bson:"created_at,omitempty"`
+	UpdatedAt    int                `bson:"updated_at,omitempty"`
+	OrderId      string             `bson:"order_id,omitempty"`
+	ProductId    string             `bson:"product_id,omitempty"`
+	MerchantId   string             `bson:"merchant_id,omitempty"`
+	CustomerId   string             `bson:"customer_id,omitempty"`
+	CustomerName string             `bson:"customer_name,omitempty"`
+	Rating       int                `bson:"rating,omitempty"`
+	Text         string             `bson:"text,omitempty"`
+	Images       []Image            `bson:"images,omitempty"`
+	Reply        *ReviewReply       `bson:"reply,omitempty"`
+}
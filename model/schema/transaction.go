@@ -2,19 +2,68 @@ package schema
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+const (
+	PaymentMethodQris       = "qris"
+	PaymentMethodBcaVa      = "bca_va"
+	PaymentMethodBniVa      = "bni_va"
+	PaymentMethodBriVa      = "bri_va"
+	PaymentMethodPermataVa  = "permata_va"
+	PaymentMethodGopay      = "gopay"
+	PaymentMethodShopeepay  = "shopeepay"
+	PaymentMethodCreditCard = "credit_card"
+)
+
+// TransactionProduct keeps the name of the variant, the variant may be changed or deleted after the checkout
 type TransactionProduct struct {
-	ProductId string `bson:"product_id,omitempty"`
-	Price     int    `bson:"price,omitempty"`
-	Quantity  int    `bson:"quantity,omitempty"`
+	ProductId   string `bson:"product_id,omitempty"`
+	VariantId   string `bson:"variant_id,omitempty"`
+	VariantName string `bson:"variant_name,omitempty"`
+	MerchantId  string `bson:"merchant_id,omitempty"`
+	Price       int    `bson:"price,omitempty"`
+	Quantity    int    `bson:"quantity,omitempty"`
+}
+
+// TransactionPayment keeps what the customer needs to finish paying, only the fields of the chosen method are set
+type TransactionPayment struct {
+	Method      string `bson:"method,omitempty"`
+	Bank        string `bson:"bank,omitempty"`
+	VANumber    string `bson:"va_number,omitempty"`
+	QRCode      string `bson:"qr_code,omitempty"`
+	Deeplink    string `bson:"deeplink,omitempty"`
+	RedirectURL string `bson:"redirect_url,omitempty"`
+	ExpiredAt   int    `bson:"expired_at,omitempty"`
+}
+
+// TransactionOrder is what the transaction charges for each merchant, the orders are created from it once paid
+type TransactionOrder struct {
+	MerchantId  string `bson:"merchant_id,omitempty"`
+	Courier     string `bson:"courier,omitempty"`
+	Service     string `bson:"service,omitempty"`
+	Subtotal    int    `bson:"subtotal,omitempty"`
+	ShippingFee int    `bson:"shipping_fee,omitempty"`
+	Discount    int    `bson:"discount,omitempty"`
+	Total       int    `bson:"total,omitempty"`
+}
+
+// TransactionVoucher is the voucher used at checkout, a platform voucher is paid by the platform
+// so only a merchant voucher lowers the total of the order of its merchant
+type TransactionVoucher struct {
+	VoucherId  string `bson:"voucher_id,omitempty"`
+	Code       string `bson:"code,omitempty"`
+	MerchantId string `bson:"merchant_id,omitempty"`
+	Discount   int    `bson:"discount,omitempty"`
 }
 
 type Transaction struct {
 	Id          primitive.ObjectID   `bson:"_id,omitempty"`
 	CreatedAt   int                  `bson:"created_at,omitempty"`
 	UpdatedAt   int                  `bson:"updated_at,omitempty"`
+	CustomerId  string               `bson:"customer_id,omitempty"`
 	PaymentType string               `bson:"payment_type,omitempty"`
 	Status      string               `bson:"status,omitempty"`
-	QRCode      string               `bson:"qr_code,omitempty"`
+	Payment     *TransactionPayment  `bson:"payment,omitempty"`
 	Products    []TransactionProduct `bson:"products,omitempty"`
+	Orders      []TransactionOrder   `bson:"orders,omitempty"`
+	Voucher     *TransactionVoucher  `bson:"voucher,omitempty"`
 	Address     *Address             `bson:"address,omitempty"`
 }
@@ -0,0 +1,38 @@
+package schema
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	VoucherTypePercentage = "percentage"
+	VoucherTypeFixed      = "fixed"
+)
+
+// Voucher without a merchant is run by the platform and applies to every merchant,
+// without categories it applies to every product, a zero limit means unlimited
+type Voucher struct {
+	Id               primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt        int                `bson:"created_at,omitempty"`
+	UpdatedAt        int                `bson:"updated_at,omitempty"`
+	Code             string             `bson:"code,omitempty"`
+	MerchantId       string             `bson:"merchant_id,omitempty"`
+	CategoryIds      []string           `bson:"category_ids,omitempty"`
+	Type             string             `bson:"type,omitempty"`
+	Value            int                `bson:"value,omitempty"`
+	MinSpend         int                `bson:"min_spend,omitempty"`
+	MaxDiscount      int                `bson:"max_discount,omitempty"`
+	StartAt          int                `bson:"start_at,omitempty"`
+	EndAt            int                `bson:"end_at,omitempty"`
+	UsageLimit       int                `bson:"usage_limit,omitempty"`
+	PerCustomerLimit int                `bson:"per_customer_limit,omitempty"`
+	UsedCount        int                `bson:"used_count,omitempty"`
+}
+
+// VoucherUsage is recorded once the transaction that used the voucher is paid
+type VoucherUsage struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	CreatedAt     int                `bson:"created_at,omitempty"`
+	VoucherId     string             `bson:"voucher_id,omitempty"`
+	CustomerId    string             `bson:"customer_id,omitempty"`
+	TransactionId string             `bson:"transaction_id,omitempty"`
+	Discount      int                `bson:"discount,omitempty"`
+}
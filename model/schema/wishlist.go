@@ -0,0 +1,6 @@
+package schema
+
+type WishlistProduct struct {
+	CreatedAt int    `bson:"created_at,omitempty"`
+	ProductId string `bson:"product_id,omitempty"`
+}
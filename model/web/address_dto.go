@@ -12,15 +12,15 @@ type AddressResponse struct {
 // Request
 
 type AddressCreateRequest struct {
-	Address    string `json:"address"`
-	City       string `json:"city"`
-	Province   string `json:"province"`
-	PostalCode string `json:"postal_code"`
+	Address    string `json:"address" validate:"required,max=255"`
+	City       string `json:"city" validate:"required,max=100"`
+	Province   string `json:"province" validate:"required,max=100"`
+	PostalCode string `json:"postal_code" validate:"required,postal_code"`
 }
 
 type AddressUpdateRequest struct {
-	Address    string `json:"address"`
-	City       string `json:"city"`
-	Province   string `json:"province"`
-	PostalCode string `json:"postal_code"`
+	Address    string `json:"address" validate:"required,max=255"`
+	City       string `json:"city" validate:"required,max=100"`
+	Province   string `json:"province" validate:"required,max=100"`
+	PostalCode string `json:"postal_code" validate:"required,postal_code"`
 }
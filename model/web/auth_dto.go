@@ -3,14 +3,20 @@ package web
 // Response
 
 type TokenResponse struct {
-	Id    string `json:"id"`
-	Role  string `json:"role"`
-	Token string `json:"token"`
+	Id           string `json:"id"`
+	Role         string `json:"role"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiredAt    int    `json:"expired_at"`
 }
 
 // Request
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
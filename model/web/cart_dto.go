@@ -4,6 +4,8 @@ package web
 
 type CartProductResponse struct {
 	ProductId   string        `json:"product_id"`
+	VariantId   string        `json:"variant_id"`
+	VariantName string        `json:"variant_name"`
 	Name        string        `json:"name"`
 	Slug        string        `json:"slug"`
 	Description string        `json:"description"`
@@ -21,13 +23,15 @@ type CartResponse struct {
 // Request
 
 type CartProductCreateRequest struct {
-	CustomerId string `json:"customer_id"`
-	ProductId  string `json:"product_id"`
-	Quantity   int    `json:"quantity"`
+	CustomerId string `json:"customer_id" validate:"required,objectid"`
+	ProductId  string `json:"product_id" validate:"required,objectid"`
+	VariantId  string `json:"variant_id" validate:"omitempty,objectid"`
+	Quantity   int    `json:"quantity" validate:"gte=1"`
 }
 
 type CartProductUpdateRequest struct {
-	CustomerId string `json:"customer_id"`
-	ProductId  string `json:"product_id"`
-	Quantity   int    `json:"quantity"`
+	CustomerId string `json:"customer_id" validate:"required,objectid"`
+	ProductId  string `json:"product_id" validate:"required,objectid"`
+	VariantId  string `json:"variant_id" validate:"omitempty,objectid"`
+	Quantity   int    `json:"quantity" validate:"gte=1"`
 }
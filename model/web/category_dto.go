@@ -3,18 +3,37 @@ package web
 // Response
 
 type CategoryDetailResponse struct {
-	Id        string                  `json:"id"`
-	CreatedAt int                     `json:"created_at"`
-	UpdatedAt int                     `json:"updated_at"`
-	Name      string                  `json:"name"`
-	Slug      string                  `json:"slug"`
-	Products  []ProductSimpleResponse `json:"products"`
+	Id          string                     `json:"id"`
+	CreatedAt   int                        `json:"created_at"`
+	UpdatedAt   int                        `json:"updated_at"`
+	ParentId    string                     `json:"parent_id"`
+	Name        string                     `json:"name"`
+	Slug        string                     `json:"slug"`
+	MainImage   ImageResponse              `json:"main_image"`
+	Breadcrumbs []CategorySimpleResponse   `json:"breadcrumbs"`
+	Children    []CategorySimpleResponse   `json:"children"`
+	Products    []ProductSimpleResponse    `json:"products"`
+	Metadata    MetadataPaginationResponse `json:"metadata"`
 }
 
 type CategorySimpleResponse struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	Id       string `json:"id"`
+	ParentId string `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+}
+
+type CategoryFindAllResponse struct {
+	Categories []CategorySimpleResponse   `json:"categories"`
+	Metadata   MetadataPaginationResponse `json:"metadata"`
+}
+
+type CategoryTreeResponse struct {
+	Id        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Slug      string                 `json:"slug"`
+	MainImage ImageResponse          `json:"main_image"`
+	Children  []CategoryTreeResponse `json:"children"`
 }
 
 // Request
@@ -22,7 +41,8 @@ type CategorySimpleResponse struct {
 type CategoryCreateRequest struct {
 	CreatedAt int    `json:"created_at"`
 	UpdatedAt int    `json:"updated_at"`
-	Name      string `json:"name"`
+	ParentId  string `json:"parent_id" validate:"omitempty,objectid"`
+	Name      string `json:"name" validate:"required,min=2,max=50"`
 	Slug      string `json:"slug"`
 }
 
@@ -30,6 +50,35 @@ type CategoryCreateRequestResponse struct {
 	Id        string `json:"id"`
 	CreatedAt int    `json:"created_at"`
 	UpdatedAt int    `json:"updated_at"`
+	ParentId  string `json:"parent_id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+}
+
+type CategoryUpdateRequest struct {
+	Id        string `json:"id"`
+	UpdatedAt int    `json:"updated_at"`
+	ParentId  string `json:"parent_id" validate:"omitempty,objectid"`
+	Name      string `json:"name" validate:"required,min=2,max=50"`
+	Slug      string `json:"slug"`
+}
+
+type CategoryUpdateRequestResponse struct {
+	Id        string `json:"id"`
+	UpdatedAt int    `json:"updated_at"`
+	ParentId  string `json:"parent_id"`
 	Name      string `json:"name"`
 	Slug      string `json:"slug"`
 }
+
+type CategoryUpdateImageRequest struct {
+	Id        string              `json:"id"`
+	UpdatedAt int                 `json:"updated_at"`
+	MainImage *ImageUpdateRequest `json:"main_image"`
+}
+
+type CategoryUpdateImageRequestResponse struct {
+	Id        string        `json:"id"`
+	UpdatedAt int           `json:"updated_at"`
+	MainImage ImageResponse `json:"main_image"`
+}
@@ -17,17 +17,17 @@ type CustomerResponse struct {
 type CustomerCreateRequest struct {
 	CreatedAt int    `json:"created_at"`
 	UpdatedAt int    `json:"updated_at"`
-	Email     string `json:"email"`
-	Password  string `json:"password"`
-	UserName  string `json:"user_name"`
-	Phone     string `json:"phone"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required,min=8,max=72,password"`
+	UserName  string `json:"user_name" validate:"required,min=3,max=50"`
+	Phone     string `json:"phone" validate:"required,phone"`
 }
 
 type CustomerUpdateRequest struct {
 	Id        string `json:"id"`
 	UpdatedAt int    `json:"updated_at"`
-	UserName  string `json:"user_name"`
-	Phone     string `json:"phone"`
+	UserName  string `json:"user_name" validate:"required,min=3,max=50"`
+	Phone     string `json:"phone" validate:"required,phone"`
 }
 
 type CustomerUpdateImageRequest struct {
@@ -3,19 +3,71 @@ package web
 // Response
 
 type ImageResponse struct {
-	Id       string `json:"id"`
-	FileName string `json:"file_name"`
-	URL      string `json:"url"`
+	Id       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	FileName string                 `json:"file_name"`
+	URL      string                 `json:"url"`
+	Width    int                    `json:"width"`
+	Height   int                    `json:"height"`
+	BlurHash string                 `json:"blur_hash"`
+	Variants []ImageVariantResponse `json:"variants"`
+}
+
+type ImageVariantResponse struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 // Request
 
 type ImageCreateRequest struct {
-	FileName string      `json:"file_name"`
-	URL      interface{} `json:"url"`
+	// Name is the name the file was uploaded with, FileName is the unique key it is stored under
+	Name     string                `json:"name"`
+	FileName string                `json:"file_name" validate:"required"`
+	URL      interface{}           `json:"url" validate:"required"`
+	Width    int                   `json:"width"`
+	Height   int                   `json:"height"`
+	BlurHash string                `json:"blur_hash"`
+	Variants []ImageVariantRequest `json:"variants"`
+}
+
+// ImageUpdateRequest replaces an image with a new upload
+type ImageUpdateRequest = ImageCreateRequest
+
+type ImageVariantRequest struct {
+	Name   string      `json:"name"`
+	URL    interface{} `json:"url"`
+	Width  int         `json:"width"`
+	Height int         `json:"height"`
+}
+
+type ImageReconcileRequest struct {
+	// GracePeriod in seconds keeps orphans this young, their document may still be on its way to the database
+	GracePeriod   int  `json:"grace_period"`
+	DeleteOrphans bool `json:"delete_orphans"`
+}
+
+type ImageReconcileResponse struct {
+	StoredImages     int                    `json:"stored_images"`
+	ReferencedImages int                    `json:"referenced_images"`
+	Orphans          []OrphanImageResponse  `json:"orphans"`
+	DeletedOrphans   int                    `json:"deleted_orphans"`
+	MissingImages    []MissingImageResponse `json:"missing_images"`
+}
+
+// OrphanImageResponse is a stored file no document references
+type OrphanImageResponse struct {
+	FileName  string `json:"file_name"`
+	CreatedAt int    `json:"created_at"`
+	Deleted   bool   `json:"deleted"`
 }
 
-type ImageUpdateRequest struct {
-	FileName string      `json:"file_name"`
-	URL      interface{} `json:"url"`
+// MissingImageResponse is an image of a document whose file is not in the storage
+type MissingImageResponse struct {
+	Collection string `json:"collection"`
+	DocumentId string `json:"document_id"`
+	FileName   string `json:"file_name"`
+	URL        string `json:"url"`
 }
@@ -0,0 +1,19 @@
+package web
+
+// Response
+
+type LedgerEntryResponse struct {
+	Id          string `json:"id"`
+	CreatedAt   int    `json:"created_at"`
+	Type        string `json:"type"`
+	Amount      int64  `json:"amount"`
+	ReferenceId string `json:"reference_id"`
+	Note        string `json:"note"`
+}
+
+type LedgerResponse struct {
+	MerchantId string                     `json:"merchant_id"`
+	Balance    int64                      `json:"balance"`
+	Entries    []LedgerEntryResponse      `json:"entries"`
+	Metadata   MetadataPaginationResponse `json:"metadata"`
+}
@@ -2,22 +2,43 @@ package web
 
 // Response
 
-type ManageOrderProductResponse struct {
-	Id          string          `json:"id"`
-	CreatedAt   int             `json:"created_at"`
-	UpdatedAt   int             `json:"updated_at"`
-	ProductId   string          `json:"product_id"`
-	Name        string          `json:"name"`
-	Slug        string          `json:"slug"`
-	Description string          `json:"description"`
-	Price       int             `json:"price"`
-	Quantity    int             `json:"quantity"`
-	TotalPrice  int             `json:"total_price"`
-	MainImage   ImageResponse   `json:"main_image"`
-	Address     AddressResponse `json:"address"`
+type ManageOrderDetailResponse struct {
+	Id            string                 `json:"id"`
+	CreatedAt     int                    `json:"created_at"`
+	UpdatedAt     int                    `json:"updated_at"`
+	TransactionId string                 `json:"transaction_id"`
+	CustomerId    string                 `json:"customer_id"`
+	Status        string                 `json:"status"`
+	Items         []OrderItemResponse    `json:"items"`
+	Subtotal      int                    `json:"subtotal"`
+	ShippingFee   int                    `json:"shipping_fee"`
+	Discount      int                    `json:"discount"`
+	Total         int                    `json:"total"`
+	Address       AddressResponse        `json:"address"`
+	Shipping      OrderShippingResponse  `json:"shipping"`
+	History       []OrderHistoryResponse `json:"history"`
 }
 
 type ManageOrderResponse struct {
-	MerchantId string                       `json:"merchant_id"`
-	Products   []ManageOrderProductResponse `json:"products"`
+	MerchantId string                      `json:"merchant_id"`
+	Orders     []ManageOrderDetailResponse `json:"orders"`
+	Metadata   MetadataPaginationResponse  `json:"metadata"`
+}
+
+// Request
+
+type ManageOrderUpdateStatusRequest struct {
+	Id         string `json:"id"`
+	MerchantId string `json:"merchant_id"`
+	UpdatedAt  int    `json:"updated_at"`
+	Status     string `json:"status" validate:"required,oneof=processing delivered cancelled"`
+	Note       string `json:"note" validate:"max=255"`
+}
+
+type ManageOrderUpdateShippingRequest struct {
+	Id             string `json:"id"`
+	MerchantId     string `json:"merchant_id"`
+	UpdatedAt      int    `json:"updated_at"`
+	Courier        string `json:"courier" validate:"required,max=50"`
+	TrackingNumber string `json:"tracking_number" validate:"required,max=100"`
 }
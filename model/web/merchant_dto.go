@@ -3,17 +3,19 @@ package web
 // Response
 
 type MerchantDetailResponse struct {
-	Id        string                  `json:"id"`
-	CreatedAt int                     `json:"created_at"`
-	UpdatedAt int                     `json:"updated_at"`
-	Email     string                  `json:"email"`
-	Name      string                  `json:"name"`
-	Slug      string                  `json:"slug"`
-	Phone     string                  `json:"phone"`
-	Balance   int64                   `json:"balance"`
-	MainImage ImageResponse           `json:"main_image"`
-	Address   AddressResponse         `json:"address"`
-	Products  []ProductSimpleResponse `json:"products"`
+	Id        string                     `json:"id"`
+	CreatedAt int                        `json:"created_at"`
+	UpdatedAt int                        `json:"updated_at"`
+	Email     string                     `json:"email"`
+	Name      string                     `json:"name"`
+	Slug      string                     `json:"slug"`
+	Phone     string                     `json:"phone"`
+	Balance   int64                      `json:"balance"`
+	Rating    RatingResponse             `json:"rating"`
+	MainImage ImageResponse              `json:"main_image"`
+	Address   AddressResponse            `json:"address"`
+	Products  []ProductSimpleResponse    `json:"products"`
+	Metadata  MetadataPaginationResponse `json:"metadata"`
 }
 
 type MerchantSimpleResponse struct {
@@ -30,21 +32,21 @@ type MerchantSimpleResponse struct {
 type MerchantCreateRequest struct {
 	CreatedAt int                   `json:"created_at"`
 	UpdatedAt int                   `json:"updated_at"`
-	Email     string                `json:"email"`
-	Password  string                `json:"password"`
-	Name      string                `json:"name"`
+	Email     string                `json:"email" validate:"required,email"`
+	Password  string                `json:"password" validate:"required,min=8,max=72,password"`
+	Name      string                `json:"name" validate:"required,min=3,max=100"`
 	Slug      string                `json:"slug"`
-	Phone     string                `json:"phone"`
-	MainImage *ImageCreateRequest   `json:"main_image"`
-	Address   *AddressCreateRequest `json:"address"`
+	Phone     string                `json:"phone" validate:"required,phone"`
+	MainImage *ImageCreateRequest   `json:"main_image" validate:"required"`
+	Address   *AddressCreateRequest `json:"address" validate:"required"`
 }
 
 type MerchantUpdateRequest struct {
 	Id        string                `json:"id"`
 	UpdatedAt int                   `json:"updated_at"`
-	Name      string                `json:"name"`
-	Phone     string                `json:"phone"`
-	Address   *AddressUpdateRequest `json:"address"`
+	Name      string                `json:"name" validate:"required,min=3,max=100"`
+	Phone     string                `json:"phone" validate:"required,phone"`
+	Address   *AddressUpdateRequest `json:"address" validate:"required"`
 }
 
 type MerchantUpdateImageRequest struct {
@@ -2,21 +2,64 @@ package web
 
 // Response
 
-type OrderProductResponse struct {
-	Id          string          `json:"id"`
-	CreatedAt   int             `json:"created_at"`
-	UpdatedAt   int             `json:"updated_at"`
-	ProductId   string          `json:"product_id"`
-	Name        string          `json:"name"`
-	Slug        string          `json:"slug"`
-	Description string          `json:"description"`
-	Price       int             `json:"price"`
-	Quantity    int             `json:"quantity"`
-	MainImage   ImageResponse   `json:"main_image"`
-	Address     AddressResponse `json:"address"`
+type OrderShippingResponse struct {
+	Courier        string `json:"courier"`
+	Service        string `json:"service"`
+	TrackingNumber string `json:"tracking_number"`
+}
+
+type OrderHistoryResponse struct {
+	CreatedAt int    `json:"created_at"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+type OrderItemResponse struct {
+	ProductId   string        `json:"product_id"`
+	VariantId   string        `json:"variant_id"`
+	VariantName string        `json:"variant_name"`
+	Name        string        `json:"name"`
+	Slug        string        `json:"slug"`
+	Description string        `json:"description"`
+	Price       int           `json:"price"`
+	Quantity    int           `json:"quantity"`
+	MainImage   ImageResponse `json:"main_image"`
+}
+
+type OrderDetailResponse struct {
+	Id            string                 `json:"id"`
+	CreatedAt     int                    `json:"created_at"`
+	UpdatedAt     int                    `json:"updated_at"`
+	TransactionId string                 `json:"transaction_id"`
+	MerchantId    string                 `json:"merchant_id"`
+	Status        string                 `json:"status"`
+	Items         []OrderItemResponse    `json:"items"`
+	Subtotal      int                    `json:"subtotal"`
+	ShippingFee   int                    `json:"shipping_fee"`
+	Discount      int                    `json:"discount"`
+	Total         int                    `json:"total"`
+	Address       AddressResponse        `json:"address"`
+	Shipping      OrderShippingResponse  `json:"shipping"`
+	History       []OrderHistoryResponse `json:"history"`
 }
 
 type OrderResponse struct {
-	CustomerId string                 `json:"customer_id"`
-	Products   []OrderProductResponse `json:"products"`
+	CustomerId string                     `json:"customer_id"`
+	Orders     []OrderDetailResponse      `json:"orders"`
+	Metadata   MetadataPaginationResponse `json:"metadata"`
+}
+
+// Request
+
+type OrderConfirmRequest struct {
+	Id         string `json:"id"`
+	CustomerId string `json:"customer_id"`
+	UpdatedAt  int    `json:"updated_at"`
+}
+
+type OrderRefundRequest struct {
+	Id         string `json:"id"`
+	CustomerId string `json:"customer_id"`
+	UpdatedAt  int    `json:"updated_at"`
+	Note       string `json:"note" validate:"required,max=255"`
 }
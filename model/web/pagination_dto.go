@@ -0,0 +1,11 @@
+package web
+
+// MetadataPaginationResponse pages by number, the lists paged by cursor leave CurrentPage out and hand out the cursors
+// of the pages around instead, an empty cursor means there is no such page
+type MetadataPaginationResponse struct {
+	CurrentPage int    `json:"current_page,omitempty"`
+	PerPage     int    `json:"per_page"`
+	TotalData   int    `json:"total_data"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	PrevCursor  string `json:"prev_cursor,omitempty"`
+}
@@ -0,0 +1,47 @@
+package web
+
+// Response
+
+type PayoutHistoryResponse struct {
+	CreatedAt int    `json:"created_at"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+type PayoutResponse struct {
+	Id            string                  `json:"id"`
+	CreatedAt     int                     `json:"created_at"`
+	UpdatedAt     int                     `json:"updated_at"`
+	MerchantId    string                  `json:"merchant_id"`
+	Amount        int64                   `json:"amount"`
+	Status        string                  `json:"status"`
+	BankName      string                  `json:"bank_name"`
+	AccountNumber string                  `json:"account_number"`
+	AccountName   string                  `json:"account_name"`
+	History       []PayoutHistoryResponse `json:"history"`
+}
+
+type PayoutFindAllResponse struct {
+	MerchantId string                     `json:"merchant_id"`
+	Payouts    []PayoutResponse           `json:"payouts"`
+	Metadata   MetadataPaginationResponse `json:"metadata"`
+}
+
+// Request
+
+type PayoutCreateRequest struct {
+	CreatedAt     int    `json:"created_at"`
+	UpdatedAt     int    `json:"updated_at"`
+	MerchantId    string `json:"merchant_id"`
+	Amount        int64  `json:"amount" validate:"required,min=10000"`
+	BankName      string `json:"bank_name" validate:"required,max=50"`
+	AccountNumber string `json:"account_number" validate:"required,numeric,max=30"`
+	AccountName   string `json:"account_name" validate:"required,max=100"`
+}
+
+type PayoutUpdateStatusRequest struct {
+	Id        string `json:"id"`
+	UpdatedAt int    `json:"updated_at"`
+	Status    string `json:"status" validate:"required,oneof=processing paid rejected"`
+	Note      string `json:"note" validate:"max=255"`
+}
@@ -2,6 +2,20 @@ package web
 
 // Response
 
+type ProductVariantOptionResponse struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type ProductVariantResponse struct {
+	Id      string                         `json:"id"`
+	Sku     string                         `json:"sku"`
+	Options []ProductVariantOptionResponse `json:"options"`
+	Price   int                            `json:"price"`
+	Stock   int                            `json:"stock"`
+	Image   ImageResponse                  `json:"image"`
+}
+
 type ProductDetailResponse struct {
 	Id          string                   `json:"id"`
 	CreatedAt   int                      `json:"created_at"`
@@ -11,9 +25,15 @@ type ProductDetailResponse struct {
 	Description string                   `json:"description"`
 	Price       int                      `json:"price"`
 	Stock       int                      `json:"stock"`
+	Weight      int                      `json:"weight"`
+	Length      int                      `json:"length"`
+	Width       int                      `json:"width"`
+	Height      int                      `json:"height"`
 	MainImage   ImageResponse            `json:"main_image"`
 	Images      []ImageResponse          `json:"images"`
 	Categories  []CategorySimpleResponse `json:"categories"`
+	Variants    []ProductVariantResponse `json:"variants"`
+	Rating      RatingResponse           `json:"rating"`
 	Merchant    MerchantSimpleResponse   `json:"merchant"`
 }
 
@@ -28,35 +48,66 @@ type ProductSimpleResponse struct {
 	MainImage   ImageResponse `json:"main_image"`
 }
 
-type MetadataPaginationResponse struct {
-	CurrentPage int `json:"current_page"`
-	PerPage     int `json:"per_page"`
-	TotalData   int `json:"total_data"`
+type ProductCategoryFacetResponse struct {
+	CategoryId string `json:"category_id"`
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+}
+
+// ProductPriceFacetResponse counts the products priced from Min up to but not including Max, a zero Max has no upper bound
+type ProductPriceFacetResponse struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+type ProductFacetResponse struct {
+	Categories []ProductCategoryFacetResponse `json:"categories"`
+	Prices     []ProductPriceFacetResponse    `json:"prices"`
 }
 
 type ProductFindAllResponse struct {
 	Products []ProductSimpleResponse    `json:"products"`
+	Facets   ProductFacetResponse       `json:"facets"`
 	Metadata MetadataPaginationResponse `json:"metadata"`
 }
 
 // Request
 
+type ProductSearchRequest struct {
+	Search      string   `json:"search"`
+	CategoryIds []string `json:"category_ids" validate:"dive,objectid"`
+	MerchantId  string   `json:"merchant_id" validate:"omitempty,objectid"`
+	MinPrice    int      `json:"min_price" validate:"min=0"`
+	MaxPrice    int      `json:"max_price" validate:"omitempty,gtefield=MinPrice"`
+	InStock     bool     `json:"in_stock"`
+	City        string   `json:"city"`
+	Province    string   `json:"province"`
+	Sort        string   `json:"sort" validate:"omitempty,oneof=relevance newest price_asc price_desc best_selling"`
+	Cursor      string   `json:"cursor"`
+	PerPage     int      `json:"per_page" validate:"min=1,max=100"`
+}
+
 type ProductCategoryCreateRequest struct {
-	CategoryId string `json:"category_id"`
+	CategoryId string `json:"category_id" validate:"required,objectid"`
 }
 
 type ProductCreateRequest struct {
 	CreatedAt   int                            `json:"created_at"`
 	UpdatedAt   int                            `json:"updated_at"`
-	MerchantId  string                         `json:"merchant_id"`
-	Name        string                         `json:"name"`
+	MerchantId  string                         `json:"merchant_id" validate:"required,objectid"`
+	Name        string                         `json:"name" validate:"required,min=3,max=100"`
 	Slug        string                         `json:"slug"`
-	Description string                         `json:"description"`
-	Price       int                            `json:"price"`
-	Stock       int                            `json:"stock"`
-	MainImage   *ImageCreateRequest            `json:"main_image"`
-	Images      []ImageCreateRequest           `json:"images"`
-	Categories  []ProductCategoryCreateRequest `json:"categories"`
+	Description string                         `json:"description" validate:"required"`
+	Price       int                            `json:"price" validate:"gt=0"`
+	Stock       int                            `json:"stock" validate:"gte=0"`
+	Weight      int                            `json:"weight" validate:"gt=0"`
+	Length      int                            `json:"length" validate:"gte=0"`
+	Width       int                            `json:"width" validate:"gte=0"`
+	Height      int                            `json:"height" validate:"gte=0"`
+	MainImage   *ImageCreateRequest            `json:"main_image" validate:"required"`
+	Images      []ImageCreateRequest           `json:"images" validate:"dive"`
+	Categories  []ProductCategoryCreateRequest `json:"categories" validate:"dive"`
 }
 
 type ProductCreateRequestResponse struct {
@@ -69,23 +120,31 @@ type ProductCreateRequestResponse struct {
 	Description string                         `json:"description"`
 	Price       int                            `json:"price"`
 	Stock       int                            `json:"stock"`
+	Weight      int                            `json:"weight"`
+	Length      int                            `json:"length"`
+	Width       int                            `json:"width"`
+	Height      int                            `json:"height"`
 	MainImage   ImageResponse                  `json:"main_image"`
 	Images      []ImageResponse                `json:"images"`
 	Categories  []ProductCategoryCreateRequest `json:"categories"`
 }
 
 type ProductCategoryUpdateRequest struct {
-	CategoryId string `json:"category_id"`
+	CategoryId string `json:"category_id" validate:"required,objectid"`
 }
 
 type ProductUpdateRequest struct {
 	Id          string                         `json:"id"`
 	UpdatedAt   int                            `json:"updated_at"`
-	Name        string                         `json:"name"`
-	Description string                         `json:"description"`
-	Price       int                            `json:"price"`
-	Stock       int                            `json:"stock"`
-	Categories  []ProductCategoryUpdateRequest `json:"categories"`
+	Name        string                         `json:"name" validate:"required,min=3,max=100"`
+	Description string                         `json:"description" validate:"required"`
+	Price       int                            `json:"price" validate:"gt=0"`
+	Stock       int                            `json:"stock" validate:"gte=0"`
+	Weight      int                            `json:"weight" validate:"gt=0"`
+	Length      int                            `json:"length" validate:"gte=0"`
+	Width       int                            `json:"width" validate:"gte=0"`
+	Height      int                            `json:"height" validate:"gte=0"`
+	Categories  []ProductCategoryUpdateRequest `json:"categories" validate:"dive"`
 }
 
 type ProductUpdateImageRequest struct {
@@ -99,3 +158,27 @@ type ProductUpdateImageRequestResponse struct {
 	UpdatedAt int           `json:"updated_at"`
 	MainImage ImageResponse `json:"main_image"`
 }
+
+type ProductVariantOptionRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Value string `json:"value" validate:"required,max=50"`
+}
+
+type ProductVariantCreateRequest struct {
+	ProductId string                        `json:"product_id"`
+	Sku       string                        `json:"sku" validate:"required,max=64"`
+	Options   []ProductVariantOptionRequest `json:"options" validate:"required,min=1,max=3,dive"`
+	Price     int                           `json:"price" validate:"gt=0"`
+	Stock     int                           `json:"stock" validate:"gte=0"`
+	ImageId   string                        `json:"image_id" validate:"omitempty,objectid"`
+}
+
+type ProductVariantUpdateRequest struct {
+	Id        string                        `json:"id"`
+	ProductId string                        `json:"product_id"`
+	Sku       string                        `json:"sku" validate:"required,max=64"`
+	Options   []ProductVariantOptionRequest `json:"options" validate:"required,min=1,max=3,dive"`
+	Price     int                           `json:"price" validate:"gt=0"`
+	Stock     int                           `json:"stock" validate:"gte=0"`
+	ImageId   string                        `json:"image_id" validate:"omitempty,objectid"`
+}
@@ -0,0 +1,64 @@
+package web
+
+// Response
+
+type ReviewReplyResponse struct {
+	CreatedAt int    `json:"created_at"`
+	UpdatedAt int    `json:"updated_at"`
+	Text      string `json:"text"`
+}
+
+type ReviewResponse struct {
+	Id           string               `json:"id"`
+	CreatedAt    int                  `json:"created_at"`
+	UpdatedAt    int                  `json:"updated_at"`
+	OrderId      string               `json:"order_id"`
+	ProductId    string               `json:"product_id"`
+	MerchantId   string               `json:"merchant_id"`
+	CustomerId   string               `json:"customer_id"`
+	CustomerName string               `json:"customer_name"`
+	Rating       int                  `json:"rating"`
+	Text         string               `json:"text"`
+	Images       []ImageResponse      `json:"images"`
+	Reply        *ReviewReplyResponse `json:"reply"`
+}
+
+type RatingResponse struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+type ReviewFindAllResponse struct {
+	ProductId string                     `json:"product_id"`
+	Rating    RatingResponse             `json:"rating"`
+	Reviews   []ReviewResponse           `json:"reviews"`
+	Metadata  MetadataPaginationResponse `json:"metadata"`
+}
+
+// Request
+
+type ReviewCreateRequest struct {
+	CreatedAt  int                  `json:"created_at"`
+	UpdatedAt  int                  `json:"updated_at"`
+	CustomerId string               `json:"customer_id"`
+	OrderId    string               `json:"order_id" validate:"required,objectid"`
+	ProductId  string               `json:"product_id" validate:"required,objectid"`
+	Rating     int                  `json:"rating" validate:"required,min=1,max=5"`
+	Text       string               `json:"text" validate:"max=1000"`
+	Images     []ImageCreateRequest `json:"images" validate:"max=5,dive"`
+}
+
+type ReviewUpdateRequest struct {
+	Id         string `json:"id"`
+	UpdatedAt  int    `json:"updated_at"`
+	CustomerId string `json:"customer_id"`
+	Rating     int    `json:"rating" validate:"required,min=1,max=5"`
+	Text       string `json:"text" validate:"max=1000"`
+}
+
+type ReviewReplyRequest struct {
+	Id         string `json:"id"`
+	UpdatedAt  int    `json:"updated_at"`
+	MerchantId string `json:"merchant_id"`
+	Text       string `json:"text" validate:"required,max=1000"`
+}
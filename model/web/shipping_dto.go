@@ -0,0 +1,31 @@
+package web
+
+// Response
+
+type ShippingRateResponse struct {
+	Courier string `json:"courier"`
+	Service string `json:"service"`
+	Fee     int    `json:"fee"`
+	Etd     string `json:"etd"`
+}
+
+// ShippingPackageResponse is the package one merchant of the cart sends, its weight is in grams
+type ShippingPackageResponse struct {
+	MerchantId   string                 `json:"merchant_id"`
+	MerchantName string                 `json:"merchant_name"`
+	Weight       int                    `json:"weight"`
+	Rates        []ShippingRateResponse `json:"rates"`
+}
+
+type ShippingQuoteResponse struct {
+	CustomerId string                    `json:"customer_id"`
+	Province   string                    `json:"province"`
+	Packages   []ShippingPackageResponse `json:"packages"`
+}
+
+// Request
+
+type ShippingQuoteRequest struct {
+	CustomerId string `json:"customer_id" validate:"required,objectid"`
+	Province   string `json:"province" validate:"required,max=100"`
+}
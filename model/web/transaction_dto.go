@@ -4,6 +4,9 @@ package web
 
 type TransactionProductResponse struct {
 	ProductId   string        `json:"product_id"`
+	VariantId   string        `json:"variant_id"`
+	VariantName string        `json:"variant_name"`
+	MerchantId  string        `json:"merchant_id"`
 	Name        string        `json:"name"`
 	Slug        string        `json:"slug"`
 	Description string        `json:"description"`
@@ -12,30 +15,92 @@ type TransactionProductResponse struct {
 	MainImage   ImageResponse `json:"main_image"`
 }
 
+type BankTransferPaymentResponse struct {
+	Bank     string `json:"bank"`
+	VANumber string `json:"va_number"`
+}
+
+type EWalletPaymentResponse struct {
+	Deeplink string `json:"deeplink"`
+	QRCode   string `json:"qr_code"`
+}
+
+type QrisPaymentResponse struct {
+	QRCode string `json:"qr_code"`
+}
+
+type CardPaymentResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+// TransactionPaymentResponse only has the instructions of the chosen method, the others are left out
+type TransactionPaymentResponse struct {
+	Method       string                       `json:"method"`
+	ExpiredAt    int                          `json:"expired_at"`
+	BankTransfer *BankTransferPaymentResponse `json:"bank_transfer,omitempty"`
+	EWallet      *EWalletPaymentResponse      `json:"e_wallet,omitempty"`
+	Qris         *QrisPaymentResponse         `json:"qris,omitempty"`
+	Card         *CardPaymentResponse         `json:"card,omitempty"`
+}
+
+type TransactionOrderResponse struct {
+	MerchantId  string `json:"merchant_id"`
+	Courier     string `json:"courier"`
+	Service     string `json:"service"`
+	Subtotal    int    `json:"subtotal"`
+	ShippingFee int    `json:"shipping_fee"`
+	Discount    int    `json:"discount"`
+	Total       int    `json:"total"`
+}
+
+type TransactionVoucherResponse struct {
+	Code       string `json:"code"`
+	MerchantId string `json:"merchant_id"`
+	Discount   int    `json:"discount"`
+}
+
 type TransactionDetailResponse struct {
 	Id          string                       `json:"id"`
 	CreatedAt   int                          `json:"created_at"`
 	UpdatedAt   int                          `json:"updated_at"`
 	PaymentType string                       `json:"payment_type"`
 	Status      string                       `json:"status"`
-	QRCode      string                       `json:"qr_code"`
+	Payment     TransactionPaymentResponse   `json:"payment"`
 	TotalPrice  int                          `json:"total_price"`
 	Products    []TransactionProductResponse `json:"products"`
+	Orders      []TransactionOrderResponse   `json:"orders"`
+	Voucher     *TransactionVoucherResponse  `json:"voucher"`
 	Address     AddressResponse              `json:"address"`
 }
 
 type TransactionResponse struct {
 	CustomerId   string                      `json:"customer_id"`
 	Transactions []TransactionDetailResponse `json:"transactions"`
+	Metadata     MetadataPaginationResponse  `json:"metadata"`
 }
 
 // Request
 
+type TransactionPaymentRequest struct {
+	Method      string `json:"payment_method" validate:"omitempty,oneof=qris bca_va bni_va bri_va permata_va gopay shopeepay credit_card"`
+	CardTokenId string `json:"card_token_id" validate:"required_if=Method credit_card"`
+	CallbackURL string `json:"callback_url" validate:"omitempty,url,max=255"`
+}
+
+// TransactionShippingRequest is the courier service every merchant of the checkout ships with
+type TransactionShippingRequest struct {
+	Courier string `json:"courier" validate:"required,max=50"`
+	Service string `json:"service" validate:"required,max=50"`
+}
+
 type TransactionCreateRequest struct {
-	CreatedAt  int                   `json:"created_at"`
-	UpdatedAt  int                   `json:"updated_at"`
-	CustomerId string                `json:"customer_id"`
-	Address    *AddressCreateRequest `json:"address"`
+	CreatedAt   int                         `json:"created_at"`
+	UpdatedAt   int                         `json:"updated_at"`
+	CustomerId  string                      `json:"customer_id" validate:"required,objectid"`
+	Address     *AddressCreateRequest       `json:"address" validate:"required"`
+	Payment     *TransactionPaymentRequest  `json:"payment" validate:"required"`
+	Shipping    *TransactionShippingRequest `json:"shipping" validate:"required"`
+	VoucherCode string                      `json:"voucher_code" validate:"omitempty,max=30"`
 }
 
 type TransactionCreateRequestResponse struct {
@@ -43,7 +108,9 @@ type TransactionCreateRequestResponse struct {
 	UpdatedAt   int                         `json:"updated_at"`
 	PaymentType string                      `json:"payment_type"`
 	Status      string                      `json:"status"`
-	QRCode      string                       `json:"qr_code"`
+	Payment     TransactionPaymentResponse  `json:"payment"`
 	TotalPrice  int                         `json:"total_price"`
+	Orders      []TransactionOrderResponse  `json:"orders"`
+	Voucher     *TransactionVoucherResponse `json:"voucher"`
 	Address     AddressCreateRequest        `json:"address"`
 }
@@ -0,0 +1,73 @@
+package web
+
+// Response
+
+type VoucherResponse struct {
+	Id               string   `json:"id"`
+	CreatedAt        int      `json:"created_at"`
+	UpdatedAt        int      `json:"updated_at"`
+	Code             string   `json:"code"`
+	MerchantId       string   `json:"merchant_id"`
+	CategoryIds      []string `json:"category_ids"`
+	Type             string   `json:"type"`
+	Value            int      `json:"value"`
+	MinSpend         int      `json:"min_spend"`
+	MaxDiscount      int      `json:"max_discount"`
+	StartAt          int      `json:"start_at"`
+	EndAt            int      `json:"end_at"`
+	UsageLimit       int      `json:"usage_limit"`
+	PerCustomerLimit int      `json:"per_customer_limit"`
+	UsedCount        int      `json:"used_count"`
+}
+
+type VoucherFindAllResponse struct {
+	MerchantId string                     `json:"merchant_id"`
+	Vouchers   []VoucherResponse          `json:"vouchers"`
+	Metadata   MetadataPaginationResponse `json:"metadata"`
+}
+
+type VoucherValidateResponse struct {
+	Code       string `json:"code"`
+	MerchantId string `json:"merchant_id"`
+	Subtotal   int    `json:"subtotal"`
+	Discount   int    `json:"discount"`
+}
+
+// Request
+
+type VoucherCreateRequest struct {
+	CreatedAt        int      `json:"created_at"`
+	UpdatedAt        int      `json:"updated_at"`
+	MerchantId       string   `json:"merchant_id"`
+	Code             string   `json:"code" validate:"required,alphanum,min=3,max=30"`
+	CategoryIds      []string `json:"category_ids" validate:"max=20,dive,objectid"`
+	Type             string   `json:"type" validate:"required,oneof=percentage fixed"`
+	Value            int      `json:"value" validate:"required,gt=0"`
+	MinSpend         int      `json:"min_spend" validate:"gte=0"`
+	MaxDiscount      int      `json:"max_discount" validate:"gte=0"`
+	StartAt          int      `json:"start_at" validate:"required"`
+	EndAt            int      `json:"end_at" validate:"required,gtfield=StartAt"`
+	UsageLimit       int      `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int      `json:"per_customer_limit" validate:"gte=0"`
+}
+
+type VoucherUpdateRequest struct {
+	Id               string   `json:"id"`
+	UpdatedAt        int      `json:"updated_at"`
+	MerchantId       string   `json:"merchant_id"`
+	Code             string   `json:"code" validate:"required,alphanum,min=3,max=30"`
+	CategoryIds      []string `json:"category_ids" validate:"max=20,dive,objectid"`
+	Type             string   `json:"type" validate:"required,oneof=percentage fixed"`
+	Value            int      `json:"value" validate:"required,gt=0"`
+	MinSpend         int      `json:"min_spend" validate:"gte=0"`
+	MaxDiscount      int      `json:"max_discount" validate:"gte=0"`
+	StartAt          int      `json:"start_at" validate:"required"`
+	EndAt            int      `json:"end_at" validate:"required,gtfield=StartAt"`
+	UsageLimit       int      `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int      `json:"per_customer_limit" validate:"gte=0"`
+}
+
+type VoucherValidateRequest struct {
+	CustomerId string `json:"customer_id" validate:"required,objectid"`
+	Code       string `json:"code" validate:"required,max=30"`
+}
@@ -1,7 +1,14 @@
 package web
 
 type WebResponse struct {
-	Code   int         `json:"code"`
-	Status string      `json:"status"`
-	Data   interface{} `json:"data"`
+	Code      int                  `json:"code"`
+	Status    string               `json:"status"`
+	ErrorCode string               `json:"error_code,omitempty"`
+	Data      interface{}          `json:"data"`
+	Errors    []FieldErrorResponse `json:"errors,omitempty"`
+}
+
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
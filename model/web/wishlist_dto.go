@@ -0,0 +1,22 @@
+package web
+
+// Response
+
+type WishlistResponse struct {
+	CustomerId string                  `json:"customer_id"`
+	Products   []ProductSimpleResponse `json:"products"`
+}
+
+// Request
+
+type WishlistProductCreateRequest struct {
+	CreatedAt  int    `json:"created_at"`
+	CustomerId string `json:"customer_id" validate:"required,objectid"`
+	ProductId  string `json:"product_id" validate:"required,objectid"`
+}
+
+type WishlistMoveToCartRequest struct {
+	CustomerId string `json:"customer_id" validate:"required,objectid"`
+	ProductId  string `json:"product_id" validate:"required,objectid"`
+	VariantId  string `json:"variant_id" validate:"omitempty,objectid"`
+}
@@ -1,20 +1,35 @@
 package pkg
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"os"
+	"time"
 	"weplant-backend/helper"
 	"weplant-backend/model/web"
 )
 
+const (
+	AccessTokenDuration  = 15 * time.Minute
+	RefreshTokenDuration = 30 * 24 * time.Hour
+)
+
 var secretKey = []byte(os.Getenv("JWT_SECRET_KEY"))
 
 func GenerateToken(payload web.JWTPayload) string {
+	now := time.Now()
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"id":   payload.Id,
 		"role": payload.Role,
+		"iat":  now.Unix(),
+		"exp":  now.Add(AccessTokenDuration).Unix(),
+		"jti":  primitive.NewObjectID().Hex(),
 	})
 	tokenString, err := token.SignedString(secretKey)
 	helper.PanicIfError(err)
@@ -38,9 +53,30 @@ func ValidateToken(tokenString string) (web.JWTPayload, error) {
 	claims, ok := token.Claims.(jwt.MapClaims)
 
 	if ok && token.Valid {
-		payload.Id = claims["id"].(string)
-		payload.Role = claims["role"].(string)
+		if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+			return payload, errors.New("token has no expiration")
+		}
+		id, okId := claims["id"].(string)
+		role, okRole := claims["role"].(string)
+		if !okId || !okRole {
+			return payload, errors.New("token claims are invalid")
+		}
+		payload.Id = id
+		payload.Role = role
 		return payload, nil
 	}
-	return payload, err
+	return payload, errors.New("token is invalid")
+}
+
+// GenerateRefreshToken returns an opaque random token, only its hash is stored
+func GenerateRefreshToken() string {
+	bytes := make([]byte, 32)
+	_, err := rand.Read(bytes)
+	helper.PanicIfError(err)
+	return hex.EncodeToString(bytes)
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
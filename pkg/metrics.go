@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "weplant_http_requests_total",
+		Help: "HTTP requests by route pattern and status.",
+	}, []string{"method", "route", "status"})
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "weplant_http_request_duration_seconds",
+		Help:    "HTTP request latency by route pattern and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	MongoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "weplant_mongo_operation_duration_seconds",
+		Help:    "MongoDB command latency by the repository method sending it.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"repository", "method", "command", "result"})
+	ExternalCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "weplant_external_calls_total",
+		Help: "Calls to Midtrans and the image storage by result.",
+	}, []string{"service", "operation", "result"})
+
+	CheckoutsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "weplant_checkouts_created_total",
+		Help: "Transactions charged at Midtrans.",
+	}, []string{"payment_method"})
+	PaymentCallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "weplant_payment_callbacks_total",
+		Help: "Verified Midtrans notifications by transaction status.",
+	}, []string{"status"})
+	OrdersPaid = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "weplant_orders_paid_total",
+		Help: "Merchant orders created from paid transactions.",
+	})
+	StockOuts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "weplant_stock_outs_total",
+		Help: "Products or variants running out of stock, and checkouts turned down for the lack of it.",
+	}, []string{"reason"})
+)
+
+const (
+	StockOutSoldOut      = "sold_out"
+	StockOutInsufficient = "insufficient"
+	StockOutSetToZero    = "set_to_zero"
+)
+
+// ObserveExternalCall counts a call to service, failed tells whether it returned an error
+func ObserveExternalCall(service string, operation string, failed bool) {
+	result := "success"
+	if failed {
+		result = "error"
+	}
+	ExternalCalls.WithLabelValues(service, operation, result).Inc()
+}
+
+// MetricsHandler serves the metrics in the Prometheus text format
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"weplant-backend/helper"
+)
+
+var phoneRegex = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,10}$`)
+var postalCodeRegex = regexp.MustCompile(`^[1-9][0-9]{4}$`)
+
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+
+	// report fields by their json name so the client can map errors back to the request body
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	err := validate.RegisterValidation("password", validatePassword)
+	helper.PanicIfError(err)
+	err = validate.RegisterValidation("phone", validatePhone)
+	helper.PanicIfError(err)
+	err = validate.RegisterValidation("postal_code", validatePostalCode)
+	helper.PanicIfError(err)
+	err = validate.RegisterValidation("objectid", validateObjectID)
+	helper.PanicIfError(err)
+
+	return validate
+}
+
+// password must contain at least one letter and one digit, the length is checked with min/max
+func validatePassword(field validator.FieldLevel) bool {
+	var hasLetter, hasDigit bool
+	for _, c := range field.Field().String() {
+		switch {
+		case unicode.IsLetter(c):
+			hasLetter = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		}
+	}
+	return hasLetter && hasDigit
+}
+
+// indonesian mobile number, e.g. 081234567890 or +6281234567890
+func validatePhone(field validator.FieldLevel) bool {
+	return phoneRegex.MatchString(field.Field().String())
+}
+
+// indonesian postal code, five digits
+func validatePostalCode(field validator.FieldLevel) bool {
+	return postalCodeRegex.MatchString(field.Field().String())
+}
+
+func validateObjectID(field validator.FieldLevel) bool {
+	return primitive.IsValidObjectID(field.Field().String())
+}
@@ -9,6 +9,8 @@ type CategoryRepository interface {
 	Create(ctx context.Context, category schema.Category) (schema.Category, error)
 	FindById(ctx context.Context, categoryId string) (schema.Category, error)
 	FindAll(ctx context.Context) ([]schema.Category, error)
+	FindPage(ctx context.Context, page Page) ([]schema.Category, PageInfo, error)
+	CountDocuments(ctx context.Context) (int, error)
 	Update(ctx context.Context, category schema.Category) (schema.Category, error)
 	Delete(ctx context.Context, categoryId string) error
 }
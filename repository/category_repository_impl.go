@@ -20,6 +20,7 @@ func NewCategoryRepository(collection *mongo.Collection) CategoryRepository {
 }
 
 func (repository *CategoryRepositoryImpl) Create(ctx context.Context, category schema.Category) (schema.Category, error) {
+	ctx = withOperation(ctx, "CategoryRepository", "Create")
 	res, err := repository.Collection.InsertOne(ctx, category)
 	if err != nil {
 		return category, err
@@ -29,6 +30,7 @@ func (repository *CategoryRepositoryImpl) Create(ctx context.Context, category s
 }
 
 func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, categoryId string) (schema.Category, error) {
+	ctx = withOperation(ctx, "CategoryRepository", "FindById")
 	var category schema.Category
 	objectId := helper.ObjectIDFromHex(categoryId)
 	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&category)
@@ -39,6 +41,7 @@ func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, category
 }
 
 func (repository *CategoryRepositoryImpl) FindAll(ctx context.Context) ([]schema.Category, error) {
+	ctx = withOperation(ctx, "CategoryRepository", "FindAll")
 	var categories []schema.Category
 	cursor, err := repository.Collection.Find(ctx, bson.D{})
 	if err != nil {
@@ -51,8 +54,26 @@ func (repository *CategoryRepositoryImpl) FindAll(ctx context.Context) ([]schema
 	return categories, nil
 }
 
+// FindPage sorts the categories by name
+func (repository *CategoryRepositoryImpl) FindPage(ctx context.Context, page Page) ([]schema.Category, PageInfo, error) {
+	ctx = withOperation(ctx, "CategoryRepository", "FindPage")
+	var categories []schema.Category
+	pageInfo, err := findPage(ctx, repository.Collection, bson.D{}, page, "name", 1, &categories)
+	return categories, pageInfo, err
+}
+
+func (repository *CategoryRepositoryImpl) CountDocuments(ctx context.Context) (int, error) {
+	ctx = withOperation(ctx, "CategoryRepository", "CountDocuments")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
 func (repository *CategoryRepositoryImpl) Update(ctx context.Context, category schema.Category) (schema.Category, error) {
-	_, err := repository.Collection.UpdateByID(ctx, category.Id, bson.D{{"$set", category}})
+	ctx = withOperation(ctx, "CategoryRepository", "Update")
+	_, err := repository.Collection.ReplaceOne(ctx, bson.D{{"_id", category.Id}}, category)
 	if err != nil {
 		return category, err
 	}
@@ -60,6 +81,7 @@ func (repository *CategoryRepositoryImpl) Update(ctx context.Context, category s
 }
 
 func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, categoryId string) error {
+	ctx = withOperation(ctx, "CategoryRepository", "Delete")
 	objectId := helper.ObjectIDFromHex(categoryId)
 	_, err := repository.Collection.DeleteOne(ctx, bson.D{{"_id", objectId}})
 	if err != nil {
@@ -3,15 +3,19 @@ package repository
 import (
 	"context"
 	"github.com/cloudinary/cloudinary-go"
+	"github.com/cloudinary/cloudinary-go/api"
+	"github.com/cloudinary/cloudinary-go/api/admin"
 	"github.com/cloudinary/cloudinary-go/api/uploader"
 	"os"
+	"strings"
+	"weplant-backend/pkg"
 )
 
 type CloudinaryRepositoryImpl struct {
 	Cloud *cloudinary.Cloudinary
 }
 
-func NewCloudinaryRepository(cloud *cloudinary.Cloudinary) CloudinaryRepository {
+func NewCloudinaryRepository(cloud *cloudinary.Cloudinary) ImageRepository {
 	return &CloudinaryRepositoryImpl{
 		Cloud: cloud,
 	}
@@ -23,6 +27,7 @@ func (repository *CloudinaryRepositoryImpl) UploadImage(ctx context.Context, fil
 	res, err := repository.Cloud.Upload.Upload(ctx, file, uploader.UploadParams{
 		PublicID: cloudFolder + "/" + filename,
 	})
+	pkg.ObserveExternalCall("cloudinary", "upload", err != nil || res.Error.Message != "")
 	url = res.SecureURL
 	if err != nil {
 		return url, err
@@ -45,11 +50,41 @@ func (repository *CloudinaryRepositoryImpl) UploadImage(ctx context.Context, fil
 
 func (repository *CloudinaryRepositoryImpl) DeleteImage(ctx context.Context, filename string) error {
 	cloudFolder := os.Getenv("CLOUDINARY_FOLDER")
-	_, err := repository.Cloud.Upload.Destroy(ctx, uploader.DestroyParams{
+	res, err := repository.Cloud.Upload.Destroy(ctx, uploader.DestroyParams{
 		PublicID: cloudFolder + "/" + filename,
 	})
+	pkg.ObserveExternalCall("cloudinary", "delete", err != nil || res.Error.Message != "")
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (repository *CloudinaryRepositoryImpl) ListImages(ctx context.Context) ([]StoredImage, error) {
+	prefix := os.Getenv("CLOUDINARY_FOLDER") + "/"
+	var images []StoredImage
+	var nextCursor string
+	for {
+		res, err := repository.Cloud.Admin.Assets(ctx, admin.AssetsParams{
+			AssetType:    api.Image,
+			DeliveryType: "upload",
+			Prefix:       prefix,
+			MaxResults:   500,
+			NextCursor:   nextCursor,
+		})
+		pkg.ObserveExternalCall("cloudinary", "list", err != nil || res.Error.Message != "")
+		if err != nil {
+			return nil, err
+		}
+		for _, asset := range res.Assets {
+			images = append(images, StoredImage{
+				FileName:  strings.TrimPrefix(asset.PublicID, prefix),
+				CreatedAt: asset.CreatedAt,
+			})
+		}
+		if res.NextCursor == "" {
+			return images, nil
+		}
+		nextCursor = res.NextCursor
+	}
+}
@@ -15,13 +15,12 @@ type CustomerRepository interface {
 	// Cart
 	PushProductToCart(ctx context.Context, customerId string, product schema.CartProduct) error
 	UpdateProductQuantity(ctx context.Context, customerId string, product schema.CartProduct) error
-	PullProductFromCart(ctx context.Context, customerId string, productId string) error
+	PullProductFromCart(ctx context.Context, customerId string, product schema.CartProduct) error
 	PullProductFromAllCart(ctx context.Context, productId string) error
+	PullVariantFromAllCart(ctx context.Context, productId string, variantId string) error
 
-	// Transaction
-	CreateTransaction(ctx context.Context, customerId string, transaction schema.Transaction) error
-	DeleteTransaction(ctx context.Context, customerId string, transactionId string) error
-
-	// Order
-	CreateOrder(ctx context.Context, customerId string, order schema.OrderProduct) error
+	// Wishlist
+	PushProductToWishlist(ctx context.Context, customerId string, product schema.WishlistProduct) error
+	PullProductFromWishlist(ctx context.Context, customerId string, productId string) error
+	PullProductFromAllWishlist(ctx context.Context, productId string) error
 }
@@ -20,6 +20,7 @@ func NewCustomerRepository(collection *mongo.Collection) CustomerRepository {
 }
 
 func (repository *CustomerRepositoryImpl) Create(ctx context.Context, customer schema.Customer) (schema.Customer, error) {
+	ctx = withOperation(ctx, "CustomerRepository", "Create")
 	res, err := repository.Collection.InsertOne(ctx, customer)
 	if err != nil {
 		return customer, err
@@ -29,6 +30,7 @@ func (repository *CustomerRepositoryImpl) Create(ctx context.Context, customer s
 }
 
 func (repository *CustomerRepositoryImpl) FindById(ctx context.Context, customerId string) (schema.Customer, error) {
+	ctx = withOperation(ctx, "CustomerRepository", "FindById")
 	objectId := helper.ObjectIDFromHex(customerId)
 	var customer schema.Customer
 	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&customer)
@@ -39,6 +41,7 @@ func (repository *CustomerRepositoryImpl) FindById(ctx context.Context, customer
 }
 
 func (repository *CustomerRepositoryImpl) FindByEmail(ctx context.Context, email string) (schema.Customer, error) {
+	ctx = withOperation(ctx, "CustomerRepository", "FindByEmail")
 	var customer schema.Customer
 	err := repository.Collection.FindOne(ctx, bson.D{{"email", email}}).Decode(&customer)
 	if err != nil {
@@ -48,6 +51,7 @@ func (repository *CustomerRepositoryImpl) FindByEmail(ctx context.Context, email
 }
 
 func (repository *CustomerRepositoryImpl) Update(ctx context.Context, customer schema.Customer) (schema.Customer, error) {
+	ctx = withOperation(ctx, "CustomerRepository", "Update")
 	_, err := repository.Collection.UpdateByID(ctx, customer.Id, bson.D{{"$set", customer}})
 	if err != nil {
 		return customer, err
@@ -56,6 +60,7 @@ func (repository *CustomerRepositoryImpl) Update(ctx context.Context, customer s
 }
 
 func (repository *CustomerRepositoryImpl) Delete(ctx context.Context, customerId string) error {
+	ctx = withOperation(ctx, "CustomerRepository", "Delete")
 	objectId := helper.ObjectIDFromHex(customerId)
 	_, err := repository.Collection.DeleteOne(ctx, bson.D{{"_id", objectId}})
 	if err != nil {
@@ -65,13 +70,27 @@ func (repository *CustomerRepositoryImpl) Delete(ctx context.Context, customerId
 }
 
 // cart
+
+// cartProductFilter matches the cart entry of the product variant, entries without a variant have no variant_id
+func cartProductFilter(product schema.CartProduct) bson.D {
+	var variantId interface{}
+	if product.VariantId != "" {
+		variantId = product.VariantId
+	}
+	return bson.D{
+		{"product_id", product.ProductId},
+		{"variant_id", variantId},
+	}
+}
+
 func (repository *CustomerRepositoryImpl) PushProductToCart(ctx context.Context, customerId string, product schema.CartProduct) error {
+	ctx = withOperation(ctx, "CustomerRepository", "PushProductToCart")
 	objectId := helper.ObjectIDFromHex(customerId)
 	_, err := repository.Collection.UpdateOne(ctx, bson.D{
 		{"$and", bson.A{
 			bson.D{{"_id", objectId}},
-			bson.D{{"carts.product_id", bson.D{
-				{"$ne", product.ProductId},
+			bson.D{{"carts", bson.D{
+				{"$not", bson.D{{"$elemMatch", cartProductFilter(product)}}},
 			}}},
 		}},
 	}, bson.D{
@@ -86,11 +105,12 @@ func (repository *CustomerRepositoryImpl) PushProductToCart(ctx context.Context,
 }
 
 func (repository *CustomerRepositoryImpl) UpdateProductQuantity(ctx context.Context, customerId string, product schema.CartProduct) error {
+	ctx = withOperation(ctx, "CustomerRepository", "UpdateProductQuantity")
 	objectId := helper.ObjectIDFromHex(customerId)
 	_, err := repository.Collection.UpdateOne(ctx, bson.D{
 		{"$and", bson.A{
 			bson.D{{"_id", objectId}},
-			bson.D{{"carts.product_id", product.ProductId}},
+			bson.D{{"carts", bson.D{{"$elemMatch", cartProductFilter(product)}}}},
 			//bson.D{{"$where", fmt.Sprintf("for (let i = 0; i < this.carts.length; i++) {if (this.carts[i].product_id == '%s' && this.carts[i].quantity+%d >= 1) {return true} }", product.ProductId, product.Quantity)}},
 		}},
 	}, bson.D{
@@ -106,11 +126,27 @@ func (repository *CustomerRepositoryImpl) UpdateProductQuantity(ctx context.Cont
 	return nil
 }
 
-func (repository *CustomerRepositoryImpl) PullProductFromCart(ctx context.Context, customerId string, productId string) error {
+func (repository *CustomerRepositoryImpl) PullProductFromCart(ctx context.Context, customerId string, product schema.CartProduct) error {
+	ctx = withOperation(ctx, "CustomerRepository", "PullProductFromCart")
 	objectId := helper.ObjectIDFromHex(customerId)
 	_, err := repository.Collection.UpdateOne(ctx, bson.D{
 		{"_id", objectId},
 	}, bson.D{
+		{
+			"$pull", bson.D{{
+				"carts", cartProductFilter(product),
+			}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repository *CustomerRepositoryImpl) PullProductFromAllCart(ctx context.Context, productId string) error {
+	ctx = withOperation(ctx, "CustomerRepository", "PullProductFromAllCart")
+	_, err := repository.Collection.UpdateMany(ctx, bson.D{}, bson.D{
 		{
 			"$pull", bson.D{{
 				"carts", bson.D{
@@ -125,12 +161,14 @@ func (repository *CustomerRepositoryImpl) PullProductFromCart(ctx context.Contex
 	return nil
 }
 
-func (repository *CustomerRepositoryImpl) PullProductFromAllCart(ctx context.Context, productId string) error {
+func (repository *CustomerRepositoryImpl) PullVariantFromAllCart(ctx context.Context, productId string, variantId string) error {
+	ctx = withOperation(ctx, "CustomerRepository", "PullVariantFromAllCart")
 	_, err := repository.Collection.UpdateMany(ctx, bson.D{}, bson.D{
 		{
 			"$pull", bson.D{{
 				"carts", bson.D{
 					{"product_id", productId},
+					{"variant_id", variantId},
 				},
 			}},
 		},
@@ -141,13 +179,16 @@ func (repository *CustomerRepositoryImpl) PullProductFromAllCart(ctx context.Con
 	return nil
 }
 
-// Transaction
-
-func (repository *CustomerRepositoryImpl) CreateTransaction(ctx context.Context, customerId string, transaction schema.Transaction) error {
+// PushProductToWishlist does nothing when the product is already in the wishlist
+func (repository *CustomerRepositoryImpl) PushProductToWishlist(ctx context.Context, customerId string, product schema.WishlistProduct) error {
+	ctx = withOperation(ctx, "CustomerRepository", "PushProductToWishlist")
 	objectId := helper.ObjectIDFromHex(customerId)
-	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
+	_, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"wishlist.product_id", bson.D{{"$ne", product.ProductId}}},
+	}, bson.D{
 		{"$push", bson.D{
-			{"transactions", transaction},
+			{"wishlist", product},
 		}},
 	})
 	if err != nil {
@@ -156,18 +197,18 @@ func (repository *CustomerRepositoryImpl) CreateTransaction(ctx context.Context,
 	return nil
 }
 
-func (repository *CustomerRepositoryImpl) DeleteTransaction(ctx context.Context, customerId string, transactionId string) error {
-	objectCustomerId := helper.ObjectIDFromHex(customerId)
-	objectTransactionId := helper.ObjectIDFromHex(transactionId)
-	_, err := repository.Collection.UpdateByID(ctx, objectCustomerId, bson.D{
+func (repository *CustomerRepositoryImpl) PullProductFromWishlist(ctx context.Context, customerId string, productId string) error {
+	ctx = withOperation(ctx, "CustomerRepository", "PullProductFromWishlist")
+	objectId := helper.ObjectIDFromHex(customerId)
+	_, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+	}, bson.D{
 		{
-			"$pull", bson.D{
-				{
-					"transactions", bson.D{
-						{"_id", objectTransactionId},
-					},
+			"$pull", bson.D{{
+				"wishlist", bson.D{
+					{"product_id", productId},
 				},
-			},
+			}},
 		},
 	})
 	if err != nil {
@@ -176,16 +217,16 @@ func (repository *CustomerRepositoryImpl) DeleteTransaction(ctx context.Context,
 	return nil
 }
 
-func (repository *CustomerRepositoryImpl) CreateOrder(ctx context.Context, customerId string, order schema.OrderProduct) error {
-	objectId := helper.ObjectIDFromHex(customerId)
-	_, err := repository.Collection.UpdateOne(ctx, bson.D{
-		{"$and", bson.A{
-			bson.D{{"_id", objectId}},
-		}},
-	}, bson.D{
-		{"$push", bson.D{
-			{"orders", order},
-		}},
+func (repository *CustomerRepositoryImpl) PullProductFromAllWishlist(ctx context.Context, productId string) error {
+	ctx = withOperation(ctx, "CustomerRepository", "PullProductFromAllWishlist")
+	_, err := repository.Collection.UpdateMany(ctx, bson.D{}, bson.D{
+		{
+			"$pull", bson.D{{
+				"wishlist", bson.D{
+					{"product_id", productId},
+				},
+			}},
+		},
 	})
 	if err != nil {
 		return err
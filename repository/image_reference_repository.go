@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"weplant-backend/model/schema"
+)
+
+// ImageReference is an image stored in a document
+type ImageReference struct {
+	Collection string
+	DocumentId string
+	Image      schema.Image
+}
+
+type ImageReferenceRepository interface {
+	FindAll(ctx context.Context) ([]ImageReference, error)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"weplant-backend/model/schema"
+)
+
+type ImageReferenceRepositoryImpl struct {
+	Collections []*mongo.Collection
+}
+
+// NewImageReferenceRepository reads the main_image and images fields of the documents in collections
+func NewImageReferenceRepository(collections ...*mongo.Collection) ImageReferenceRepository {
+	return &ImageReferenceRepositoryImpl{
+		Collections: collections,
+	}
+}
+
+type imageDocument struct {
+	Id        primitive.ObjectID `bson:"_id"`
+	MainImage *schema.Image      `bson:"main_image"`
+	Images    []schema.Image     `bson:"images"`
+}
+
+func (repository *ImageReferenceRepositoryImpl) FindAll(ctx context.Context) ([]ImageReference, error) {
+	var references []ImageReference
+	for _, collection := range repository.Collections {
+		cursor, err := collection.Find(ctx, bson.D{
+			{"$or", bson.A{
+				bson.D{{"main_image", bson.D{{"$exists", true}}}},
+				bson.D{{"images", bson.D{{"$exists", true}}}},
+			}},
+		}, options.Find().SetProjection(bson.D{{"main_image", 1}, {"images", 1}}))
+		if err != nil {
+			return nil, err
+		}
+		for cursor.Next(ctx) {
+			var document imageDocument
+			err = cursor.Decode(&document)
+			if err != nil {
+				cursor.Close(ctx)
+				return nil, err
+			}
+			if document.MainImage != nil {
+				references = append(references, ImageReference{
+					Collection: collection.Name(),
+					DocumentId: document.Id.Hex(),
+					Image:      *document.MainImage,
+				})
+			}
+			for _, image := range document.Images {
+				references = append(references, ImageReference{
+					Collection: collection.Name(),
+					DocumentId: document.Id.Hex(),
+					Image:      image,
+				})
+			}
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return references, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ImageRepository stores the uploaded images, it is backed by Cloudinary, the local disk or an S3 compatible bucket
+type ImageRepository interface {
+	UploadImage(ctx context.Context, filename string, image interface{}) (string, error)
+	DeleteImage(ctx context.Context, filename string) error
+	ListImages(ctx context.Context) ([]StoredImage, error)
+}
+
+// StoredImage is a file found in the storage, FileName is the one the image was uploaded with
+type StoredImage struct {
+	FileName  string
+	CreatedAt time.Time
+}
+
+var ErrImageSource = errors.New("image must be a readable file")
+
+// imageReader returns the content of an uploaded image, Cloudinary also accepts URLs but the other storages only files
+func imageReader(image interface{}) (io.Reader, error) {
+	reader, ok := image.(io.Reader)
+	if !ok {
+		return nil, ErrImageSource
+	}
+	return reader, nil
+}
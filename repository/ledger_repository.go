@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"weplant-backend/model/schema"
+)
+
+type LedgerRepository interface {
+	Create(ctx context.Context, entry schema.LedgerEntry) (schema.LedgerEntry, error)
+	FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.LedgerEntry, PageInfo, error)
+	CountByMerchantId(ctx context.Context, merchantId string) (int, error)
+	SumByMerchantId(ctx context.Context, merchantId string) (int64, error)
+}
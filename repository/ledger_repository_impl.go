@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/model/schema"
+)
+
+type LedgerRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewLedgerRepository(collection *mongo.Collection) LedgerRepository {
+	return &LedgerRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *LedgerRepositoryImpl) Create(ctx context.Context, entry schema.LedgerEntry) (schema.LedgerEntry, error) {
+	ctx = withOperation(ctx, "LedgerRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, entry)
+	if err != nil {
+		return entry, err
+	}
+	entry.Id = res.InsertedID.(primitive.ObjectID)
+	return entry, nil
+}
+
+func (repository *LedgerRepositoryImpl) FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.LedgerEntry, PageInfo, error) {
+	ctx = withOperation(ctx, "LedgerRepository", "FindByMerchantId")
+	var entries []schema.LedgerEntry
+	pageInfo, err := findPage(ctx, repository.Collection, bson.D{{"merchant_id", merchantId}}, page, "created_at", -1, &entries)
+	return entries, pageInfo, err
+}
+
+func (repository *LedgerRepositoryImpl) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	ctx = withOperation(ctx, "LedgerRepository", "CountByMerchantId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{{"merchant_id", merchantId}})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
+func (repository *LedgerRepositoryImpl) SumByMerchantId(ctx context.Context, merchantId string) (int64, error) {
+	ctx = withOperation(ctx, "LedgerRepository", "SumByMerchantId")
+	cursor, err := repository.Collection.Aggregate(ctx, mongo.Pipeline{
+		{{"$match", bson.D{{"merchant_id", merchantId}}}},
+		{{"$group", bson.D{
+			{"_id", "$merchant_id"},
+			{"total", bson.D{{"$sum", "$amount"}}},
+		}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var results []struct {
+		Total int64 `bson:"total"`
+	}
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Total, nil
+}
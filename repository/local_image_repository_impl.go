@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalImagePath is the route the images of the local storage are served under
+const LocalImagePath = "/images/"
+
+type LocalImageRepositoryImpl struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocalImageRepository keeps the images in dir, their URLs start with baseURL
+func NewLocalImageRepository(dir string, baseURL string) ImageRepository {
+	return &LocalImageRepositoryImpl{
+		Dir:     dir,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (repository *LocalImageRepositoryImpl) path(filename string) (string, error) {
+	if filename == "" || filename != filepath.Base(filename) || filename == ".." {
+		return "", errors.New("invalid image filename " + filename)
+	}
+	return filepath.Join(repository.Dir, filename), nil
+}
+
+func (repository *LocalImageRepositoryImpl) UploadImage(ctx context.Context, filename string, image interface{}) (string, error) {
+	reader, err := imageReader(image)
+	if err != nil {
+		return "", err
+	}
+	path, err := repository.path(filename)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(repository.Dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(file, reader)
+	errClose := file.Close()
+	if err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return repository.BaseURL + LocalImagePath + filename, nil
+}
+
+func (repository *LocalImageRepositoryImpl) DeleteImage(ctx context.Context, filename string) error {
+	path, err := repository.path(filename)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (repository *LocalImageRepositoryImpl) ListImages(ctx context.Context) ([]StoredImage, error) {
+	entries, err := os.ReadDir(repository.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var images []StoredImage
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, StoredImage{
+			FileName:  entry.Name(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+	return images, nil
+}
@@ -2,18 +2,23 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"weplant-backend/model/schema"
 )
 
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
 type MerchantRepository interface {
 	Create(ctx context.Context, merchant schema.Merchant) (schema.Merchant, error)
 	FindById(ctx context.Context, merchantId string) (schema.Merchant, error)
 	FindByEmail(ctx context.Context, email string) (schema.Merchant, error)
 	FindBySlug(ctx context.Context, slug string) (schema.Merchant, error)
+	FindAll(ctx context.Context) ([]schema.Merchant, error)
+	FindByLocation(ctx context.Context, city string, province string) ([]schema.Merchant, error)
 	Update(ctx context.Context, merchant schema.Merchant) (schema.Merchant, error)
-	//UpdateBalance(ctx context.Context, merchant schema.Merchant) error
+	UpdateBalance(ctx context.Context, merchant schema.Merchant) error
+	WithdrawBalance(ctx context.Context, merchantId string, amount int64) error
+	SetBalance(ctx context.Context, merchantId string, balance int64) error
+	UpdateRating(ctx context.Context, merchantId string, count int, total int) error
 	Delete(ctx context.Context, merchantId string) error
-
-	// Manage Order
-	PushProductToManageOrders(ctx context.Context, merchantId string, product schema.ManageOrderProduct) error
 }
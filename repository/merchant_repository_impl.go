@@ -5,6 +5,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
 	"weplant-backend/helper"
 	"weplant-backend/model/schema"
 )
@@ -20,6 +22,7 @@ func NewMerchantRepository(collection *mongo.Collection) MerchantRepository {
 }
 
 func (repository *MerchantRepositoryImpl) Create(ctx context.Context, merchant schema.Merchant) (schema.Merchant, error) {
+	ctx = withOperation(ctx, "MerchantRepository", "Create")
 	res, err := repository.Collection.InsertOne(ctx, merchant)
 	if err != nil {
 		return merchant, err
@@ -29,6 +32,7 @@ func (repository *MerchantRepositoryImpl) Create(ctx context.Context, merchant s
 }
 
 func (repository *MerchantRepositoryImpl) FindById(ctx context.Context, merchantId string) (schema.Merchant, error) {
+	ctx = withOperation(ctx, "MerchantRepository", "FindById")
 	var merchant schema.Merchant
 	objectId := helper.ObjectIDFromHex(merchantId)
 	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&merchant)
@@ -39,6 +43,7 @@ func (repository *MerchantRepositoryImpl) FindById(ctx context.Context, merchant
 }
 
 func (repository *MerchantRepositoryImpl) FindByEmail(ctx context.Context, email string) (schema.Merchant, error) {
+	ctx = withOperation(ctx, "MerchantRepository", "FindByEmail")
 	var merchant schema.Merchant
 	err := repository.Collection.FindOne(ctx, bson.D{{"email", email}}).Decode(&merchant)
 	if err != nil {
@@ -48,6 +53,7 @@ func (repository *MerchantRepositoryImpl) FindByEmail(ctx context.Context, email
 }
 
 func (repository *MerchantRepositoryImpl) FindBySlug(ctx context.Context, slug string) (schema.Merchant, error) {
+	ctx = withOperation(ctx, "MerchantRepository", "FindBySlug")
 	var merchant schema.Merchant
 	err := repository.Collection.FindOne(ctx, bson.D{{"slug", slug}}).Decode(&merchant)
 	if err != nil {
@@ -56,7 +62,48 @@ func (repository *MerchantRepositoryImpl) FindBySlug(ctx context.Context, slug s
 	return merchant, nil
 }
 
+func (repository *MerchantRepositoryImpl) FindAll(ctx context.Context) ([]schema.Merchant, error) {
+	ctx = withOperation(ctx, "MerchantRepository", "FindAll")
+	var merchants []schema.Merchant
+	cursor, err := repository.Collection.Find(ctx, bson.D{}, options.Find().SetProjection(bson.D{
+		{"_id", 1},
+	}))
+	if err != nil {
+		return merchants, err
+	}
+	err = cursor.All(ctx, &merchants)
+	if err != nil {
+		return merchants, err
+	}
+	return merchants, nil
+}
+
+// FindByLocation matches the city and the province of the address ignoring case, an empty one is not filtered on
+func (repository *MerchantRepositoryImpl) FindByLocation(ctx context.Context, city string, province string) ([]schema.Merchant, error) {
+	ctx = withOperation(ctx, "MerchantRepository", "FindByLocation")
+	var merchants []schema.Merchant
+	filter := bson.D{}
+	if city != "" {
+		filter = append(filter, bson.E{"address.city", bson.D{{"$regex", "^" + regexp.QuoteMeta(city) + "$"}, {"$options", "i"}}})
+	}
+	if province != "" {
+		filter = append(filter, bson.E{"address.province", bson.D{{"$regex", "^" + regexp.QuoteMeta(province) + "$"}, {"$options", "i"}}})
+	}
+	cursor, err := repository.Collection.Find(ctx, filter, options.Find().SetProjection(bson.D{
+		{"_id", 1},
+	}))
+	if err != nil {
+		return merchants, err
+	}
+	err = cursor.All(ctx, &merchants)
+	if err != nil {
+		return merchants, err
+	}
+	return merchants, nil
+}
+
 func (repository *MerchantRepositoryImpl) Update(ctx context.Context, merchant schema.Merchant) (schema.Merchant, error) {
+	ctx = withOperation(ctx, "MerchantRepository", "Update")
 	_, err := repository.Collection.UpdateByID(ctx, merchant.Id, bson.D{{"$set", merchant}})
 	if err != nil {
 		return merchant, err
@@ -64,23 +111,60 @@ func (repository *MerchantRepositoryImpl) Update(ctx context.Context, merchant s
 	return merchant, nil
 }
 
-//func (repository *MerchantRepositoryImpl) UpdateBalance(ctx context.Context, merchant schema.Merchant) error {
-//	_, err := repository.Collection.UpdateByID(ctx, merchant.Id, bson.D{
-//		{
-//			"$inc", bson.D{
-//				{
-//					"balance", merchant.Balance,
-//				},
-//			},
-//		},
-//	})
-//	if err != nil {
-//		return err
-//	}
-//	return nil
-//}
+func (repository *MerchantRepositoryImpl) UpdateBalance(ctx context.Context, merchant schema.Merchant) error {
+	ctx = withOperation(ctx, "MerchantRepository", "UpdateBalance")
+	_, err := repository.Collection.UpdateByID(ctx, merchant.Id, bson.D{
+		{
+			"$inc", bson.D{
+				{
+					"balance", merchant.Balance,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// WithdrawBalance only takes the amount when the balance covers it
+func (repository *MerchantRepositoryImpl) WithdrawBalance(ctx context.Context, merchantId string, amount int64) error {
+	ctx = withOperation(ctx, "MerchantRepository", "WithdrawBalance")
+	objectId := helper.ObjectIDFromHex(merchantId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"balance", bson.D{{"$gte", amount}}},
+	}, bson.D{
+		{"$inc", bson.D{
+			{"balance", -amount},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+func (repository *MerchantRepositoryImpl) SetBalance(ctx context.Context, merchantId string, balance int64) error {
+	ctx = withOperation(ctx, "MerchantRepository", "SetBalance")
+	objectId := helper.ObjectIDFromHex(merchantId)
+	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
+		{"$set", bson.D{
+			{"balance", balance},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
 
 func (repository *MerchantRepositoryImpl) Delete(ctx context.Context, merchantId string) error {
+	ctx = withOperation(ctx, "MerchantRepository", "Delete")
 	objectId := helper.ObjectIDFromHex(merchantId)
 	_, err := repository.Collection.DeleteOne(ctx, bson.D{{"_id", objectId}})
 	if err != nil {
@@ -89,22 +173,15 @@ func (repository *MerchantRepositoryImpl) Delete(ctx context.Context, merchantId
 	return nil
 }
 
-func (repository *MerchantRepositoryImpl) PushProductToManageOrders(ctx context.Context, merchantId string, product schema.ManageOrderProduct) error {
+// UpdateRating adds to the number of reviews and to the sum of their ratings
+func (repository *MerchantRepositoryImpl) UpdateRating(ctx context.Context, merchantId string, count int, total int) error {
+	ctx = withOperation(ctx, "MerchantRepository", "UpdateRating")
 	objectId := helper.ObjectIDFromHex(merchantId)
-	_, err := repository.Collection.UpdateOne(ctx, bson.D{
-		{"_id", objectId},
-	}, bson.D{
-		{"$push", bson.D{
-			{"orders", product},
-		},
-		},
-		{
-			"$inc", bson.D{
-				{
-					"balance", product.Price * product.Quantity,
-				},
-			},
-		},
+	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
+		{"$inc", bson.D{
+			{"rating_count", count},
+			{"rating_total", total},
+		}},
 	})
 	if err != nil {
 		return err
@@ -5,6 +5,7 @@ import (
 	"github.com/midtrans/midtrans-go/coreapi"
 	"os"
 	"weplant-backend/helper"
+	"weplant-backend/pkg"
 )
 
 type MidtransRepositoryImpl struct {
@@ -21,14 +22,18 @@ func (repository *MidtransRepositoryImpl) CreateTransaction(req coreapi.ChargeRe
 	var c coreapi.Client
 	c.New(repository.ServerKey, helper.MidtransEnvType(os.Getenv("GO_ENV")))
 
-	return c.ChargeTransaction(&req)
+	res, err := c.ChargeTransaction(&req)
+	pkg.ObserveExternalCall("midtrans", "charge", err != nil)
+	return res, err
 }
 
 func (repository *MidtransRepositoryImpl) CancelTransaction(orderId string) (*coreapi.CancelResponse, *midtrans.Error) {
 	var c coreapi.Client
 	c.New(repository.ServerKey, helper.MidtransEnvType(os.Getenv("GO_ENV")))
 
-	return c.CancelTransaction(orderId)
+	res, err := c.CancelTransaction(orderId)
+	pkg.ObserveExternalCall("midtrans", "cancel", err != nil)
+	return res, err
 }
 
 func (repository *MidtransRepositoryImpl) CheckTransaction(orderId string) (*coreapi.TransactionStatusResponse, *midtrans.Error) {
@@ -36,5 +41,7 @@ func (repository *MidtransRepositoryImpl) CheckTransaction(orderId string) (*cor
 	var c coreapi.Client
 	c.New(repository.ServerKey, helper.MidtransEnvType(os.Getenv("GO_ENV")))
 
-	return c.CheckTransaction(orderId)
+	res, err := c.CheckTransaction(orderId)
+	pkg.ObserveExternalCall("midtrans", "check", err != nil)
+	return res, err
 }
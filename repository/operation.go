@@ -0,0 +1,22 @@
+package repository
+
+import "context"
+
+type operationKey struct{}
+
+// Operation is the repository method a mongo command is sent from
+type Operation struct {
+	Repository string
+	Method     string
+}
+
+// withOperation names the repository method on ctx, every command sent with the returned ctx is timed under it
+func withOperation(ctx context.Context, repository string, method string) context.Context {
+	return context.WithValue(ctx, operationKey{}, Operation{Repository: repository, Method: method})
+}
+
+// OperationFromContext is the repository method ctx was named after, commands sent from outside the repositories have none
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	operation, ok := ctx.Value(operationKey{}).(Operation)
+	return operation, ok
+}
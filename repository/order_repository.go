@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"weplant-backend/model/schema"
+)
+
+var ErrOrderStatusChanged = errors.New("order status already changed")
+
+type OrderRepository interface {
+	Create(ctx context.Context, order schema.Order) (schema.Order, error)
+	FindById(ctx context.Context, orderId string) (schema.Order, error)
+	FindByCustomerId(ctx context.Context, customerId string, page Page) ([]schema.Order, PageInfo, error)
+	CountByCustomerId(ctx context.Context, customerId string) (int, error)
+	FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Order, PageInfo, error)
+	CountByMerchantId(ctx context.Context, merchantId string) (int, error)
+	UpdateStatus(ctx context.Context, orderId string, from string, history schema.OrderHistory, shipping *schema.OrderShipping) error
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+type OrderRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewOrderRepository(collection *mongo.Collection) OrderRepository {
+	return &OrderRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *OrderRepositoryImpl) Create(ctx context.Context, order schema.Order) (schema.Order, error) {
+	ctx = withOperation(ctx, "OrderRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, order)
+	if err != nil {
+		return order, err
+	}
+	order.Id = res.InsertedID.(primitive.ObjectID)
+	return order, nil
+}
+
+func (repository *OrderRepositoryImpl) FindById(ctx context.Context, orderId string) (schema.Order, error) {
+	ctx = withOperation(ctx, "OrderRepository", "FindById")
+	var order schema.Order
+	objectId := helper.ObjectIDFromHex(orderId)
+	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&order)
+	if err != nil {
+		return order, err
+	}
+	return order, nil
+}
+
+func (repository *OrderRepositoryImpl) FindByCustomerId(ctx context.Context, customerId string, page Page) ([]schema.Order, PageInfo, error) {
+	ctx = withOperation(ctx, "OrderRepository", "FindByCustomerId")
+	return repository.find(ctx, bson.D{{"customer_id", customerId}}, page)
+}
+
+func (repository *OrderRepositoryImpl) CountByCustomerId(ctx context.Context, customerId string) (int, error) {
+	ctx = withOperation(ctx, "OrderRepository", "CountByCustomerId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{{"customer_id", customerId}})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
+func (repository *OrderRepositoryImpl) FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Order, PageInfo, error) {
+	ctx = withOperation(ctx, "OrderRepository", "FindByMerchantId")
+	return repository.find(ctx, bson.D{{"merchant_id", merchantId}}, page)
+}
+
+func (repository *OrderRepositoryImpl) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	ctx = withOperation(ctx, "OrderRepository", "CountByMerchantId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{{"merchant_id", merchantId}})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
+// UpdateStatus only moves the order when it is still in the from status and records the move in the history
+func (repository *OrderRepositoryImpl) UpdateStatus(ctx context.Context, orderId string, from string, history schema.OrderHistory, shipping *schema.OrderShipping) error {
+	ctx = withOperation(ctx, "OrderRepository", "UpdateStatus")
+	objectId := helper.ObjectIDFromHex(orderId)
+	set := bson.D{
+		{"status", history.Status},
+		{"updated_at", history.CreatedAt},
+	}
+	if shipping != nil {
+		set = append(set, bson.E{Key: "shipping", Value: shipping})
+	}
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"status", from},
+	}, bson.D{
+		{"$set", set},
+		{"$push", bson.D{
+			{"history", history},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrOrderStatusChanged
+	}
+	return nil
+}
+
+// find returns the newest orders first
+func (repository *OrderRepositoryImpl) find(ctx context.Context, filter bson.D, page Page) ([]schema.Order, PageInfo, error) {
+	var orders []schema.Order
+	pageInfo, err := findPage(ctx, repository.Collection, filter, page, "created_at", -1, &orders)
+	return orders, pageInfo, err
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"reflect"
+	"weplant-backend/helper"
+)
+
+var ErrInvalidCursor = errors.New("cursor is invalid")
+
+// Page asks for Limit items following Cursor, an empty Cursor is the first page
+type Page struct {
+	Cursor string
+	Limit  int
+}
+
+// size is at least one item
+func (page Page) size() int {
+	if page.Limit < 1 {
+		return 1
+	}
+	return page.Limit
+}
+
+// PageInfo holds the cursors of the pages around the one that was read, they are empty when there is no such page
+type PageInfo struct {
+	NextCursor string
+	PrevCursor string
+}
+
+// findPage reads the page of the documents matching filter sorted on field then _id in direction into items, a pointer to a slice
+func findPage(ctx context.Context, collection *mongo.Collection, filter bson.D, page Page, field string, direction int, items interface{}) (PageInfo, error) {
+	keyset, sort, limit, err := page.keyset(field, direction)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	cursor, err := collection.Find(ctx, append(filter, keyset...), options.Find().SetSort(sort).SetLimit(limit))
+	if err != nil {
+		return PageInfo{}, err
+	}
+	var documents []bson.Raw
+	err = cursor.All(ctx, &documents)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	return page.decodePage(documents, field, items)
+}
+
+// keyset returns the filter and the sort reading the page when the items are sorted on field then _id in direction,
+// one more item than asked is read to tell whether another page follows
+func (page Page) keyset(field string, direction int) (bson.D, bson.D, int64, error) {
+	filter := bson.D{}
+	if page.Cursor == "" {
+		return filter, keysetSort(field, direction), int64(page.size() + 1), nil
+	}
+
+	cursor, err := decodeCursor(page.Cursor)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if cursor.Backward {
+		direction = -direction
+	}
+	operator := "$gt"
+	if direction < 0 {
+		operator = "$lt"
+	}
+	id := helper.ObjectIDFromHex(cursor.Id)
+	if field == "_id" {
+		filter = append(filter, bson.E{"_id", bson.D{{operator, id}}})
+		return filter, keysetSort(field, direction), int64(page.size() + 1), nil
+	}
+
+	// a missing field sorts before every value, omitempty leaves out the zero ones
+	var or bson.A
+	switch {
+	case cursor.Value == nil && direction > 0:
+		or = bson.A{
+			bson.D{{field, bson.D{{"$ne", nil}}}},
+			bson.D{{field, nil}, {"_id", bson.D{{operator, id}}}},
+		}
+	case cursor.Value == nil:
+		or = bson.A{
+			bson.D{{field, nil}, {"_id", bson.D{{operator, id}}}},
+		}
+	default:
+		or = bson.A{
+			bson.D{{field, bson.D{{operator, cursor.Value}}}},
+			bson.D{{field, cursor.Value}, {"_id", bson.D{{operator, id}}}},
+		}
+		if direction < 0 {
+			or = append(or, bson.D{{field, nil}})
+		}
+	}
+	filter = append(filter, bson.E{"$or", or})
+	return filter, keysetSort(field, direction), int64(page.size() + 1), nil
+}
+
+// decodeCursor reads a cursor handed out with a page, any other value is an ErrInvalidCursor
+func decodeCursor(value string) (helper.Cursor, error) {
+	cursor, err := helper.DecodeCursor(value)
+	if err != nil {
+		return cursor, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return cursor, nil
+}
+
+func keysetSort(field string, direction int) bson.D {
+	if field == "_id" {
+		return bson.D{{"_id", direction}}
+	}
+	return bson.D{{field, direction}, {"_id", direction}}
+}
+
+// decodePage decodes the documents read with keyset into items, a pointer to a slice, and hands out the cursors around them
+func (page Page) decodePage(documents []bson.Raw, field string, items interface{}) (PageInfo, error) {
+	var pageInfo PageInfo
+	backward := false
+	if page.Cursor != "" {
+		cursor, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return pageInfo, err
+		}
+		backward = cursor.Backward
+	}
+
+	more := len(documents) > page.size()
+	if more {
+		documents = documents[:page.size()]
+	}
+	// going backward the page was read in reverse
+	if backward {
+		for i, j := 0, len(documents)-1; i < j; i, j = i+1, j-1 {
+			documents[i], documents[j] = documents[j], documents[i]
+		}
+	}
+
+	slice := reflect.ValueOf(items).Elem()
+	for _, document := range documents {
+		item := reflect.New(slice.Type().Elem())
+		err := bson.Unmarshal(document, item.Interface())
+		if err != nil {
+			return pageInfo, err
+		}
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+	if len(documents) == 0 {
+		return pageInfo, nil
+	}
+
+	if more || backward {
+		pageInfo.NextCursor = documentCursor(documents[len(documents)-1], field, false)
+	}
+	if (backward && more) || (!backward && page.Cursor != "") {
+		pageInfo.PrevCursor = documentCursor(documents[0], field, true)
+	}
+	return pageInfo, nil
+}
+
+func documentCursor(document bson.Raw, field string, backward bool) string {
+	cursor := helper.Cursor{
+		Id:       document.Lookup("_id").ObjectID().Hex(),
+		Backward: backward,
+	}
+	if field != "_id" {
+		var value interface{}
+		document.Lookup(field).Unmarshal(&value)
+		cursor.Value = value
+	}
+	return helper.EncodeCursor(cursor)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"context"
+	"weplant-backend/model/schema"
+)
+
+type PaymentNotificationRepository interface {
+	Create(ctx context.Context, notification schema.PaymentNotification) (schema.PaymentNotification, error)
+}
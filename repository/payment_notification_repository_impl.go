@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/model/schema"
+)
+
+type PaymentNotificationRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewPaymentNotificationRepository(collection *mongo.Collection) PaymentNotificationRepository {
+	return &PaymentNotificationRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+// Create fails with a duplicate key error when the same order id and status was already recorded
+func (repository *PaymentNotificationRepositoryImpl) Create(ctx context.Context, notification schema.PaymentNotification) (schema.PaymentNotification, error) {
+	ctx = withOperation(ctx, "PaymentNotificationRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, notification)
+	if err != nil {
+		return notification, err
+	}
+	notification.Id = res.InsertedID.(primitive.ObjectID)
+	return notification, nil
+}
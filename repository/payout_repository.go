@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"weplant-backend/model/schema"
+)
+
+var ErrPayoutStatusChanged = errors.New("payout status already changed")
+
+type PayoutRepository interface {
+	Create(ctx context.Context, payout schema.Payout) (schema.Payout, error)
+	FindById(ctx context.Context, payoutId string) (schema.Payout, error)
+	FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Payout, PageInfo, error)
+	CountByMerchantId(ctx context.Context, merchantId string) (int, error)
+	UpdateStatus(ctx context.Context, payoutId string, from string, history schema.PayoutHistory) error
+}
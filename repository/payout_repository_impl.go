@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+type PayoutRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewPayoutRepository(collection *mongo.Collection) PayoutRepository {
+	return &PayoutRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *PayoutRepositoryImpl) Create(ctx context.Context, payout schema.Payout) (schema.Payout, error) {
+	ctx = withOperation(ctx, "PayoutRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, payout)
+	if err != nil {
+		return payout, err
+	}
+	payout.Id = res.InsertedID.(primitive.ObjectID)
+	return payout, nil
+}
+
+func (repository *PayoutRepositoryImpl) FindById(ctx context.Context, payoutId string) (schema.Payout, error) {
+	ctx = withOperation(ctx, "PayoutRepository", "FindById")
+	var payout schema.Payout
+	objectId := helper.ObjectIDFromHex(payoutId)
+	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&payout)
+	if err != nil {
+		return payout, err
+	}
+	return payout, nil
+}
+
+func (repository *PayoutRepositoryImpl) FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Payout, PageInfo, error) {
+	ctx = withOperation(ctx, "PayoutRepository", "FindByMerchantId")
+	var payouts []schema.Payout
+	pageInfo, err := findPage(ctx, repository.Collection, bson.D{{"merchant_id", merchantId}}, page, "created_at", -1, &payouts)
+	return payouts, pageInfo, err
+}
+
+func (repository *PayoutRepositoryImpl) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	ctx = withOperation(ctx, "PayoutRepository", "CountByMerchantId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{{"merchant_id", merchantId}})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
+// UpdateStatus only moves the payout when it is still in the from status and records the move in the history
+func (repository *PayoutRepositoryImpl) UpdateStatus(ctx context.Context, payoutId string, from string, history schema.PayoutHistory) error {
+	ctx = withOperation(ctx, "PayoutRepository", "UpdateStatus")
+	objectId := helper.ObjectIDFromHex(payoutId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"status", from},
+	}, bson.D{
+		{"$set", bson.D{
+			{"status", history.Status},
+			{"updated_at", history.CreatedAt},
+		}},
+		{"$push", bson.D{
+			{"history", history},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrPayoutStatusChanged
+	}
+	return nil
+}
@@ -2,27 +2,86 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"weplant-backend/model/schema"
 )
 
+var ErrInsufficientStock = errors.New("insufficient stock")
+var ErrVariantChanged = errors.New("variant was changed by another request")
+
+const (
+	ProductSortRelevance   = "relevance"
+	ProductSortNewest      = "newest"
+	ProductSortPriceAsc    = "price_asc"
+	ProductSortPriceDesc   = "price_desc"
+	ProductSortBestSelling = "best_selling"
+)
+
+// ProductPriceBoundaries are the lower bounds of the price buckets counted by Search, the last bucket has no upper bound
+var ProductPriceBoundaries = []int{0, 25000, 50000, 100000, 250000, 500000, 1000000}
+
+// ProductSearch filters the products, an empty field is not filtered on
+type ProductSearch struct {
+	Search      string
+	CategoryIds []string
+	MerchantIds []string
+	MinPrice    int
+	MaxPrice    int
+	InStock     bool
+	Sort        string
+}
+
+type ProductCategoryCount struct {
+	CategoryId string `bson:"_id"`
+	Count      int    `bson:"count"`
+}
+
+// ProductPriceCount counts the products priced from Min up to the next boundary
+type ProductPriceCount struct {
+	Min   int `bson:"_id"`
+	Count int `bson:"count"`
+}
+
+type ProductSearchResult struct {
+	Products   []schema.Product
+	PageInfo   PageInfo
+	TotalData  int
+	Categories []ProductCategoryCount
+	Prices     []ProductPriceCount
+}
+
 type ProductRepository interface {
 	Create(ctx context.Context, product schema.Product) (schema.Product, error)
 	FindById(ctx context.Context, productId string) (schema.Product, error)
-	FindAll(ctx context.Context, skip int, limit int) ([]schema.Product, error)
-	FindAllWithSearch(ctx context.Context, search string, skip int, limit int) ([]schema.Product, error)
+	Search(ctx context.Context, search ProductSearch, page Page) (ProductSearchResult, error)
 	Update(ctx context.Context, product schema.Product) (schema.Product, error)
 	PushImageIntoImages(ctx context.Context, productId string, images []schema.Image) ([]schema.Image, error)
 	PullImageFromImages(ctx context.Context, productId string, imageId string) (schema.Image, error)
 	Delete(ctx context.Context, productId string) error
-	CountDocuments(ctx context.Context) (int, error)
 
 	// merchant
 	FindByMerchantId(ctx context.Context, merchantId string) ([]schema.Product, error)
+	FindPageByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Product, PageInfo, error)
+	CountByMerchantId(ctx context.Context, merchantId string) (int, error)
 
 	// category
-	FindByCategoryId(ctx context.Context, categoryId string) ([]schema.Product, error)
+	FindByCategoryIds(ctx context.Context, categoryIds []string, page Page) ([]schema.Product, PageInfo, error)
+	CountByCategoryIds(ctx context.Context, categoryIds []string) (int, error)
 	PullCategoryIdFromProduct(ctx context.Context, categoryId string) error
+	UpdateCategoryName(ctx context.Context, categoryId string, name string) error
+
+	// review
+	UpdateRating(ctx context.Context, productId string, count int, total int) error
+
+	// variant
+	PushVariant(ctx context.Context, productId string, variant schema.ProductVariant) error
+	UpdateVariant(ctx context.Context, productId string, from schema.ProductVariant, variant schema.ProductVariant) error
+	PullVariant(ctx context.Context, productId string, variant schema.ProductVariant) error
 
 	// transaction
 	UpdateQuantity(ctx context.Context, product schema.Product) error
+	IncrementSold(ctx context.Context, productId string, quantity int) error
+	ReserveStock(ctx context.Context, productId string, quantity int) error
+	UpdateVariantQuantity(ctx context.Context, productId string, variantId string, quantity int) error
+	ReserveVariantStock(ctx context.Context, productId string, variantId string, quantity int) error
 }
@@ -22,6 +22,7 @@ func NewProductRepository(collection *mongo.Collection) ProductRepository {
 }
 
 func (repository *ProductRepositoryImpl) Create(ctx context.Context, product schema.Product) (schema.Product, error) {
+	ctx = withOperation(ctx, "ProductRepository", "Create")
 	res, err := repository.Collection.InsertOne(ctx, product)
 	if err != nil {
 		return product, err
@@ -31,6 +32,7 @@ func (repository *ProductRepositoryImpl) Create(ctx context.Context, product sch
 }
 
 func (repository *ProductRepositoryImpl) FindById(ctx context.Context, productId string) (schema.Product, error) {
+	ctx = withOperation(ctx, "ProductRepository", "FindById")
 	var product schema.Product
 	objectId := helper.ObjectIDFromHex(productId)
 	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&product)
@@ -40,40 +42,134 @@ func (repository *ProductRepositoryImpl) FindById(ctx context.Context, productId
 	return product, nil
 }
 
-func (repository *ProductRepositoryImpl) FindAll(ctx context.Context, skip int, limit int) ([]schema.Product, error) {
-	var products []schema.Product
-	cursor, err := repository.Collection.Find(ctx, bson.D{}, options.Find().SetSkip(int64(skip)).SetLimit(int64(limit)))
+func (repository *ProductRepositoryImpl) Search(ctx context.Context, search ProductSearch, page Page) (ProductSearchResult, error) {
+	ctx = withOperation(ctx, "ProductRepository", "Search")
+	var result ProductSearchResult
+
+	filter := bson.D{}
+	if search.Search != "" {
+		filter = append(filter, bson.E{"$text", bson.D{{"$search", search.Search}}})
+	}
+	if len(search.CategoryIds) > 0 {
+		filter = append(filter, bson.E{"categories.category_id", bson.D{{"$in", search.CategoryIds}}})
+	}
+	if len(search.MerchantIds) > 0 {
+		filter = append(filter, bson.E{"merchant_id", bson.D{{"$in", search.MerchantIds}}})
+	}
+	price := bson.D{}
+	if search.MinPrice > 0 {
+		price = append(price, bson.E{"$gte", search.MinPrice})
+	}
+	if search.MaxPrice > 0 {
+		price = append(price, bson.E{"$lte", search.MaxPrice})
+	}
+	if len(price) > 0 {
+		filter = append(filter, bson.E{"price", price})
+	}
+	if search.InStock {
+		filter = append(filter, bson.E{"stock", bson.D{{"$gt", 0}}})
+	}
+
+	// the products are paged on an indexed field, only relevance is computed and pages on sort_value
+	field, direction := "created_at", -1
+	switch search.Sort {
+	case ProductSortRelevance:
+		field = "sort_value"
+	case ProductSortPriceAsc:
+		field, direction = "price", 1
+	case ProductSortPriceDesc:
+		field = "price"
+	case ProductSortBestSelling:
+		field = "sold"
+	}
+	keyset, sort, limit, err := page.keyset(field, direction)
 	if err != nil {
-		return products, err
+		return result, err
 	}
-	errorBind := cursor.All(ctx, &products)
-	if errorBind != nil {
-		return products, errorBind
+
+	pipeline := mongo.Pipeline{}
+	if field == "sort_value" {
+		pipeline = append(pipeline,
+			bson.D{{"$match", filter}},
+			bson.D{{"$addFields", bson.D{{"sort_value", bson.D{{"$meta", "textScore"}}}}}},
+			bson.D{{"$match", keyset}},
+		)
+	} else {
+		pipeline = append(pipeline, bson.D{{"$match", append(append(bson.D{}, filter...), keyset...)}})
 	}
-	return products, nil
-}
+	pipeline = append(pipeline, bson.D{{"$sort", sort}}, bson.D{{"$limit", limit}})
 
-func (repository *ProductRepositoryImpl) FindAllWithSearch(ctx context.Context, search string, skip int, limit int) ([]schema.Product, error) {
-	var products []schema.Product
-	cursor, err := repository.Collection.Find(ctx, bson.D{
-		{"$text", bson.D{
-			{
-				"$search", search,
-			},
-		},
-		},
-	}, options.Find().SetSkip(int64(skip)).SetLimit(int64(limit)))
+	cursor, err := repository.Collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return products, err
+		return result, err
 	}
-	errorBind := cursor.All(ctx, &products)
-	if errorBind != nil {
-		return products, errorBind
+	var products []bson.Raw
+	err = cursor.All(ctx, &products)
+	if err != nil {
+		return result, err
 	}
-	return products, nil
+	result.PageInfo, err = page.decodePage(products, field, &result.Products)
+	if err != nil {
+		return result, err
+	}
+
+	// the total and the facets do not change from page to page, they come with the first one only
+	if page.Cursor != "" {
+		return result, nil
+	}
+	cursor, err = repository.Collection.Aggregate(ctx, mongo.Pipeline{
+		{{"$match", filter}},
+		{{"$facet", bson.D{
+			{"total", bson.A{
+				bson.D{{"$count", "count"}},
+			}},
+			{"categories", bson.A{
+				bson.D{{"$unwind", "$categories"}},
+				bson.D{{"$group", bson.D{
+					{"_id", "$categories.category_id"},
+					{"count", bson.D{{"$sum", 1}}},
+				}}},
+				bson.D{{"$sort", bson.D{{"count", -1}, {"_id", 1}}}},
+			}},
+			{"prices", bson.A{
+				bson.D{{"$bucket", bson.D{
+					{"groupBy", "$price"},
+					{"boundaries", ProductPriceBoundaries},
+					// prices above the last boundary fall in the default bucket, it is keyed by the last boundary
+					{"default", ProductPriceBoundaries[len(ProductPriceBoundaries)-1]},
+					{"output", bson.D{{"count", bson.D{{"$sum", 1}}}}},
+				}}},
+			}},
+		}}},
+	})
+	if err != nil {
+		return result, err
+	}
+	var facets []struct {
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+		Categories []ProductCategoryCount `bson:"categories"`
+		Prices     []ProductPriceCount    `bson:"prices"`
+	}
+	err = cursor.All(ctx, &facets)
+	if err != nil {
+		return result, err
+	}
+	if len(facets) == 0 {
+		return result, nil
+	}
+
+	result.Categories = facets[0].Categories
+	result.Prices = facets[0].Prices
+	if len(facets[0].Total) > 0 {
+		result.TotalData = facets[0].Total[0].Count
+	}
+	return result, nil
 }
 
 func (repository *ProductRepositoryImpl) Update(ctx context.Context, product schema.Product) (schema.Product, error) {
+	ctx = withOperation(ctx, "ProductRepository", "Update")
 	_, err := repository.Collection.UpdateByID(ctx, product.Id, bson.D{{"$set", product}})
 	if err != nil {
 		return product, err
@@ -82,6 +178,7 @@ func (repository *ProductRepositoryImpl) Update(ctx context.Context, product sch
 }
 
 func (repository *ProductRepositoryImpl) PushImageIntoImages(ctx context.Context, productId string, images []schema.Image) ([]schema.Image, error) {
+	ctx = withOperation(ctx, "ProductRepository", "PushImageIntoImages")
 	objectId := helper.ObjectIDFromHex(productId)
 	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
 		{
@@ -103,6 +200,7 @@ func (repository *ProductRepositoryImpl) PushImageIntoImages(ctx context.Context
 }
 
 func (repository *ProductRepositoryImpl) PullImageFromImages(ctx context.Context, productId string, imageId string) (schema.Image, error) {
+	ctx = withOperation(ctx, "ProductRepository", "PullImageFromImages")
 	objectProductId := helper.ObjectIDFromHex(productId)
 	objectImageId := helper.ObjectIDFromHex(imageId)
 
@@ -149,6 +247,7 @@ func (repository *ProductRepositoryImpl) PullImageFromImages(ctx context.Context
 }
 
 func (repository *ProductRepositoryImpl) Delete(ctx context.Context, productId string) error {
+	ctx = withOperation(ctx, "ProductRepository", "Delete")
 	objectId := helper.ObjectIDFromHex(productId)
 	_, err := repository.Collection.DeleteOne(ctx, bson.D{{"_id", objectId}})
 	if err != nil {
@@ -157,16 +256,9 @@ func (repository *ProductRepositoryImpl) Delete(ctx context.Context, productId s
 	return nil
 }
 
-func (repository *ProductRepositoryImpl) CountDocuments(ctx context.Context) (int, error) {
-	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{})
-	if err != nil {
-		return int(itemCount), err
-	}
-	return int(itemCount), nil
-}
-
 // merchant
 func (repository *ProductRepositoryImpl) FindByMerchantId(ctx context.Context, merchantId string) ([]schema.Product, error) {
+	ctx = withOperation(ctx, "ProductRepository", "FindByMerchantId")
 	var products []schema.Product
 	cursor, err := repository.Collection.Find(ctx, bson.D{
 		{"merchant_id", merchantId},
@@ -181,28 +273,48 @@ func (repository *ProductRepositoryImpl) FindByMerchantId(ctx context.Context, m
 	return products, nil
 }
 
+// FindPageByMerchantId puts the newest products first
+func (repository *ProductRepositoryImpl) FindPageByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Product, PageInfo, error) {
+	ctx = withOperation(ctx, "ProductRepository", "FindPageByMerchantId")
+	var products []schema.Product
+	pageInfo, err := findPage(ctx, repository.Collection, bson.D{{"merchant_id", merchantId}}, page, "created_at", -1, &products)
+	return products, pageInfo, err
+}
+
+func (repository *ProductRepositoryImpl) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	ctx = withOperation(ctx, "ProductRepository", "CountByMerchantId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{{"merchant_id", merchantId}})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
 // category
-func (repository *ProductRepositoryImpl) FindByCategoryId(ctx context.Context, categoryId string) ([]schema.Product, error) {
+// FindByCategoryIds puts the newest products first
+func (repository *ProductRepositoryImpl) FindByCategoryIds(ctx context.Context, categoryIds []string, page Page) ([]schema.Product, PageInfo, error) {
+	ctx = withOperation(ctx, "ProductRepository", "FindByCategoryIds")
 	var products []schema.Product
-	cursor, err := repository.Collection.Find(ctx, bson.D{
-		{"categories", bson.D{
-			{"$elemMatch", bson.D{
-				{"category_id", categoryId},
-			}},
-		}},
+	pageInfo, err := findPage(ctx, repository.Collection, bson.D{
+		{"categories.category_id", bson.D{{"$in", categoryIds}}},
+	}, page, "created_at", -1, &products)
+	return products, pageInfo, err
+}
+
+func (repository *ProductRepositoryImpl) CountByCategoryIds(ctx context.Context, categoryIds []string) (int, error) {
+	ctx = withOperation(ctx, "ProductRepository", "CountByCategoryIds")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{
+		{"categories.category_id", bson.D{{"$in", categoryIds}}},
 	})
 	if err != nil {
-		return products, err
-	}
-	errBind := cursor.All(ctx, &products)
-	if errBind != nil {
-		return products, errBind
+		return int(itemCount), err
 	}
-	return products, nil
+	return int(itemCount), nil
 }
 
 func (repository *ProductRepositoryImpl) PullCategoryIdFromProduct(ctx context.Context, categoryId string) error {
-	_, err := repository.Collection.UpdateMany(ctx, bson.D{}, bson.D{
+	ctx = withOperation(ctx, "ProductRepository", "PullCategoryIdFromProduct")
+	_, err := repository.Collection.UpdateMany(ctx, bson.D{{"categories.category_id", categoryId}}, bson.D{
 		{
 			"$pull", bson.D{
 				{
@@ -219,8 +331,20 @@ func (repository *ProductRepositoryImpl) PullCategoryIdFromProduct(ctx context.C
 	return nil
 }
 
+func (repository *ProductRepositoryImpl) UpdateCategoryName(ctx context.Context, categoryId string, name string) error {
+	ctx = withOperation(ctx, "ProductRepository", "UpdateCategoryName")
+	_, err := repository.Collection.UpdateMany(ctx, bson.D{{"categories.category_id", categoryId}}, bson.D{
+		{"$set", bson.D{{"categories.$.name", name}}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // transaction
 func (repository *ProductRepositoryImpl) UpdateQuantity(ctx context.Context, product schema.Product) error {
+	ctx = withOperation(ctx, "ProductRepository", "UpdateQuantity")
 	_, err := repository.Collection.UpdateByID(ctx, product.Id, bson.D{
 		{
 			"$inc", bson.D{
@@ -235,3 +359,167 @@ func (repository *ProductRepositoryImpl) UpdateQuantity(ctx context.Context, pro
 	}
 	return nil
 }
+
+func (repository *ProductRepositoryImpl) IncrementSold(ctx context.Context, productId string, quantity int) error {
+	ctx = withOperation(ctx, "ProductRepository", "IncrementSold")
+	objectId := helper.ObjectIDFromHex(productId)
+	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
+		{"$inc", bson.D{{"sold", quantity}}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReserveStock only decrements when enough stock is left, otherwise ErrInsufficientStock is returned
+func (repository *ProductRepositoryImpl) ReserveStock(ctx context.Context, productId string, quantity int) error {
+	ctx = withOperation(ctx, "ProductRepository", "ReserveStock")
+	objectId := helper.ObjectIDFromHex(productId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"stock", bson.D{{"$gte", quantity}}},
+	}, bson.D{
+		{"$inc", bson.D{{"stock", -quantity}}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// variant
+// PushVariant adds the stock of the variant to the product, the first variant replaces the stock the product had on its own
+func (repository *ProductRepositoryImpl) PushVariant(ctx context.Context, productId string, variant schema.ProductVariant) error {
+	ctx = withOperation(ctx, "ProductRepository", "PushVariant")
+	objectId := helper.ObjectIDFromHex(productId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"variants.0", bson.D{{"$exists", false}}},
+	}, bson.D{
+		{"$push", bson.D{{"variants", variant}}},
+		{"$set", bson.D{{"stock", variant.Stock}}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+
+	_, err = repository.Collection.UpdateByID(ctx, objectId, bson.D{
+		{"$push", bson.D{{"variants", variant}}},
+		{"$inc", bson.D{{"stock", variant.Stock}}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateVariant only replaces the variant while its stock is still the one of from, otherwise ErrVariantChanged is returned
+func (repository *ProductRepositoryImpl) UpdateVariant(ctx context.Context, productId string, from schema.ProductVariant, variant schema.ProductVariant) error {
+	ctx = withOperation(ctx, "ProductRepository", "UpdateVariant")
+	objectId := helper.ObjectIDFromHex(productId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"variants", bson.D{{"$elemMatch", bson.D{
+			{"_id", from.Id},
+			{"stock", from.Stock},
+		}}}},
+	}, bson.D{
+		{"$set", bson.D{{"variants.$", variant}}},
+		{"$inc", bson.D{{"stock", variant.Stock - from.Stock}}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrVariantChanged
+	}
+	return nil
+}
+
+// PullVariant only removes the variant while its stock is still the one of variant, otherwise ErrVariantChanged is returned
+func (repository *ProductRepositoryImpl) PullVariant(ctx context.Context, productId string, variant schema.ProductVariant) error {
+	ctx = withOperation(ctx, "ProductRepository", "PullVariant")
+	objectId := helper.ObjectIDFromHex(productId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"variants", bson.D{{"$elemMatch", bson.D{
+			{"_id", variant.Id},
+			{"stock", variant.Stock},
+		}}}},
+	}, bson.D{
+		{"$pull", bson.D{{"variants", bson.D{{"_id", variant.Id}}}}},
+		{"$inc", bson.D{{"stock", -variant.Stock}}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrVariantChanged
+	}
+	return nil
+}
+
+// UpdateVariantQuantity moves the stock of the variant and of its product together, deleted variants are skipped
+func (repository *ProductRepositoryImpl) UpdateVariantQuantity(ctx context.Context, productId string, variantId string, quantity int) error {
+	ctx = withOperation(ctx, "ProductRepository", "UpdateVariantQuantity")
+	_, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", helper.ObjectIDFromHex(productId)},
+		{"variants._id", helper.ObjectIDFromHex(variantId)},
+	}, bson.D{
+		{"$inc", bson.D{
+			{"variants.$.stock", quantity},
+			{"stock", quantity},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReserveVariantStock only decrements when enough stock of the variant is left, otherwise ErrInsufficientStock is returned
+func (repository *ProductRepositoryImpl) ReserveVariantStock(ctx context.Context, productId string, variantId string, quantity int) error {
+	ctx = withOperation(ctx, "ProductRepository", "ReserveVariantStock")
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", helper.ObjectIDFromHex(productId)},
+		{"variants", bson.D{{"$elemMatch", bson.D{
+			{"_id", helper.ObjectIDFromHex(variantId)},
+			{"stock", bson.D{{"$gte", quantity}}},
+		}}}},
+	}, bson.D{
+		{"$inc", bson.D{
+			{"variants.$.stock", -quantity},
+			{"stock", -quantity},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// UpdateRating adds to the number of reviews and to the sum of their ratings
+func (repository *ProductRepositoryImpl) UpdateRating(ctx context.Context, productId string, count int, total int) error {
+	ctx = withOperation(ctx, "ProductRepository", "UpdateRating")
+	objectId := helper.ObjectIDFromHex(productId)
+	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
+		{"$inc", bson.D{
+			{"rating_count", count},
+			{"rating_total", total},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
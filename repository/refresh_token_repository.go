@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"weplant-backend/model/schema"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, refreshToken schema.RefreshToken) (schema.RefreshToken, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (schema.RefreshToken, error)
+	Revoke(ctx context.Context, refreshTokenId string, replacedBy string) error
+	RevokeFamily(ctx context.Context, family string) error
+}
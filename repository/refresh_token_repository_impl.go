@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+type RefreshTokenRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository(collection *mongo.Collection) RefreshTokenRepository {
+	return &RefreshTokenRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *RefreshTokenRepositoryImpl) Create(ctx context.Context, refreshToken schema.RefreshToken) (schema.RefreshToken, error) {
+	ctx = withOperation(ctx, "RefreshTokenRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, refreshToken)
+	if err != nil {
+		return refreshToken, err
+	}
+	refreshToken.Id = res.InsertedID.(primitive.ObjectID)
+	return refreshToken, nil
+}
+
+func (repository *RefreshTokenRepositoryImpl) FindByTokenHash(ctx context.Context, tokenHash string) (schema.RefreshToken, error) {
+	ctx = withOperation(ctx, "RefreshTokenRepository", "FindByTokenHash")
+	var refreshToken schema.RefreshToken
+	err := repository.Collection.FindOne(ctx, bson.D{{"token_hash", tokenHash}}).Decode(&refreshToken)
+	if err != nil {
+		return refreshToken, err
+	}
+	return refreshToken, nil
+}
+
+// Revoke only succeeds once per token, a second call means the token was already used
+func (repository *RefreshTokenRepositoryImpl) Revoke(ctx context.Context, refreshTokenId string, replacedBy string) error {
+	ctx = withOperation(ctx, "RefreshTokenRepository", "Revoke")
+	objectId := helper.ObjectIDFromHex(refreshTokenId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"revoked", false},
+	}, bson.D{
+		{"$set", bson.D{
+			{"revoked", true},
+			{"replaced_by", replacedBy},
+			{"updated_at", helper.GetTimeNow()},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return errors.New("refresh token already revoked")
+	}
+	return nil
+}
+
+func (repository *RefreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, family string) error {
+	ctx = withOperation(ctx, "RefreshTokenRepository", "RevokeFamily")
+	_, err := repository.Collection.UpdateMany(ctx, bson.D{
+		{"family", family},
+		{"revoked", false},
+	}, bson.D{
+		{"$set", bson.D{
+			{"revoked", true},
+			{"updated_at", helper.GetTimeNow()},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
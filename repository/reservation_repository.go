@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"weplant-backend/model/schema"
+)
+
+var ErrReservationStatusChanged = errors.New("reservation status already changed")
+
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation schema.Reservation) (schema.Reservation, error)
+	FindByTransactionId(ctx context.Context, transactionId string) (schema.Reservation, error)
+	FindExpired(ctx context.Context, now int) ([]schema.Reservation, error)
+	UpdateStatus(ctx context.Context, reservationId string, from string, to string) error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+type ReservationRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewReservationRepository(collection *mongo.Collection) ReservationRepository {
+	return &ReservationRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *ReservationRepositoryImpl) Create(ctx context.Context, reservation schema.Reservation) (schema.Reservation, error) {
+	ctx = withOperation(ctx, "ReservationRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, reservation)
+	if err != nil {
+		return reservation, err
+	}
+	reservation.Id = res.InsertedID.(primitive.ObjectID)
+	return reservation, nil
+}
+
+func (repository *ReservationRepositoryImpl) FindByTransactionId(ctx context.Context, transactionId string) (schema.Reservation, error) {
+	ctx = withOperation(ctx, "ReservationRepository", "FindByTransactionId")
+	var reservation schema.Reservation
+	err := repository.Collection.FindOne(ctx, bson.D{{"transaction_id", transactionId}}).Decode(&reservation)
+	if err != nil {
+		return reservation, err
+	}
+	return reservation, nil
+}
+
+func (repository *ReservationRepositoryImpl) FindExpired(ctx context.Context, now int) ([]schema.Reservation, error) {
+	ctx = withOperation(ctx, "ReservationRepository", "FindExpired")
+	cursor, err := repository.Collection.Find(ctx, bson.D{
+		{"status", schema.ReservationStatusActive},
+		{"expired_at", bson.D{{"$lte", now}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var reservations []schema.Reservation
+	err = cursor.All(ctx, &reservations)
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// UpdateStatus only moves the reservation when it is still in the from status, so a reservation is released once
+func (repository *ReservationRepositoryImpl) UpdateStatus(ctx context.Context, reservationId string, from string, to string) error {
+	ctx = withOperation(ctx, "ReservationRepository", "UpdateStatus")
+	objectId := helper.ObjectIDFromHex(reservationId)
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", objectId},
+		{"status", from},
+	}, bson.D{
+		{"$set", bson.D{
+			{"status", to},
+			{"updated_at", helper.GetTimeNow()},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrReservationStatusChanged
+	}
+	return nil
+}
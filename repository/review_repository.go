@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"weplant-backend/model/schema"
+)
+
+var ErrReviewChanged = errors.New("review was changed by another request")
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review schema.Review) (schema.Review, error)
+	FindById(ctx context.Context, reviewId string) (schema.Review, error)
+	FindByProductId(ctx context.Context, productId string, page Page) ([]schema.Review, PageInfo, error)
+	CountByProductId(ctx context.Context, productId string) (int, error)
+	Update(ctx context.Context, review schema.Review, fromRating int) error
+	UpdateReply(ctx context.Context, reviewId string, reply schema.ReviewReply) error
+	Delete(ctx context.Context, review schema.Review) error
+}
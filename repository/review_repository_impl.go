@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+type ReviewRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewReviewRepository(collection *mongo.Collection) ReviewRepository {
+	return &ReviewRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *ReviewRepositoryImpl) Create(ctx context.Context, review schema.Review) (schema.Review, error) {
+	ctx = withOperation(ctx, "ReviewRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, review)
+	if err != nil {
+		return review, err
+	}
+	review.Id = res.InsertedID.(primitive.ObjectID)
+	return review, nil
+}
+
+func (repository *ReviewRepositoryImpl) FindById(ctx context.Context, reviewId string) (schema.Review, error) {
+	ctx = withOperation(ctx, "ReviewRepository", "FindById")
+	var review schema.Review
+	objectId := helper.ObjectIDFromHex(reviewId)
+	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&review)
+	if err != nil {
+		return review, err
+	}
+	return review, nil
+}
+
+// FindByProductId returns the newest reviews first
+func (repository *ReviewRepositoryImpl) FindByProductId(ctx context.Context, productId string, page Page) ([]schema.Review, PageInfo, error) {
+	ctx = withOperation(ctx, "ReviewRepository", "FindByProductId")
+	var reviews []schema.Review
+	pageInfo, err := findPage(ctx, repository.Collection, bson.D{{"product_id", productId}}, page, "created_at", -1, &reviews)
+	return reviews, pageInfo, err
+}
+
+func (repository *ReviewRepositoryImpl) CountByProductId(ctx context.Context, productId string) (int, error) {
+	ctx = withOperation(ctx, "ReviewRepository", "CountByProductId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{{"product_id", productId}})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
+// Update only changes the review while its rating is still fromRating, otherwise ErrReviewChanged is returned
+func (repository *ReviewRepositoryImpl) Update(ctx context.Context, review schema.Review, fromRating int) error {
+	ctx = withOperation(ctx, "ReviewRepository", "Update")
+	res, err := repository.Collection.UpdateOne(ctx, bson.D{
+		{"_id", review.Id},
+		{"rating", fromRating},
+	}, bson.D{
+		{"$set", bson.D{
+			{"updated_at", review.UpdatedAt},
+			{"rating", review.Rating},
+			{"text", review.Text},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrReviewChanged
+	}
+	return nil
+}
+
+func (repository *ReviewRepositoryImpl) UpdateReply(ctx context.Context, reviewId string, reply schema.ReviewReply) error {
+	ctx = withOperation(ctx, "ReviewRepository", "UpdateReply")
+	objectId := helper.ObjectIDFromHex(reviewId)
+	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
+		{"$set", bson.D{{"reply", reply}}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Delete only removes the review while its rating is still the one of review, otherwise ErrReviewChanged is returned
+func (repository *ReviewRepositoryImpl) Delete(ctx context.Context, review schema.Review) error {
+	ctx = withOperation(ctx, "ReviewRepository", "Delete")
+	res, err := repository.Collection.DeleteOne(ctx, bson.D{
+		{"_id", review.Id},
+		{"rating", review.Rating},
+	})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrReviewChanged
+	}
+	return nil
+}
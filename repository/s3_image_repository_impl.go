@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"weplant-backend/pkg"
+)
+
+// S3Config points at an S3 compatible bucket, objects are addressed path style so MinIO works as well as AWS
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL is where the bucket is read from, the endpoint when it is empty
+	PublicURL string
+}
+
+type S3ImageRepositoryImpl struct {
+	Config S3Config
+	Client *http.Client
+}
+
+func NewS3ImageRepository(config S3Config) ImageRepository {
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	return &S3ImageRepositoryImpl{
+		Config: config,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (repository *S3ImageRepositoryImpl) UploadImage(ctx context.Context, filename string, image interface{}) (string, error) {
+	reader, err := imageReader(image)
+	if err != nil {
+		return "", err
+	}
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	request, err := repository.request(ctx, http.MethodPut, repository.objectPath(filename), nil, body)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", http.DetectContentType(body))
+	_, err = repository.do(request)
+	if err != nil {
+		return "", err
+	}
+	return repository.Config.PublicURL + "/" + escapeS3(filename, false), nil
+}
+
+func (repository *S3ImageRepositoryImpl) DeleteImage(ctx context.Context, filename string) error {
+	request, err := repository.request(ctx, http.MethodDelete, repository.objectPath(filename), nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = repository.do(request)
+	return err
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (repository *S3ImageRepositoryImpl) ListImages(ctx context.Context) ([]StoredImage, error) {
+	var images []StoredImage
+	query := url.Values{"list-type": {"2"}}
+	for {
+		request, err := repository.request(ctx, http.MethodGet, "/"+repository.Config.Bucket, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := repository.do(request)
+		if err != nil {
+			return nil, err
+		}
+		var result s3ListBucketResult
+		err = xml.Unmarshal(body, &result)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range result.Contents {
+			images = append(images, StoredImage{
+				FileName:  object.Key,
+				CreatedAt: object.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return images, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+func (repository *S3ImageRepositoryImpl) objectPath(key string) string {
+	return "/" + repository.Config.Bucket + "/" + escapeS3(key, false)
+}
+
+// request builds a signed request on the escaped path
+func (repository *S3ImageRepositoryImpl) request(ctx context.Context, method string, path string, query url.Values, body []byte) (*http.Request, error) {
+	canonicalQuery := canonicalS3Query(query)
+	rawURL := repository.Config.Endpoint + path
+	if canonicalQuery != "" {
+		rawURL += "?" + canonicalQuery
+	}
+	endpoint, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	repository.sign(request, path, canonicalQuery, body, time.Now().UTC())
+	return request, nil
+}
+
+func (repository *S3ImageRepositoryImpl) do(request *http.Request) (body []byte, err error) {
+	defer func() {
+		pkg.ObserveExternalCall("s3", s3Operations[request.Method], err != nil)
+	}()
+	response, err := repository.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return nil, fmt.Errorf("s3 %s %s: %s %s", request.Method, request.URL.Path, response.Status, strings.TrimSpace(string(message)))
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+var s3Operations = map[string]string{
+	http.MethodPut:    "upload",
+	http.MethodDelete: "delete",
+	http.MethodGet:    "list",
+}
+
+// sign adds the AWS signature version 4 headers
+func (repository *S3ImageRepositoryImpl) sign(request *http.Request, path string, canonicalQuery string, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		path,
+		canonicalQuery,
+		"host:" + request.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + repository.Config.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+repository.Config.SecretKey), date)
+	key = hmacSHA256(key, repository.Config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+repository.Config.AccessKey+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// canonicalS3Query sorts and escapes the query the way it is signed
+func canonicalS3Query(query url.Values) string {
+	var keys []string
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parameters []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parameters = append(parameters, escapeS3(key, true)+"="+escapeS3(value, true))
+		}
+	}
+	return strings.Join(parameters, "&")
+}
+
+// escapeS3 percent encodes everything but the unreserved characters, slashes are kept unless escapeSlash
+func escapeS3(value string, escapeSlash bool) string {
+	unreserved := "-_.~/"
+	if escapeSlash {
+		unreserved = "-_.~"
+	}
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || strings.IndexByte(unreserved, b) >= 0 {
+			builder.WriteByte(b)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "context"
+
+type SessionRepository interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
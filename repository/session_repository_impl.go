@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SessionRepositoryImpl struct {
+	Client *mongo.Client
+}
+
+func NewSessionRepository(client *mongo.Client) SessionRepository {
+	return &SessionRepositoryImpl{
+		Client: client,
+	}
+}
+
+// WithTransaction runs fn inside a multi-document transaction, repositories called with the ctx passed to fn
+// take part in it. Transactions need mongo to run as a replica set.
+func (repository *SessionRepositoryImpl) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx = withOperation(ctx, "SessionRepository", "WithTransaction")
+	session, err := repository.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionContext)
+	})
+	return err
+}
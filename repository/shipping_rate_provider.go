@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrShippingRouteUnavailable = errors.New("no courier ships between the provinces")
+
+// ShippingRateRequest describes one package, the weight is in grams
+type ShippingRateRequest struct {
+	OriginProvince      string
+	DestinationProvince string
+	Weight              int
+}
+
+type ShippingRate struct {
+	Courier string
+	Service string
+	Fee     int
+	Etd     string
+}
+
+// ShippingRateProvider quotes what the couriers charge to send a package, ErrShippingRouteUnavailable is returned when none of them ship there
+type ShippingRateProvider interface {
+	Rates(ctx context.Context, request ShippingRateRequest) ([]ShippingRate, error)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	shippingZoneProvince = iota
+	shippingZoneIsland
+	shippingZoneInterIsland
+)
+
+// TableShippingRate is what a courier service charges per started kilogram in each zone
+type TableShippingRate struct {
+	Courier string
+	Service string
+	PerKg   [3]int
+	Etd     [3]string
+}
+
+// provinceIslands groups the provinces by the island they are on, packages that cross islands cost more
+var provinceIslands = map[string]string{
+	"aceh":                      "sumatera",
+	"sumatera utara":            "sumatera",
+	"sumatera barat":            "sumatera",
+	"riau":                      "sumatera",
+	"kepulauan riau":            "sumatera",
+	"jambi":                     "sumatera",
+	"sumatera selatan":          "sumatera",
+	"kepulauan bangka belitung": "sumatera",
+	"bengkulu":                  "sumatera",
+	"lampung":                   "sumatera",
+	"banten":                    "jawa",
+	"dki jakarta":               "jawa",
+	"jawa barat":                "jawa",
+	"jawa tengah":               "jawa",
+	"di yogyakarta":             "jawa",
+	"jawa timur":                "jawa",
+	"bali":                      "nusa tenggara",
+	"nusa tenggara barat":       "nusa tenggara",
+	"nusa tenggara timur":       "nusa tenggara",
+	"kalimantan barat":          "kalimantan",
+	"kalimantan tengah":         "kalimantan",
+	"kalimantan selatan":        "kalimantan",
+	"kalimantan timur":          "kalimantan",
+	"kalimantan utara":          "kalimantan",
+	"sulawesi utara":            "sulawesi",
+	"gorontalo":                 "sulawesi",
+	"sulawesi tengah":           "sulawesi",
+	"sulawesi barat":            "sulawesi",
+	"sulawesi selatan":          "sulawesi",
+	"sulawesi tenggara":         "sulawesi",
+	"maluku":                    "maluku",
+	"maluku utara":              "maluku",
+	"papua":                     "papua",
+	"papua barat":               "papua",
+	"papua barat daya":          "papua",
+	"papua tengah":              "papua",
+	"papua pegunungan":          "papua",
+	"papua selatan":             "papua",
+}
+
+var provinceAliases = map[string]string{
+	"jakarta":    "dki jakarta",
+	"yogyakarta": "di yogyakarta",
+	"diy":        "di yogyakarta",
+}
+
+var defaultTableShippingRates = []TableShippingRate{
+	{Courier: "jne", Service: "REG", PerKg: [3]int{9000, 16000, 32000}, Etd: [3]string{"1-2", "2-3", "3-6"}},
+	{Courier: "jne", Service: "YES", PerKg: [3]int{18000, 28000, 56000}, Etd: [3]string{"1", "1", "1-2"}},
+	{Courier: "pos", Service: "Kilat Khusus", PerKg: [3]int{8000, 14000, 29000}, Etd: [3]string{"2-3", "3-4", "4-7"}},
+	{Courier: "sicepat", Service: "REG", PerKg: [3]int{8500, 15000, 30000}, Etd: [3]string{"1-2", "2-3", "3-5"}},
+}
+
+type TableShippingRateProviderImpl struct {
+	Table []TableShippingRate
+}
+
+// NewTableShippingRateProvider quotes from the given table, the built-in table is used when it is empty
+func NewTableShippingRateProvider(table []TableShippingRate) ShippingRateProvider {
+	if len(table) == 0 {
+		table = defaultTableShippingRates
+	}
+	return &TableShippingRateProviderImpl{
+		Table: table,
+	}
+}
+
+func (provider *TableShippingRateProviderImpl) Rates(ctx context.Context, request ShippingRateRequest) ([]ShippingRate, error) {
+	origin, originOk := shippingProvince(request.OriginProvince)
+	destination, destinationOk := shippingProvince(request.DestinationProvince)
+	if !originOk || !destinationOk {
+		return nil, ErrShippingRouteUnavailable
+	}
+
+	zone := shippingZoneInterIsland
+	if origin == destination {
+		zone = shippingZoneProvince
+	} else if provinceIslands[origin] == provinceIslands[destination] {
+		zone = shippingZoneIsland
+	}
+
+	// every started kilogram is charged, a package weighs at least one
+	kg := (request.Weight + 999) / 1000
+	if kg < 1 {
+		kg = 1
+	}
+
+	var rates []ShippingRate
+	for _, v := range provider.Table {
+		rates = append(rates, ShippingRate{
+			Courier: v.Courier,
+			Service: v.Service,
+			Fee:     v.PerKg[zone] * kg,
+			Etd:     v.Etd[zone],
+		})
+	}
+	return rates, nil
+}
+
+func shippingProvince(province string) (string, bool) {
+	province = strings.ToLower(strings.TrimSpace(province))
+	if alias, ok := provinceAliases[province]; ok {
+		province = alias
+	}
+	_, ok := provinceIslands[province]
+	return province, ok
+}
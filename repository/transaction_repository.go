@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"weplant-backend/model/schema"
+)
+
+type TransactionRepository interface {
+	Create(ctx context.Context, transaction schema.Transaction) (schema.Transaction, error)
+	FindById(ctx context.Context, transactionId string) (schema.Transaction, error)
+	FindByCustomerId(ctx context.Context, customerId string, page Page) ([]schema.Transaction, PageInfo, error)
+	CountByCustomerId(ctx context.Context, customerId string) (int, error)
+	Delete(ctx context.Context, transactionId string) error
+}
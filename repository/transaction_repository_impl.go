@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+type TransactionRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewTransactionRepository(collection *mongo.Collection) TransactionRepository {
+	return &TransactionRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *TransactionRepositoryImpl) Create(ctx context.Context, transaction schema.Transaction) (schema.Transaction, error) {
+	ctx = withOperation(ctx, "TransactionRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, transaction)
+	if err != nil {
+		return transaction, err
+	}
+	transaction.Id = res.InsertedID.(primitive.ObjectID)
+	return transaction, nil
+}
+
+func (repository *TransactionRepositoryImpl) FindById(ctx context.Context, transactionId string) (schema.Transaction, error) {
+	ctx = withOperation(ctx, "TransactionRepository", "FindById")
+	var transaction schema.Transaction
+	objectId := helper.ObjectIDFromHex(transactionId)
+	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&transaction)
+	if err != nil {
+		return transaction, err
+	}
+	return transaction, nil
+}
+
+func (repository *TransactionRepositoryImpl) FindByCustomerId(ctx context.Context, customerId string, page Page) ([]schema.Transaction, PageInfo, error) {
+	ctx = withOperation(ctx, "TransactionRepository", "FindByCustomerId")
+	var transactions []schema.Transaction
+	pageInfo, err := findPage(ctx, repository.Collection, bson.D{{"customer_id", customerId}}, page, "created_at", -1, &transactions)
+	return transactions, pageInfo, err
+}
+
+func (repository *TransactionRepositoryImpl) CountByCustomerId(ctx context.Context, customerId string) (int, error) {
+	ctx = withOperation(ctx, "TransactionRepository", "CountByCustomerId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{{"customer_id", customerId}})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
+func (repository *TransactionRepositoryImpl) Delete(ctx context.Context, transactionId string) error {
+	ctx = withOperation(ctx, "TransactionRepository", "Delete")
+	objectId := helper.ObjectIDFromHex(transactionId)
+	_, err := repository.Collection.DeleteOne(ctx, bson.D{{"_id", objectId}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"weplant-backend/model/schema"
+)
+
+// VoucherRepository looks platform vouchers up with an empty merchant id
+type VoucherRepository interface {
+	Create(ctx context.Context, voucher schema.Voucher) (schema.Voucher, error)
+	FindById(ctx context.Context, voucherId string) (schema.Voucher, error)
+	FindByCode(ctx context.Context, code string) (schema.Voucher, error)
+	FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Voucher, PageInfo, error)
+	CountByMerchantId(ctx context.Context, merchantId string) (int, error)
+	Update(ctx context.Context, voucher schema.Voucher) (schema.Voucher, error)
+	Delete(ctx context.Context, voucherId string) error
+	IncrementUsage(ctx context.Context, voucherId string) error
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+)
+
+type VoucherRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewVoucherRepository(collection *mongo.Collection) VoucherRepository {
+	return &VoucherRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *VoucherRepositoryImpl) Create(ctx context.Context, voucher schema.Voucher) (schema.Voucher, error) {
+	ctx = withOperation(ctx, "VoucherRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, voucher)
+	if err != nil {
+		return voucher, err
+	}
+	voucher.Id = res.InsertedID.(primitive.ObjectID)
+	return voucher, nil
+}
+
+func (repository *VoucherRepositoryImpl) FindById(ctx context.Context, voucherId string) (schema.Voucher, error) {
+	ctx = withOperation(ctx, "VoucherRepository", "FindById")
+	var voucher schema.Voucher
+	objectId := helper.ObjectIDFromHex(voucherId)
+	err := repository.Collection.FindOne(ctx, bson.D{{"_id", objectId}}).Decode(&voucher)
+	if err != nil {
+		return voucher, err
+	}
+	return voucher, nil
+}
+
+func (repository *VoucherRepositoryImpl) FindByCode(ctx context.Context, code string) (schema.Voucher, error) {
+	ctx = withOperation(ctx, "VoucherRepository", "FindByCode")
+	var voucher schema.Voucher
+	err := repository.Collection.FindOne(ctx, bson.D{{"code", code}}).Decode(&voucher)
+	if err != nil {
+		return voucher, err
+	}
+	return voucher, nil
+}
+
+func (repository *VoucherRepositoryImpl) FindByMerchantId(ctx context.Context, merchantId string, page Page) ([]schema.Voucher, PageInfo, error) {
+	ctx = withOperation(ctx, "VoucherRepository", "FindByMerchantId")
+	var vouchers []schema.Voucher
+	pageInfo, err := findPage(ctx, repository.Collection, voucherMerchantFilter(merchantId), page, "created_at", -1, &vouchers)
+	return vouchers, pageInfo, err
+}
+
+func (repository *VoucherRepositoryImpl) CountByMerchantId(ctx context.Context, merchantId string) (int, error) {
+	ctx = withOperation(ctx, "VoucherRepository", "CountByMerchantId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, voucherMerchantFilter(merchantId))
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
+
+// Update leaves the usage counter alone, it is only moved by IncrementUsage
+func (repository *VoucherRepositoryImpl) Update(ctx context.Context, voucher schema.Voucher) (schema.Voucher, error) {
+	ctx = withOperation(ctx, "VoucherRepository", "Update")
+	_, err := repository.Collection.UpdateByID(ctx, voucher.Id, bson.D{
+		{"$set", bson.D{
+			{"updated_at", voucher.UpdatedAt},
+			{"code", voucher.Code},
+			{"category_ids", voucher.CategoryIds},
+			{"type", voucher.Type},
+			{"value", voucher.Value},
+			{"min_spend", voucher.MinSpend},
+			{"max_discount", voucher.MaxDiscount},
+			{"start_at", voucher.StartAt},
+			{"end_at", voucher.EndAt},
+			{"usage_limit", voucher.UsageLimit},
+			{"per_customer_limit", voucher.PerCustomerLimit},
+		}},
+	})
+	if err != nil {
+		return voucher, err
+	}
+	return voucher, nil
+}
+
+func (repository *VoucherRepositoryImpl) Delete(ctx context.Context, voucherId string) error {
+	ctx = withOperation(ctx, "VoucherRepository", "Delete")
+	objectId := helper.ObjectIDFromHex(voucherId)
+	_, err := repository.Collection.DeleteOne(ctx, bson.D{{"_id", objectId}})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (repository *VoucherRepositoryImpl) IncrementUsage(ctx context.Context, voucherId string) error {
+	ctx = withOperation(ctx, "VoucherRepository", "IncrementUsage")
+	objectId := helper.ObjectIDFromHex(voucherId)
+	_, err := repository.Collection.UpdateByID(ctx, objectId, bson.D{
+		{"$inc", bson.D{{"used_count", 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// voucherMerchantFilter matches the platform vouchers when the merchant id is empty
+func voucherMerchantFilter(merchantId string) bson.D {
+	if merchantId == "" {
+		return bson.D{{"merchant_id", bson.D{{"$exists", false}}}}
+	}
+	return bson.D{{"merchant_id", merchantId}}
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"weplant-backend/model/schema"
+)
+
+type VoucherUsageRepository interface {
+	Create(ctx context.Context, usage schema.VoucherUsage) (schema.VoucherUsage, error)
+	CountByCustomerId(ctx context.Context, voucherId string, customerId string) (int, error)
+}
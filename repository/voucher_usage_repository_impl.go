@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/model/schema"
+)
+
+type VoucherUsageRepositoryImpl struct {
+	Collection *mongo.Collection
+}
+
+func NewVoucherUsageRepository(collection *mongo.Collection) VoucherUsageRepository {
+	return &VoucherUsageRepositoryImpl{
+		Collection: collection,
+	}
+}
+
+func (repository *VoucherUsageRepositoryImpl) Create(ctx context.Context, usage schema.VoucherUsage) (schema.VoucherUsage, error) {
+	ctx = withOperation(ctx, "VoucherUsageRepository", "Create")
+	res, err := repository.Collection.InsertOne(ctx, usage)
+	if err != nil {
+		return usage, err
+	}
+	usage.Id = res.InsertedID.(primitive.ObjectID)
+	return usage, nil
+}
+
+func (repository *VoucherUsageRepositoryImpl) CountByCustomerId(ctx context.Context, voucherId string, customerId string) (int, error) {
+	ctx = withOperation(ctx, "VoucherUsageRepository", "CountByCustomerId")
+	itemCount, err := repository.Collection.CountDocuments(ctx, bson.D{
+		{"voucher_id", voucherId},
+		{"customer_id", customerId},
+	})
+	if err != nil {
+		return int(itemCount), err
+	}
+	return int(itemCount), nil
+}
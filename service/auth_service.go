@@ -6,6 +6,8 @@ import (
 )
 
 type AuthService interface {
-	LoginCustomer(ctx context.Context, request web.LoginRequest) web.TokenResponse
-	LoginMerchant(ctx context.Context, request web.LoginRequest) web.TokenResponse
+	LoginCustomer(ctx context.Context, request web.LoginRequest) (web.TokenResponse, error)
+	LoginMerchant(ctx context.Context, request web.LoginRequest) (web.TokenResponse, error)
+	Refresh(ctx context.Context, request web.RefreshTokenRequest) (web.TokenResponse, error)
+	Logout(ctx context.Context, request web.RefreshTokenRequest) error
 }
@@ -3,56 +3,131 @@ package service
 import (
 	"context"
 	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+	"weplant-backend/exception"
 	"weplant-backend/helper"
+	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
 	"weplant-backend/pkg"
 	"weplant-backend/repository"
 )
 
 type AuthServiceImpl struct {
-	MerchantRepository repository.MerchantRepository
-	CustomerRepository repository.CustomerRepository
+	MerchantRepository     repository.MerchantRepository
+	CustomerRepository     repository.CustomerRepository
+	RefreshTokenRepository repository.RefreshTokenRepository
 }
 
-func NewAuthService(merchantRepository repository.MerchantRepository, customerRepository repository.CustomerRepository) AuthService {
+func NewAuthService(merchantRepository repository.MerchantRepository, customerRepository repository.CustomerRepository, refreshTokenRepository repository.RefreshTokenRepository) AuthService {
 	return &AuthServiceImpl{
-		MerchantRepository: merchantRepository,
-		CustomerRepository: customerRepository,
+		MerchantRepository:     merchantRepository,
+		CustomerRepository:     customerRepository,
+		RefreshTokenRepository: refreshTokenRepository,
 	}
 }
 
-func (service *AuthServiceImpl) LoginCustomer(ctx context.Context, request web.LoginRequest) web.TokenResponse {
+func (service *AuthServiceImpl) LoginCustomer(ctx context.Context, request web.LoginRequest) (web.TokenResponse, error) {
 	customer, err := service.CustomerRepository.FindByEmail(ctx, request.Email)
-	helper.PanicIfError(err)
-	if !pkg.CheckPasswordHash(request.Password, customer.Password) {
-		panic(errors.New("password not match").Error())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("email or password is wrong")
+	} else if err != nil {
+		return web.TokenResponse{}, err
 	}
-	token := pkg.GenerateToken(web.JWTPayload{
-		Id:   customer.Id.Hex(),
-		Role: "customer",
-	})
-	return web.TokenResponse{
-		Id:    customer.Id.Hex(),
-		Role:  "customer",
-		Token: token,
+	if !pkg.CheckPasswordHash(request.Password, customer.Password) {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("email or password is wrong")
 	}
+	return generateTokenResponse(ctx, service.RefreshTokenRepository, customer.Id.Hex(), "customer", primitive.NewObjectID().Hex())
 }
 
-func (service *AuthServiceImpl) LoginMerchant(ctx context.Context, request web.LoginRequest) web.TokenResponse {
+func (service *AuthServiceImpl) LoginMerchant(ctx context.Context, request web.LoginRequest) (web.TokenResponse, error) {
 	merchant, err := service.MerchantRepository.FindByEmail(ctx, request.Email)
-	helper.PanicIfError(err)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("email or password is wrong")
+	} else if err != nil {
+		return web.TokenResponse{}, err
+	}
 	if !pkg.CheckPasswordHash(request.Password, merchant.Password) {
-		panic(errors.New("password not match").Error())
+		return web.TokenResponse{}, exception.NewUnauthorizedError("email or password is wrong")
 	}
+	return generateTokenResponse(ctx, service.RefreshTokenRepository, merchant.Id.Hex(), "merchant", primitive.NewObjectID().Hex())
+}
 
-	token := pkg.GenerateToken(web.JWTPayload{
-		Id:   merchant.Id.Hex(),
-		Role: "merchant",
-	})
-	return web.TokenResponse{
-		Id:    merchant.Id.Hex(),
-		Role:  "merchant",
-		Token: token,
+func (service *AuthServiceImpl) Refresh(ctx context.Context, request web.RefreshTokenRequest) (web.TokenResponse, error) {
+	refreshToken, err := service.RefreshTokenRepository.FindByTokenHash(ctx, pkg.HashRefreshToken(request.RefreshToken))
+	if err != nil {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("refresh token is invalid")
+	}
+
+	// a revoked token being presented again means it leaked, so the whole family is killed
+	if refreshToken.Revoked {
+		err = service.RefreshTokenRepository.RevokeFamily(ctx, refreshToken.Family)
+		if err != nil {
+			return web.TokenResponse{}, err
+		}
+		return web.TokenResponse{}, exception.NewUnauthorizedError("refresh token has already been used")
+	}
+
+	if refreshToken.ExpiredAt < helper.GetTimeNow() {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("refresh token is expired")
+	}
+
+	res, err := generateTokenResponse(ctx, service.RefreshTokenRepository, refreshToken.UserId, refreshToken.Role, refreshToken.Family)
+	if err != nil {
+		return res, err
 	}
+
+	err = service.RefreshTokenRepository.Revoke(ctx, refreshToken.Id.Hex(), pkg.HashRefreshToken(res.RefreshToken))
+	if err != nil {
+		// lost the race against another request using the same token
+		errRevoke := service.RefreshTokenRepository.RevokeFamily(ctx, refreshToken.Family)
+		if errRevoke != nil {
+			return web.TokenResponse{}, errRevoke
+		}
+		return web.TokenResponse{}, exception.NewUnauthorizedError("refresh token has already been used")
+	}
+	return res, nil
 }
 
+func (service *AuthServiceImpl) Logout(ctx context.Context, request web.RefreshTokenRequest) error {
+	refreshToken, err := service.RefreshTokenRepository.FindByTokenHash(ctx, pkg.HashRefreshToken(request.RefreshToken))
+	if err != nil {
+		return exception.NewUnauthorizedError("refresh token is invalid")
+	}
+
+	return service.RefreshTokenRepository.RevokeFamily(ctx, refreshToken.Family)
+}
+
+// generateTokenResponse signs a new access token and stores a new refresh token in the given family
+func generateTokenResponse(ctx context.Context, refreshTokenRepository repository.RefreshTokenRepository, id string, role string, family string) (web.TokenResponse, error) {
+	timeNow := helper.GetTimeNow()
+
+	accessToken := pkg.GenerateToken(web.JWTPayload{
+		Id:   id,
+		Role: role,
+	})
+	refreshToken := pkg.GenerateRefreshToken()
+
+	_, err := refreshTokenRepository.Create(ctx, schema.RefreshToken{
+		CreatedAt: timeNow,
+		UpdatedAt: timeNow,
+		ExpiredAt: timeNow + int(pkg.RefreshTokenDuration/time.Second),
+		UserId:    id,
+		Role:      role,
+		Family:    family,
+		TokenHash: pkg.HashRefreshToken(refreshToken),
+		Revoked:   false,
+	})
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return web.TokenResponse{
+		Id:           id,
+		Role:         role,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiredAt:    timeNow + int(pkg.AccessTokenDuration/time.Second),
+	}, nil
+}
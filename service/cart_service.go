@@ -6,7 +6,7 @@ import (
 )
 
 type CartService interface {
-	PushProductToCart(ctx context.Context, request web.CartProductCreateRequest) web.CartProductCreateRequest
-	UpdateProductQuantity(ctx context.Context, request web.CartProductUpdateRequest) web.CartProductUpdateRequest
-	PullProductFromCart(ctx context.Context, customerId string, productId string)
+	PushProductToCart(ctx context.Context, request web.CartProductCreateRequest) (web.CartProductCreateRequest, error)
+	UpdateProductQuantity(ctx context.Context, request web.CartProductUpdateRequest) (web.CartProductUpdateRequest, error)
+	PullProductFromCart(ctx context.Context, customerId string, productId string, variantId string) error
 }
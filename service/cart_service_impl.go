@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"weplant-backend/helper"
+	"weplant-backend/exception"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
 	"weplant-backend/repository"
@@ -20,48 +20,74 @@ func NewCartService(customerRepository repository.CustomerRepository, productRep
 	}
 }
 
-func (service *CartServiceImpl) PushProductToCart(ctx context.Context, request web.CartProductCreateRequest) web.CartProductCreateRequest {
+func (service *CartServiceImpl) PushProductToCart(ctx context.Context, request web.CartProductCreateRequest) (web.CartProductCreateRequest, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
 
 	product, err := service.ProductRepository.FindById(ctx, request.ProductId)
-	helper.PanicIfError(err)
+	if err != nil {
+		return request, err
+	}
+
+	_, err = sellableVariant(product, request.VariantId)
+	if err != nil {
+		return request, err
+	}
 
 	err = service.CustomerRepository.PushProductToCart(ctx, customer.Id.Hex(), schema.CartProduct{
 		ProductId: product.Id.Hex(),
+		VariantId: request.VariantId,
 		Quantity:  request.Quantity,
 	})
-	helper.PanicIfError(err)
-	return request
+	if err != nil {
+		return request, err
+	}
+	return request, nil
 }
 
-func (service *CartServiceImpl) UpdateProductQuantity(ctx context.Context, request web.CartProductUpdateRequest) web.CartProductUpdateRequest {
+func (service *CartServiceImpl) UpdateProductQuantity(ctx context.Context, request web.CartProductUpdateRequest) (web.CartProductUpdateRequest, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
 
 	product, err := service.ProductRepository.FindById(ctx, request.ProductId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
 
 	err = service.CustomerRepository.UpdateProductQuantity(ctx, customer.Id.Hex(), schema.CartProduct{
 		ProductId: product.Id.Hex(),
+		VariantId: request.VariantId,
 		Quantity:  request.Quantity,
 	})
-	helper.PanicIfError(err)
+	if err != nil {
+		return request, err
+	}
 
-	return request
+	return request, nil
 }
 
-func (service *CartServiceImpl) PullProductFromCart(ctx context.Context, customerId string, productId string) {
+func (service *CartServiceImpl) PullProductFromCart(ctx context.Context, customerId string, productId string, variantId string) error {
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
 
 	product, err := service.ProductRepository.FindById(ctx, productId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
 
 	for _, v := range customer.Carts {
-		if v.ProductId == product.Id.Hex() {
-			err = service.CustomerRepository.PullProductFromCart(ctx, customer.Id.Hex(), product.Id.Hex())
-			helper.PanicIfError(err)
+		if v.ProductId == product.Id.Hex() && v.VariantId == variantId {
+			err = service.CustomerRepository.PullProductFromCart(ctx, customer.Id.Hex(), v)
+			if err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
@@ -6,7 +6,11 @@ import (
 )
 
 type CategoryService interface {
-	Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryCreateRequestResponse
-	FindById(ctx context.Context, categoryId string) web.CategoryDetailResponse
-	FindAll(ctx context.Context) []web.CategorySimpleResponse
+	Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryCreateRequestResponse, error)
+	FindById(ctx context.Context, categoryId string, cursor string, perPage int) (web.CategoryDetailResponse, error)
+	FindAll(ctx context.Context, cursor string, perPage int) (web.CategoryFindAllResponse, error)
+	FindTree(ctx context.Context) ([]web.CategoryTreeResponse, error)
+	Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryUpdateRequestResponse, error)
+	UpdateMainImage(ctx context.Context, request web.CategoryUpdateImageRequest) (web.CategoryUpdateImageRequestResponse, error)
+	Delete(ctx context.Context, categoryId string) error
 }
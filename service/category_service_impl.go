@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"weplant-backend/exception"
 	"weplant-backend/helper"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
@@ -11,39 +12,69 @@ import (
 type CategoryServiceImpl struct {
 	CategoryRepository repository.CategoryRepository
 	ProductRepository  repository.ProductRepository
+	ImageRepository    repository.ImageRepository
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository, productRepository repository.ProductRepository) CategoryService {
+func NewCategoryService(categoryRepository repository.CategoryRepository, productRepository repository.ProductRepository, imageRepository repository.ImageRepository) CategoryService {
 	return &CategoryServiceImpl{
-		CategoryRepository:   categoryRepository,
-		ProductRepository:    productRepository,
+		CategoryRepository: categoryRepository,
+		ProductRepository:  productRepository,
+		ImageRepository:    imageRepository,
 	}
 }
 
-func (service *CategoryServiceImpl) Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryCreateRequestResponse {
+func (service *CategoryServiceImpl) Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryCreateRequestResponse, error) {
+	if request.ParentId != "" {
+		_, err := service.CategoryRepository.FindById(ctx, request.ParentId)
+		if err != nil {
+			return web.CategoryCreateRequestResponse{}, exception.NewNotFoundError(err.Error())
+		}
+	}
+
 	res, err := service.CategoryRepository.Create(ctx, schema.Category{
 		CreatedAt: request.CreatedAt,
 		UpdatedAt: request.UpdatedAt,
+		ParentId:  request.ParentId,
 		Name:      request.Name,
 		Slug:      request.Slug,
 	})
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.CategoryCreateRequestResponse{}, helper.WrapDuplicateKeyError(err, "category "+request.Name+" already exists")
+	}
 
 	return web.CategoryCreateRequestResponse{
 		Id:        res.Id.Hex(),
 		CreatedAt: res.CreatedAt,
 		UpdatedAt: res.UpdatedAt,
+		ParentId:  res.ParentId,
 		Name:      res.Name,
 		Slug:      res.Slug,
-	}
+	}, nil
 }
 
-func (service *CategoryServiceImpl) FindById(ctx context.Context, categoryId string) web.CategoryDetailResponse {
+func (service *CategoryServiceImpl) FindById(ctx context.Context, categoryId string, cursor string, perPage int) (web.CategoryDetailResponse, error) {
 	category, err := service.CategoryRepository.FindById(ctx, categoryId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.CategoryDetailResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
-	products, err := service.ProductRepository.FindByCategoryId(ctx, category.Id.Hex())
-	helper.PanicIfError(err)
+	categories, err := service.CategoryRepository.FindAll(ctx)
+	if err != nil {
+		return web.CategoryDetailResponse{}, err
+	}
+
+	// products of every subcategory are listed under their ancestors too
+	categoryIds := append([]string{category.Id.Hex()}, categoryDescendantIds(categories, category.Id.Hex())...)
+
+	products, pageInfo, err := service.ProductRepository.FindByCategoryIds(ctx, categoryIds, repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.CategoryDetailResponse{}, pageError(err)
+	}
+
+	itemCount, err := service.ProductRepository.CountByCategoryIds(ctx, categoryIds)
+	if err != nil {
+		return web.CategoryDetailResponse{}, err
+	}
 
 	var productsResponse []web.ProductSimpleResponse
 	for _, product := range products {
@@ -55,35 +86,260 @@ func (service *CategoryServiceImpl) FindById(ctx context.Context, categoryId str
 			Description: product.Description,
 			Price:       product.Price,
 			Stock:       product.Stock,
-			MainImage: web.ImageResponse{
-				Id:       product.MainImage.Id.Hex(),
-				FileName: product.MainImage.FileName,
-				URL:      product.MainImage.URL,
-			},
+			MainImage:   imageResponse(product.MainImage),
 		})
 	}
 
+	var childrenResponse []web.CategorySimpleResponse
+	for _, child := range categories {
+		if child.ParentId == category.Id.Hex() {
+			childrenResponse = append(childrenResponse, categorySimpleResponse(child))
+		}
+	}
+
 	return web.CategoryDetailResponse{
+		Id:          category.Id.Hex(),
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+		ParentId:    category.ParentId,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		MainImage:   imageResponse(category.MainImage),
+		Breadcrumbs: categoryBreadcrumbs(categories, category),
+		Children:    childrenResponse,
+		Products:    productsResponse,
+		Metadata:    cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+func (service *CategoryServiceImpl) FindAll(ctx context.Context, cursor string, perPage int) (web.CategoryFindAllResponse, error) {
+	categories, pageInfo, err := service.CategoryRepository.FindPage(ctx, repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.CategoryFindAllResponse{}, pageError(err)
+	}
+
+	itemCount, err := service.CategoryRepository.CountDocuments(ctx)
+	if err != nil {
+		return web.CategoryFindAllResponse{}, err
+	}
+
+	var categoriesResponse []web.CategorySimpleResponse
+	for _, category := range categories {
+		categoriesResponse = append(categoriesResponse, categorySimpleResponse(category))
+	}
+	return web.CategoryFindAllResponse{
+		Categories: categoriesResponse,
+		Metadata:   cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+func (service *CategoryServiceImpl) FindTree(ctx context.Context) ([]web.CategoryTreeResponse, error) {
+	categories, err := service.CategoryRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return categoryTree(categories, ""), nil
+}
+
+func (service *CategoryServiceImpl) Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryUpdateRequestResponse, error) {
+	category, err := service.CategoryRepository.FindById(ctx, request.Id)
+	if err != nil {
+		return web.CategoryUpdateRequestResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	if request.ParentId != "" {
+		_, err := service.CategoryRepository.FindById(ctx, request.ParentId)
+		if err != nil {
+			return web.CategoryUpdateRequestResponse{}, exception.NewNotFoundError(err.Error())
+		}
+
+		categories, err := service.CategoryRepository.FindAll(ctx)
+		if err != nil {
+			return web.CategoryUpdateRequestResponse{}, err
+		}
+
+		// a category can not be moved under itself or one of its own subcategories
+		cyclic := request.ParentId == request.Id
+		for _, descendantId := range categoryDescendantIds(categories, request.Id) {
+			if descendantId == request.ParentId {
+				cyclic = true
+			}
+		}
+		if cyclic {
+			return web.CategoryUpdateRequestResponse{}, exception.NewValidationError("a category can not be moved under itself", web.FieldErrorResponse{
+				Field:   "parent_id",
+				Message: "must not be the category or one of its subcategories",
+			})
+		}
+	}
+
+	renamed := category.Name != request.Name
+	category.UpdatedAt = request.UpdatedAt
+	category.ParentId = request.ParentId
+	category.Name = request.Name
+	category.Slug = request.Slug
+
+	res, err := service.CategoryRepository.Update(ctx, category)
+	if err != nil {
+		return web.CategoryUpdateRequestResponse{}, helper.WrapDuplicateKeyError(err, "category "+request.Name+" already exists")
+	}
+
+	// products keep a copy of the name for the text search
+	if renamed {
+		err = service.ProductRepository.UpdateCategoryName(ctx, request.Id, request.Name)
+		if err != nil {
+			return web.CategoryUpdateRequestResponse{}, err
+		}
+	}
+
+	return web.CategoryUpdateRequestResponse{
+		Id:        res.Id.Hex(),
+		UpdatedAt: res.UpdatedAt,
+		ParentId:  res.ParentId,
+		Name:      res.Name,
+		Slug:      res.Slug,
+	}, nil
+}
+
+func (service *CategoryServiceImpl) UpdateMainImage(ctx context.Context, request web.CategoryUpdateImageRequest) (web.CategoryUpdateImageRequestResponse, error) {
+	category, err := service.CategoryRepository.FindById(ctx, request.Id)
+	if err != nil {
+		return web.CategoryUpdateImageRequestResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	mainImage, err := uploadImage(ctx, service.ImageRepository, *request.MainImage)
+	if err != nil {
+		return web.CategoryUpdateImageRequestResponse{}, err
+	}
+
+	oldImage := category.MainImage
+	category.UpdatedAt = request.UpdatedAt
+	category.MainImage = &mainImage
+
+	_, err = service.CategoryRepository.Update(ctx, category)
+	if err != nil {
+		return web.CategoryUpdateImageRequestResponse{}, err
+	}
+
+	if oldImage != nil {
+		err = deleteImage(ctx, service.ImageRepository, *oldImage)
+		if err != nil {
+			return web.CategoryUpdateImageRequestResponse{}, err
+		}
+	}
+
+	return web.CategoryUpdateImageRequestResponse{
 		Id:        category.Id.Hex(),
-		CreatedAt: category.CreatedAt,
 		UpdatedAt: category.UpdatedAt,
-		Name:      category.Name,
-		Slug:      category.Slug,
-		Products:  productsResponse,
-	}
+		MainImage: imageResponse(category.MainImage),
+	}, nil
 }
 
-func (service *CategoryServiceImpl) FindAll(ctx context.Context) []web.CategorySimpleResponse {
+func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId string) error {
+	category, err := service.CategoryRepository.FindById(ctx, categoryId)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
+
 	categories, err := service.CategoryRepository.FindAll(ctx)
-	helper.PanicIfError(err)
+	if err != nil {
+		return err
+	}
+	for _, child := range categories {
+		if child.ParentId == categoryId {
+			return exception.NewConflictError("category " + category.Name + " still has subcategories")
+		}
+	}
 
-	var categoriesResponse []web.CategorySimpleResponse
+	err = service.ProductRepository.PullCategoryIdFromProduct(ctx, categoryId)
+	if err != nil {
+		return err
+	}
+
+	err = service.CategoryRepository.Delete(ctx, categoryId)
+	if err != nil {
+		return err
+	}
+
+	if category.MainImage != nil {
+		err = deleteImage(ctx, service.ImageRepository, *category.MainImage)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// categoryDescendantIds walks the parent links down from categoryId
+func categoryDescendantIds(categories []schema.Category, categoryId string) []string {
+	var descendantIds []string
+	parentIds := []string{categoryId}
+	seen := map[string]bool{categoryId: true}
+	for len(parentIds) > 0 {
+		var nextIds []string
+		for _, category := range categories {
+			id := category.Id.Hex()
+			if seen[id] {
+				continue
+			}
+			for _, parentId := range parentIds {
+				if category.ParentId == parentId {
+					seen[id] = true
+					descendantIds = append(descendantIds, id)
+					nextIds = append(nextIds, id)
+					break
+				}
+			}
+		}
+		parentIds = nextIds
+	}
+	return descendantIds
+}
+
+// categoryBreadcrumbs lists the ancestors from the root down to the category itself
+func categoryBreadcrumbs(categories []schema.Category, category schema.Category) []web.CategorySimpleResponse {
+	byId := make(map[string]schema.Category)
+	for _, c := range categories {
+		byId[c.Id.Hex()] = c
+	}
+
+	breadcrumbs := []web.CategorySimpleResponse{categorySimpleResponse(category)}
+	seen := map[string]bool{category.Id.Hex(): true}
+	for parentId := category.ParentId; parentId != "" && !seen[parentId]; {
+		parent, ok := byId[parentId]
+		if !ok {
+			break
+		}
+		seen[parentId] = true
+		breadcrumbs = append([]web.CategorySimpleResponse{categorySimpleResponse(parent)}, breadcrumbs...)
+		parentId = parent.ParentId
+	}
+	return breadcrumbs
+}
+
+func categoryTree(categories []schema.Category, parentId string) []web.CategoryTreeResponse {
+	var tree []web.CategoryTreeResponse
 	for _, category := range categories {
-		categoriesResponse = append(categoriesResponse, web.CategorySimpleResponse{
-			Id:   category.Id.Hex(),
-			Name: category.Name,
-			Slug: category.Slug,
+		if category.ParentId != parentId {
+			continue
+		}
+		tree = append(tree, web.CategoryTreeResponse{
+			Id:        category.Id.Hex(),
+			Name:      category.Name,
+			Slug:      category.Slug,
+			MainImage: imageResponse(category.MainImage),
+			Children:  categoryTree(categories, category.Id.Hex()),
 		})
 	}
-	return categoriesResponse
+	return tree
+}
+
+func categorySimpleResponse(category schema.Category) web.CategorySimpleResponse {
+	return web.CategorySimpleResponse{
+		Id:       category.Id.Hex(),
+		ParentId: category.ParentId,
+		Name:     category.Name,
+		Slug:     category.Slug,
+	}
 }
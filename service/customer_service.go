@@ -6,12 +6,14 @@ import (
 )
 
 type CustomerService interface {
-	Create(ctx context.Context, request web.CustomerCreateRequest) web.TokenResponse
-	FindById(ctx context.Context, customerId string) web.CustomerResponse
-	FindCartById(ctx context.Context, customerId string) web.CartResponse
-	FindTransactionById(ctx context.Context, customerId string) web.TransactionResponse
-	FindOrderById(ctx context.Context, customerId string) web.OrderResponse
-	Update(ctx context.Context, request web.CustomerUpdateRequest) web.CustomerUpdateRequest
-	UpdateMainImage(ctx context.Context, request web.CustomerUpdateImageRequest) web.CustomerUpdateImageRequestResponse
-	Delete(ctx context.Context, customerId string)
+	Create(ctx context.Context, request web.CustomerCreateRequest) (web.TokenResponse, error)
+	FindById(ctx context.Context, customerId string) (web.CustomerResponse, error)
+	FindCartById(ctx context.Context, customerId string) (web.CartResponse, error)
+	FindTransactionById(ctx context.Context, customerId string, cursor string, perPage int) (web.TransactionResponse, error)
+	FindOrderById(ctx context.Context, customerId string, cursor string, perPage int) (web.OrderResponse, error)
+	ConfirmOrder(ctx context.Context, request web.OrderConfirmRequest) (web.OrderDetailResponse, error)
+	RequestRefund(ctx context.Context, request web.OrderRefundRequest) (web.OrderDetailResponse, error)
+	Update(ctx context.Context, request web.CustomerUpdateRequest) (web.CustomerUpdateRequest, error)
+	UpdateMainImage(ctx context.Context, request web.CustomerUpdateImageRequest) (web.CustomerUpdateImageRequestResponse, error)
+	Delete(ctx context.Context, customerId string) error
 }
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/exception"
 	"weplant-backend/helper"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
@@ -11,20 +13,32 @@ import (
 )
 
 type CustomerServiceImpl struct {
-	CustomerRepository   repository.CustomerRepository
-	ProductRepository    repository.ProductRepository
-	CloudinaryRepository repository.CloudinaryRepository
+	CustomerRepository     repository.CustomerRepository
+	ProductRepository      repository.ProductRepository
+	ImageRepository        repository.ImageRepository
+	RefreshTokenRepository repository.RefreshTokenRepository
+	TransactionRepository  repository.TransactionRepository
+	OrderRepository        repository.OrderRepository
+	LedgerRepository       repository.LedgerRepository
+	MerchantRepository     repository.MerchantRepository
+	SessionRepository      repository.SessionRepository
 }
 
-func NewCustomerService(customerRepository repository.CustomerRepository, productRepository repository.ProductRepository, cloudinaryRepository repository.CloudinaryRepository) CustomerService {
+func NewCustomerService(customerRepository repository.CustomerRepository, productRepository repository.ProductRepository, imageRepository repository.ImageRepository, refreshTokenRepository repository.RefreshTokenRepository, transactionRepository repository.TransactionRepository, orderRepository repository.OrderRepository, ledgerRepository repository.LedgerRepository, merchantRepository repository.MerchantRepository, sessionRepository repository.SessionRepository) CustomerService {
 	return &CustomerServiceImpl{
-		CustomerRepository:   customerRepository,
-		ProductRepository:    productRepository,
-		CloudinaryRepository: cloudinaryRepository,
+		CustomerRepository:     customerRepository,
+		ProductRepository:      productRepository,
+		ImageRepository:        imageRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+		TransactionRepository:  transactionRepository,
+		OrderRepository:        orderRepository,
+		LedgerRepository:       ledgerRepository,
+		MerchantRepository:     merchantRepository,
+		SessionRepository:      sessionRepository,
 	}
 }
 
-func (service *CustomerServiceImpl) Create(ctx context.Context, request web.CustomerCreateRequest) web.TokenResponse {
+func (service *CustomerServiceImpl) Create(ctx context.Context, request web.CustomerCreateRequest) (web.TokenResponse, error) {
 	res, err := service.CustomerRepository.Create(ctx, schema.Customer{
 		CreatedAt: request.CreatedAt,
 		UpdatedAt: request.UpdatedAt,
@@ -38,22 +52,18 @@ func (service *CustomerServiceImpl) Create(ctx context.Context, request web.Cust
 			URL:      "",
 		},
 	})
-	helper.PanicIfError(err)
-
-	token := pkg.GenerateToken(web.JWTPayload{
-		Id:   res.Id.Hex(),
-		Role: "customer",
-	})
-	return web.TokenResponse{
-		Id:    res.Id.Hex(),
-		Role:  "customer",
-		Token: token,
+	if err != nil {
+		return web.TokenResponse{}, helper.WrapDuplicateKeyError(err, "email "+request.Email+" is already registered")
 	}
+
+	return generateTokenResponse(ctx, service.RefreshTokenRepository, res.Id.Hex(), "customer", primitive.NewObjectID().Hex())
 }
 
-func (service *CustomerServiceImpl) FindById(ctx context.Context, customerId string) web.CustomerResponse {
+func (service *CustomerServiceImpl) FindById(ctx context.Context, customerId string) (web.CustomerResponse, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.CustomerResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
 	return web.CustomerResponse{
 		Id:        customer.Id.Hex(),
@@ -62,38 +72,41 @@ func (service *CustomerServiceImpl) FindById(ctx context.Context, customerId str
 		Email:     customer.Email,
 		UserName:  customer.UserName,
 		Phone:     customer.Phone,
-		MainImage: web.ImageResponse{
-			Id:       customer.MainImage.Id.Hex(),
-			FileName: customer.MainImage.FileName,
-			URL:      customer.MainImage.URL,
-		},
-	}
+		MainImage: imageResponse(customer.MainImage),
+	}, nil
 }
 
-func (service *CustomerServiceImpl) FindCartById(ctx context.Context, customerId string) web.CartResponse {
+func (service *CustomerServiceImpl) FindCartById(ctx context.Context, customerId string) (web.CartResponse, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.CartResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
 	var totalPrice int
 	var productsResponse []web.CartProductResponse
 
 	for _, product := range customer.Carts {
 		findProduct, err := service.ProductRepository.FindById(ctx, product.ProductId)
-		helper.PanicIfError(err)
-		subTotal := product.Quantity * findProduct.Price
-		productsResponse = append(productsResponse, web.CartProductResponse{
+		if err != nil {
+			return web.CartResponse{}, err
+		}
+		cartProductResponse := web.CartProductResponse{
 			ProductId:   findProduct.Id.Hex(),
 			Name:        findProduct.Name,
 			Slug:        findProduct.Slug,
 			Description: findProduct.Description,
 			Price:       findProduct.Price,
 			Quantity:    product.Quantity,
-			MainImage: web.ImageResponse{
-				Id:       findProduct.MainImage.Id.Hex(),
-				FileName: findProduct.MainImage.FileName,
-				URL:      findProduct.MainImage.URL,
-			},
-		})
+			MainImage:   imageResponse(findProduct.MainImage),
+		}
+		if variant, ok := findVariant(findProduct, product.VariantId); ok {
+			cartProductResponse.VariantId = product.VariantId
+			cartProductResponse.VariantName = variantName(variant)
+			cartProductResponse.Price = variant.Price
+			cartProductResponse.MainImage = variantImageResponse(findProduct, variant)
+		}
+		subTotal := product.Quantity * cartProductResponse.Price
+		productsResponse = append(productsResponse, cartProductResponse)
 		totalPrice += subTotal
 	}
 
@@ -101,20 +114,35 @@ func (service *CustomerServiceImpl) FindCartById(ctx context.Context, customerId
 		CustomerId: customer.Id.Hex(),
 		TotalPrice: totalPrice,
 		Products:   productsResponse,
-	}
+	}, nil
 }
-func (service *CustomerServiceImpl) FindTransactionById(ctx context.Context, customerId string) web.TransactionResponse {
+
+func (service *CustomerServiceImpl) FindTransactionById(ctx context.Context, customerId string, cursor string, perPage int) (web.TransactionResponse, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.TransactionResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	transactions, pageInfo, err := service.TransactionRepository.FindByCustomerId(ctx, customer.Id.Hex(), repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.TransactionResponse{}, pageError(err)
+	}
+
+	itemCount, err := service.TransactionRepository.CountByCustomerId(ctx, customer.Id.Hex())
+	if err != nil {
+		return web.TransactionResponse{}, err
+	}
 
 	var transactionsResponse []web.TransactionDetailResponse
 
-	for _, v := range customer.Transactions {
+	for _, v := range transactions {
 		var totalPrice int
 		var productsResponse []web.TransactionProductResponse
 		for _, p := range v.Products {
 			product, err := service.ProductRepository.FindById(ctx, p.ProductId)
-			helper.PanicIfError(err)
+			if err != nil {
+				return web.TransactionResponse{}, err
+			}
 
 			subTotal := p.Price * p.Quantity
 
@@ -122,18 +150,23 @@ func (service *CustomerServiceImpl) FindTransactionById(ctx context.Context, cus
 
 			productsResponse = append(productsResponse, web.TransactionProductResponse{
 				ProductId:   product.Id.Hex(),
+				VariantId:   p.VariantId,
+				VariantName: p.VariantName,
+				MerchantId:  product.MerchantId,
 				Name:        product.Name,
 				Slug:        product.Slug,
 				Description: product.Description,
 				Price:       p.Price,
 				Quantity:    p.Quantity,
-				MainImage: web.ImageResponse{
-					Id:       product.MainImage.Id.Hex(),
-					FileName: product.MainImage.FileName,
-					URL:      product.MainImage.URL,
-				},
+				MainImage:   imageResponse(product.MainImage),
 			})
 		}
+		for _, o := range v.Orders {
+			totalPrice += o.ShippingFee
+		}
+		if v.Voucher != nil {
+			totalPrice -= v.Voucher.Discount
+		}
 
 		transactionsResponse = append(transactionsResponse, web.TransactionDetailResponse{
 			Id:          v.Id.Hex(),
@@ -141,9 +174,11 @@ func (service *CustomerServiceImpl) FindTransactionById(ctx context.Context, cus
 			UpdatedAt:   v.UpdatedAt,
 			PaymentType: v.PaymentType,
 			Status:      v.Status,
-			QRCode:      v.QRCode,
+			Payment:     transactionPaymentResponse(v.Payment),
 			TotalPrice:  totalPrice,
 			Products:    productsResponse,
+			Orders:      transactionOrderResponses(v.Orders),
+			Voucher:     transactionVoucherResponse(v.Voucher),
 			Address: web.AddressResponse{
 				Address:    v.Address.Address,
 				City:       v.Address.City,
@@ -156,51 +191,141 @@ func (service *CustomerServiceImpl) FindTransactionById(ctx context.Context, cus
 	return web.TransactionResponse{
 		CustomerId:   customer.Id.Hex(),
 		Transactions: transactionsResponse,
-	}
+		Metadata:     cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
 }
 
-func (service *CustomerServiceImpl) FindOrderById(ctx context.Context, customerId string) web.OrderResponse {
+func (service *CustomerServiceImpl) FindOrderById(ctx context.Context, customerId string, cursor string, perPage int) (web.OrderResponse, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.OrderResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
-	var productsResponse []web.OrderProductResponse
-	for _, v := range customer.Orders {
-		product, err := service.ProductRepository.FindById(ctx, v.ProductId)
-		helper.PanicIfError(err)
-		productsResponse = append(productsResponse, web.OrderProductResponse{
-			Id:          v.Id.Hex(),
-			CreatedAt:   v.CreatedAt,
-			UpdatedAt:   v.UpdatedAt,
-			ProductId:   product.Id.Hex(),
-			Name:        product.Name,
-			Slug:        product.Slug,
-			Description: product.Description,
-			Price:       v.Price,
-			Quantity:    v.Quantity,
-			MainImage: web.ImageResponse{
-				Id:       product.MainImage.Id.Hex(),
-				FileName: product.MainImage.FileName,
-				URL:      product.MainImage.URL,
-			},
-			Address: web.AddressResponse{
-				Address:    v.Address.Address,
-				City:       v.Address.City,
-				Province:   v.Address.Province,
-				PostalCode: v.Address.PostalCode,
-			},
-		})
+	orders, pageInfo, err := service.OrderRepository.FindByCustomerId(ctx, customer.Id.Hex(), repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.OrderResponse{}, pageError(err)
+	}
+
+	itemCount, err := service.OrderRepository.CountByCustomerId(ctx, customer.Id.Hex())
+	if err != nil {
+		return web.OrderResponse{}, err
+	}
+
+	var ordersResponse []web.OrderDetailResponse
+	for _, v := range orders {
+		orderResponse, err := service.orderDetailResponse(ctx, v)
+		if err != nil {
+			return web.OrderResponse{}, err
+		}
+		ordersResponse = append(ordersResponse, orderResponse)
 	}
 
 	return web.OrderResponse{
 		CustomerId: customer.Id.Hex(),
-		Products:   productsResponse,
+		Orders:     ordersResponse,
+		Metadata:   cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+func (service *CustomerServiceImpl) ConfirmOrder(ctx context.Context, request web.OrderConfirmRequest) (web.OrderDetailResponse, error) {
+	order, err := service.findOrder(ctx, request.CustomerId, request.Id)
+	if err != nil {
+		return web.OrderDetailResponse{}, err
+	}
+
+	// the merchant is credited once the customer confirms, minus the platform commission
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		credited := orderWasCompleted(order)
+		completed, err := moveOrder(ctx, service.OrderRepository, order, schema.OrderHistory{
+			CreatedAt: request.UpdatedAt,
+			Status:    schema.OrderStatusCompleted,
+		}, nil)
+		if err != nil {
+			return err
+		}
+		if !credited {
+			total, commission := orderEarning(order)
+			err = recordLedger(ctx, service.LedgerRepository, service.MerchantRepository, order.MerchantId, schema.LedgerEntry{
+				CreatedAt:   request.UpdatedAt,
+				Type:        schema.LedgerTypeSale,
+				Amount:      total,
+				ReferenceId: order.Id.Hex(),
+			}, schema.LedgerEntry{
+				CreatedAt:   request.UpdatedAt,
+				Type:        schema.LedgerTypeCommission,
+				Amount:      -commission,
+				ReferenceId: order.Id.Hex(),
+				Note:        fmt.Sprintf("%d%% platform commission", CommissionPercent),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		order = completed
+		return nil
+	})
+	if err != nil {
+		return web.OrderDetailResponse{}, err
 	}
 
+	return service.orderDetailResponse(ctx, order)
+}
+
+func (service *CustomerServiceImpl) RequestRefund(ctx context.Context, request web.OrderRefundRequest) (web.OrderDetailResponse, error) {
+	order, err := service.findOrder(ctx, request.CustomerId, request.Id)
+	if err != nil {
+		return web.OrderDetailResponse{}, err
+	}
+
+	order, err = moveOrder(ctx, service.OrderRepository, order, schema.OrderHistory{
+		CreatedAt: request.UpdatedAt,
+		Status:    schema.OrderStatusRefundRequested,
+		Note:      request.Note,
+	}, nil)
+	if err != nil {
+		return web.OrderDetailResponse{}, err
+	}
+
+	return service.orderDetailResponse(ctx, order)
+}
+
+// findOrder hides orders of other customers behind a not found error
+func (service *CustomerServiceImpl) findOrder(ctx context.Context, customerId string, orderId string) (schema.Order, error) {
+	order, err := service.OrderRepository.FindById(ctx, orderId)
+	if err != nil || order.CustomerId != customerId {
+		return order, exception.NewNotFoundError(fmt.Sprintf("order id %s not found in customer id %s", orderId, customerId))
+	}
+	return order, nil
 }
 
-func (service *CustomerServiceImpl) Update(ctx context.Context, request web.CustomerUpdateRequest) web.CustomerUpdateRequest {
+func (service *CustomerServiceImpl) orderDetailResponse(ctx context.Context, order schema.Order) (web.OrderDetailResponse, error) {
+	itemsResponse, err := orderItemResponses(ctx, service.ProductRepository, order.Items)
+	if err != nil {
+		return web.OrderDetailResponse{}, err
+	}
+	return web.OrderDetailResponse{
+		Id:            order.Id.Hex(),
+		CreatedAt:     order.CreatedAt,
+		UpdatedAt:     order.UpdatedAt,
+		TransactionId: order.TransactionId,
+		MerchantId:    order.MerchantId,
+		Status:        order.Status,
+		Items:         itemsResponse,
+		Subtotal:      order.Subtotal,
+		ShippingFee:   order.ShippingFee,
+		Discount:      order.Discount,
+		Total:         order.Total,
+		Address:       orderAddressResponse(order.Address),
+		Shipping:      orderShippingResponse(order.Shipping),
+		History:       orderHistoryResponses(order.History),
+	}, nil
+}
+
+func (service *CustomerServiceImpl) Update(ctx context.Context, request web.CustomerUpdateRequest) (web.CustomerUpdateRequest, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, request.Id)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
 
 	_, err = service.CustomerRepository.Update(ctx, schema.Customer{
 		Id:        customer.Id,
@@ -208,49 +333,54 @@ func (service *CustomerServiceImpl) Update(ctx context.Context, request web.Cust
 		UserName:  request.UserName,
 		Phone:     request.Phone,
 	})
-	helper.PanicIfError(err)
-	return request
+	if err != nil {
+		return request, err
+	}
+	return request, nil
 }
-func (service *CustomerServiceImpl) UpdateMainImage(ctx context.Context, request web.CustomerUpdateImageRequest) web.CustomerUpdateImageRequestResponse {
+
+func (service *CustomerServiceImpl) UpdateMainImage(ctx context.Context, request web.CustomerUpdateImageRequest) (web.CustomerUpdateImageRequestResponse, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, request.Id)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.CustomerUpdateImageRequestResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
-	url, err := service.CloudinaryRepository.UploadImage(ctx, request.MainImage.FileName, request.MainImage.URL)
-	helper.PanicIfError(err)
+	mainImage, err := uploadImage(ctx, service.ImageRepository, *request.MainImage)
+	if err != nil {
+		return web.CustomerUpdateImageRequestResponse{}, err
+	}
+	if customer.MainImage != nil {
+		mainImage.Id = customer.MainImage.Id
+	}
 
 	_, err = service.CustomerRepository.Update(ctx, schema.Customer{
 		Id:        customer.Id,
 		UpdatedAt: request.UpdatedAt,
-		MainImage: &schema.Image{
-			Id:       customer.MainImage.Id,
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
+		MainImage: &mainImage,
 	})
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.CustomerUpdateImageRequestResponse{}, err
+	}
 
 	if customer.MainImage != nil {
-		err = service.CloudinaryRepository.DeleteImage(ctx, customer.MainImage.FileName)
-		helper.PanicIfError(err)
+		err = deleteImage(ctx, service.ImageRepository, *customer.MainImage)
+		if err != nil {
+			return web.CustomerUpdateImageRequestResponse{}, err
+		}
 	}
 
-	request.MainImage.URL = url
 	return web.CustomerUpdateImageRequestResponse{
 		Id:        customer.Id.Hex(),
 		UpdatedAt: request.UpdatedAt,
-		MainImage: web.ImageResponse{
-			Id:       customer.MainImage.Id.Hex(),
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
-	}
-
+		MainImage: imageResponse(&mainImage),
+	}, nil
 }
 
-func (service *CustomerServiceImpl) Delete(ctx context.Context, customerId string) {
+func (service *CustomerServiceImpl) Delete(ctx context.Context, customerId string) error {
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
 
-	err = service.CustomerRepository.Delete(ctx, customer.Id.Hex())
-	helper.PanicIfError(err)
+	return service.CustomerRepository.Delete(ctx, customer.Id.Hex())
 }
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// uploadImage stores the image along with its variants, nothing is left behind when one of them fails
+func uploadImage(ctx context.Context, imageRepository repository.ImageRepository, request web.ImageCreateRequest) (schema.Image, error) {
+	url, err := imageRepository.UploadImage(ctx, request.FileName, request.URL)
+	if err != nil {
+		return schema.Image{}, err
+	}
+	image := schema.Image{
+		Id:       primitive.NewObjectID(),
+		Name:     request.Name,
+		FileName: request.FileName,
+		URL:      url,
+		Width:    request.Width,
+		Height:   request.Height,
+		BlurHash: request.BlurHash,
+	}
+	for _, variant := range request.Variants {
+		filename := request.FileName + "-" + variant.Name
+		url, err := imageRepository.UploadImage(ctx, filename, variant.URL)
+		if err != nil {
+			errDelete := deleteImage(ctx, imageRepository, image)
+			if errDelete != nil {
+				helper.GetLogger(ctx).Error("delete partly uploaded image", "file_name", image.FileName, "error", errDelete)
+			}
+			return schema.Image{}, err
+		}
+		image.Variants = append(image.Variants, schema.ImageVariant{
+			Name:     variant.Name,
+			FileName: filename,
+			URL:      url,
+			Width:    variant.Width,
+			Height:   variant.Height,
+		})
+	}
+	return image, nil
+}
+
+// deleteImage removes the image along with its variants
+func deleteImage(ctx context.Context, imageRepository repository.ImageRepository, image schema.Image) error {
+	for _, variant := range image.Variants {
+		err := imageRepository.DeleteImage(ctx, variant.FileName)
+		if err != nil {
+			return err
+		}
+	}
+	return imageRepository.DeleteImage(ctx, image.FileName)
+}
+
+func imageResponse(image *schema.Image) web.ImageResponse {
+	if image == nil {
+		return web.ImageResponse{}
+	}
+	var variants []web.ImageVariantResponse
+	for _, variant := range image.Variants {
+		variants = append(variants, web.ImageVariantResponse{
+			Name:   variant.Name,
+			URL:    variant.URL,
+			Width:  variant.Width,
+			Height: variant.Height,
+		})
+	}
+	return web.ImageResponse{
+		Id:       image.Id.Hex(),
+		Name:     image.Name,
+		FileName: image.FileName,
+		URL:      image.URL,
+		Width:    image.Width,
+		Height:   image.Height,
+		BlurHash: image.BlurHash,
+		Variants: variants,
+	}
+}
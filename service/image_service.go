@@ -0,0 +1,10 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+type ImageService interface {
+	Reconcile(ctx context.Context, request web.ImageReconcileRequest) (web.ImageReconcileResponse, error)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"weplant-backend/helper"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+type ImageServiceImpl struct {
+	ImageRepository          repository.ImageRepository
+	ImageReferenceRepository repository.ImageReferenceRepository
+}
+
+func NewImageService(imageRepository repository.ImageRepository, imageReferenceRepository repository.ImageReferenceRepository) ImageService {
+	return &ImageServiceImpl{
+		ImageRepository:          imageRepository,
+		ImageReferenceRepository: imageReferenceRepository,
+	}
+}
+
+var ErrNoImageReferences = errors.New("no document references an image, refusing to delete every stored image")
+
+// Reconcile cross-references the stored files with the images of every document. Stored files nobody references are
+// orphans, left behind when a request failed between the upload and the database write, they are deleted once older
+// than the grace period if asked to. Images whose file is gone are only reported.
+func (service *ImageServiceImpl) Reconcile(ctx context.Context, request web.ImageReconcileRequest) (web.ImageReconcileResponse, error) {
+	stored, err := service.ImageRepository.ListImages(ctx)
+	if err != nil {
+		return web.ImageReconcileResponse{}, err
+	}
+	references, err := service.ImageReferenceRepository.FindAll(ctx)
+	if err != nil {
+		return web.ImageReconcileResponse{}, err
+	}
+
+	storedFiles := map[string]bool{}
+	for _, image := range stored {
+		storedFiles[image.FileName] = true
+	}
+
+	response := web.ImageReconcileResponse{
+		StoredImages: len(stored),
+	}
+	referencedFiles := map[string]bool{}
+	for _, reference := range references {
+		files := []string{reference.Image.FileName}
+		for _, variant := range reference.Image.Variants {
+			files = append(files, variant.FileName)
+		}
+		for _, file := range files {
+			if file == "" || referencedFiles[file] {
+				continue
+			}
+			referencedFiles[file] = true
+			if !storedFiles[file] {
+				response.MissingImages = append(response.MissingImages, web.MissingImageResponse{
+					Collection: reference.Collection,
+					DocumentId: reference.DocumentId,
+					FileName:   file,
+					URL:        reference.Image.URL,
+				})
+			}
+		}
+	}
+	response.ReferencedImages = len(referencedFiles)
+
+	if request.DeleteOrphans && len(referencedFiles) == 0 && len(stored) > 0 {
+		return web.ImageReconcileResponse{}, ErrNoImageReferences
+	}
+
+	sort.Slice(stored, func(i, j int) bool {
+		return stored[i].FileName < stored[j].FileName
+	})
+	deleteBefore := helper.GetTimeNow() - request.GracePeriod
+	for _, image := range stored {
+		if referencedFiles[image.FileName] {
+			continue
+		}
+		orphan := web.OrphanImageResponse{
+			FileName:  image.FileName,
+			CreatedAt: int(image.CreatedAt.Unix()),
+		}
+		if request.DeleteOrphans && orphan.CreatedAt <= deleteBefore {
+			err = service.ImageRepository.DeleteImage(ctx, image.FileName)
+			if err != nil {
+				return response, err
+			}
+			orphan.Deleted = true
+			response.DeletedOrphans++
+		}
+		response.Orphans = append(response.Orphans, orphan)
+	}
+	return response, nil
+}
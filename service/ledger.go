@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// CommissionPercent is what the platform keeps from every completed order
+const CommissionPercent = 5
+
+// recordLedger appends the entries and moves the merchant balance by their sum, it must run inside a transaction
+func recordLedger(ctx context.Context, ledgerRepository repository.LedgerRepository, merchantRepository repository.MerchantRepository, merchantId string, entries ...schema.LedgerEntry) error {
+	var total int64
+	for _, entry := range entries {
+		entry.MerchantId = merchantId
+		_, err := ledgerRepository.Create(ctx, entry)
+		if err != nil {
+			return err
+		}
+		total += entry.Amount
+	}
+
+	return merchantRepository.UpdateBalance(ctx, schema.Merchant{
+		Id:      helper.ObjectIDFromHex(merchantId),
+		Balance: total,
+	})
+}
+
+// orderEarning returns what the order pays the merchant and the commission taken from it,
+// shipping is not commissioned and neither is the discount of a merchant voucher
+func orderEarning(order schema.Order) (int64, int64) {
+	total := int64(order.Total)
+	commission := int64(order.Subtotal-order.Discount) * CommissionPercent / 100
+	return total, commission
+}
+
+// orderWasCompleted tells whether the order has already been credited to the merchant
+func orderWasCompleted(order schema.Order) bool {
+	for _, v := range order.History {
+		if v.Status == schema.OrderStatusCompleted {
+			return true
+		}
+	}
+	return false
+}
+
+func ledgerEntryResponses(entries []schema.LedgerEntry) []web.LedgerEntryResponse {
+	var entryResponses []web.LedgerEntryResponse
+	for _, v := range entries {
+		entryResponses = append(entryResponses, web.LedgerEntryResponse{
+			Id:          v.Id.Hex(),
+			CreatedAt:   v.CreatedAt,
+			Type:        v.Type,
+			Amount:      v.Amount,
+			ReferenceId: v.ReferenceId,
+			Note:        v.Note,
+		})
+	}
+	return entryResponses
+}
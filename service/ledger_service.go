@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+type LedgerService interface {
+	FindByMerchantId(ctx context.Context, merchantId string, cursor string, perPage int) (web.LedgerResponse, error)
+	Reconcile(ctx context.Context) (int, error)
+}
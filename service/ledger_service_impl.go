@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/exception"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+type LedgerServiceImpl struct {
+	LedgerRepository   repository.LedgerRepository
+	MerchantRepository repository.MerchantRepository
+	SessionRepository  repository.SessionRepository
+}
+
+func NewLedgerService(ledgerRepository repository.LedgerRepository, merchantRepository repository.MerchantRepository, sessionRepository repository.SessionRepository) LedgerService {
+	return &LedgerServiceImpl{
+		LedgerRepository:   ledgerRepository,
+		MerchantRepository: merchantRepository,
+		SessionRepository:  sessionRepository,
+	}
+}
+
+func (service *LedgerServiceImpl) FindByMerchantId(ctx context.Context, merchantId string, cursor string, perPage int) (web.LedgerResponse, error) {
+	merchant, err := service.MerchantRepository.FindById(ctx, merchantId)
+	if err != nil {
+		return web.LedgerResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	entries, pageInfo, err := service.LedgerRepository.FindByMerchantId(ctx, merchantId, repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.LedgerResponse{}, pageError(err)
+	}
+	itemCount, err := service.LedgerRepository.CountByMerchantId(ctx, merchantId)
+	if err != nil {
+		return web.LedgerResponse{}, err
+	}
+
+	return web.LedgerResponse{
+		MerchantId: merchant.Id.Hex(),
+		Balance:    merchant.Balance,
+		Entries:    ledgerEntryResponses(entries),
+		Metadata:   cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+// Reconcile makes every merchant balance equal to the sum of its ledger. Balances written before the ledger existed
+// are kept as an opening balance entry, any other difference is overwritten by the ledger.
+func (service *LedgerServiceImpl) Reconcile(ctx context.Context) (int, error) {
+	merchants, err := service.MerchantRepository.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var fixed int
+	for _, v := range merchants {
+		merchantId := v.Id.Hex()
+		// the transaction may run the function again when it is retried, only the run that commits counts
+		var changed bool
+		err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+			changed = false
+			// read again inside the transaction so the balance and the ledger come from the same snapshot
+			merchant, err := service.MerchantRepository.FindById(ctx, merchantId)
+			if err != nil {
+				return err
+			}
+			count, err := service.LedgerRepository.CountByMerchantId(ctx, merchantId)
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				if merchant.Balance == 0 {
+					return nil
+				}
+				_, err = service.LedgerRepository.Create(ctx, schema.LedgerEntry{
+					CreatedAt:   helper.GetTimeNow(),
+					MerchantId:  merchantId,
+					Type:        schema.LedgerTypeOpeningBalance,
+					Amount:      merchant.Balance,
+					ReferenceId: merchantId,
+				})
+				if err != nil {
+					return err
+				}
+				changed = true
+				return nil
+			}
+
+			sum, err := service.LedgerRepository.SumByMerchantId(ctx, merchantId)
+			if err != nil {
+				return err
+			}
+			if sum == merchant.Balance {
+				return nil
+			}
+			helper.GetLogger(ctx).Info("merchant balance does not match its ledger", "merchant_id", merchantId, "balance", merchant.Balance, "ledger", sum)
+			err = service.MerchantRepository.SetBalance(ctx, merchantId, sum)
+			if err != nil {
+				return err
+			}
+			changed = true
+			return nil
+		})
+		if err != nil {
+			return fixed, err
+		}
+		if changed {
+			fixed++
+		}
+	}
+	return fixed, nil
+}
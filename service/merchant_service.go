@@ -6,10 +6,12 @@ import (
 )
 
 type MerchantService interface {
-	Create(ctx context.Context, request web.MerchantCreateRequest) web.TokenResponse
-	FindById(ctx context.Context, merchantId string) web.MerchantDetailResponse
-	FindManageOrderById(ctx context.Context, merchantId string) web.ManageOrderResponse
-	Update(ctx context.Context, request web.MerchantUpdateRequest) web.MerchantUpdateRequest
-	UpdateMainImage(ctx context.Context, request web.MerchantUpdateImageRequest) web.MerchantUpdateImageRequestResponse
-	Delete(ctx context.Context, merchantId string)
+	Create(ctx context.Context, request web.MerchantCreateRequest) (web.TokenResponse, error)
+	FindById(ctx context.Context, merchantId string, cursor string, perPage int) (web.MerchantDetailResponse, error)
+	FindManageOrderById(ctx context.Context, merchantId string, cursor string, perPage int) (web.ManageOrderResponse, error)
+	UpdateOrderStatus(ctx context.Context, request web.ManageOrderUpdateStatusRequest) (web.ManageOrderDetailResponse, error)
+	UpdateOrderShipping(ctx context.Context, request web.ManageOrderUpdateShippingRequest) (web.ManageOrderDetailResponse, error)
+	Update(ctx context.Context, request web.MerchantUpdateRequest) (web.MerchantUpdateRequest, error)
+	UpdateMainImage(ctx context.Context, request web.MerchantUpdateImageRequest) (web.MerchantUpdateImageRequestResponse, error)
+	Delete(ctx context.Context, merchantId string) error
 }
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/exception"
 	"weplant-backend/helper"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
@@ -11,22 +13,32 @@ import (
 )
 
 type MerchantServiceImpl struct {
-	MerchantRepository   repository.MerchantRepository
-	CloudinaryRepository repository.CloudinaryRepository
-	ProductRepository    repository.ProductRepository
+	MerchantRepository     repository.MerchantRepository
+	ImageRepository        repository.ImageRepository
+	ProductRepository      repository.ProductRepository
+	RefreshTokenRepository repository.RefreshTokenRepository
+	OrderRepository        repository.OrderRepository
+	SessionRepository      repository.SessionRepository
+	LedgerRepository       repository.LedgerRepository
 }
 
-func NewMerchantService(merchantRepository repository.MerchantRepository, cloudinaryRepository repository.CloudinaryRepository, productRepository repository.ProductRepository) MerchantService {
+func NewMerchantService(merchantRepository repository.MerchantRepository, imageRepository repository.ImageRepository, productRepository repository.ProductRepository, refreshTokenRepository repository.RefreshTokenRepository, orderRepository repository.OrderRepository, sessionRepository repository.SessionRepository, ledgerRepository repository.LedgerRepository) MerchantService {
 	return &MerchantServiceImpl{
-		MerchantRepository:   merchantRepository,
-		CloudinaryRepository: cloudinaryRepository,
-		ProductRepository:    productRepository,
+		MerchantRepository:     merchantRepository,
+		ImageRepository:        imageRepository,
+		ProductRepository:      productRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+		OrderRepository:        orderRepository,
+		SessionRepository:      sessionRepository,
+		LedgerRepository:       ledgerRepository,
 	}
 }
 
-func (service *MerchantServiceImpl) Create(ctx context.Context, request web.MerchantCreateRequest) web.TokenResponse {
-	url, err := service.CloudinaryRepository.UploadImage(ctx, request.MainImage.FileName, request.MainImage.URL)
-	helper.PanicIfError(err)
+func (service *MerchantServiceImpl) Create(ctx context.Context, request web.MerchantCreateRequest) (web.TokenResponse, error) {
+	mainImage, err := uploadImage(ctx, service.ImageRepository, *request.MainImage)
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
 
 	res, err := service.MerchantRepository.Create(ctx, schema.Merchant{
 		CreatedAt: request.CreatedAt,
@@ -36,11 +48,7 @@ func (service *MerchantServiceImpl) Create(ctx context.Context, request web.Merc
 		Name:      request.Name,
 		Slug:      request.Slug,
 		Phone:     request.Phone,
-		MainImage: &schema.Image{
-			Id:       primitive.NewObjectID(),
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
+		MainImage: &mainImage,
 		Address: &schema.Address{
 			Address:    request.Address.Address,
 			City:       request.Address.City,
@@ -49,28 +57,31 @@ func (service *MerchantServiceImpl) Create(ctx context.Context, request web.Merc
 		},
 	})
 	if err != nil {
-		errUpload := service.CloudinaryRepository.DeleteImage(ctx, request.MainImage.FileName)
-		helper.PanicIfError(errUpload)
-		panic(err.Error())
+		errUpload := deleteImage(ctx, service.ImageRepository, mainImage)
+		if errUpload != nil {
+			return web.TokenResponse{}, errUpload
+		}
+		return web.TokenResponse{}, helper.WrapDuplicateKeyError(err, "email or slug is already registered")
 	}
 
-	token := pkg.GenerateToken(web.JWTPayload{
-		Id:   res.Id.Hex(),
-		Role: "merchant",
-	})
-	return web.TokenResponse{
-		Id:    res.Id.Hex(),
-		Role:  "merchant",
-		Token: token,
-	}
+	return generateTokenResponse(ctx, service.RefreshTokenRepository, res.Id.Hex(), "merchant", primitive.NewObjectID().Hex())
 }
 
-func (service *MerchantServiceImpl) FindById(ctx context.Context, merchantId string) web.MerchantDetailResponse {
+func (service *MerchantServiceImpl) FindById(ctx context.Context, merchantId string, cursor string, perPage int) (web.MerchantDetailResponse, error) {
 	merchant, err := service.MerchantRepository.FindById(ctx, merchantId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.MerchantDetailResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
-	products, err := service.ProductRepository.FindByMerchantId(ctx, merchant.Id.Hex())
-	helper.PanicIfError(err)
+	products, pageInfo, err := service.ProductRepository.FindPageByMerchantId(ctx, merchant.Id.Hex(), repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.MerchantDetailResponse{}, pageError(err)
+	}
+
+	itemCount, err := service.ProductRepository.CountByMerchantId(ctx, merchant.Id.Hex())
+	if err != nil {
+		return web.MerchantDetailResponse{}, err
+	}
 
 	var productsResponse []web.ProductSimpleResponse
 	for _, p := range products {
@@ -82,11 +93,7 @@ func (service *MerchantServiceImpl) FindById(ctx context.Context, merchantId str
 			Description: p.Description,
 			Price:       p.Price,
 			Stock:       p.Stock,
-			MainImage: web.ImageResponse{
-				Id:       p.MainImage.Id.Hex(),
-				FileName: p.MainImage.FileName,
-				URL:      p.MainImage.URL,
-			},
+			MainImage:   imageResponse(p.MainImage),
 		})
 	}
 
@@ -99,11 +106,8 @@ func (service *MerchantServiceImpl) FindById(ctx context.Context, merchantId str
 		Slug:      merchant.Slug,
 		Phone:     merchant.Phone,
 		Balance:   merchant.Balance,
-		MainImage: web.ImageResponse{
-			Id:       merchant.MainImage.Id.Hex(),
-			FileName: merchant.MainImage.FileName,
-			URL:      merchant.MainImage.URL,
-		},
+		Rating:    ratingResponse(merchant.RatingCount, merchant.RatingTotal),
+		MainImage: imageResponse(merchant.MainImage),
 		Address: web.AddressResponse{
 			Address:    merchant.Address.Address,
 			City:       merchant.Address.City,
@@ -111,52 +115,156 @@ func (service *MerchantServiceImpl) FindById(ctx context.Context, merchantId str
 			PostalCode: merchant.Address.PostalCode,
 		},
 		Products: productsResponse,
-	}
+		Metadata: cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
 }
 
-func (service *MerchantServiceImpl) FindManageOrderById(ctx context.Context, merchantId string) web.ManageOrderResponse {
+func (service *MerchantServiceImpl) FindManageOrderById(ctx context.Context, merchantId string, cursor string, perPage int) (web.ManageOrderResponse, error) {
 	merchant, err := service.MerchantRepository.FindById(ctx, merchantId)
-	helper.PanicIfErrorNotFound(err)
-
-	var productsResponse []web.ManageOrderProductResponse
-	for _, v := range merchant.Orders {
-		product, err := service.ProductRepository.FindById(ctx, v.ProductId)
-		helper.PanicIfError(err)
-
-		productsResponse = append(productsResponse, web.ManageOrderProductResponse{
-			Id:          v.Id.Hex(),
-			CreatedAt:   v.CreatedAt,
-			UpdatedAt:   v.UpdatedAt,
-			ProductId:   product.Id.Hex(),
-			Name:        product.Name,
-			Slug:        product.Slug,
-			Description: product.Description,
-			Price:       v.Price,
-			Quantity:    v.Quantity,
-			TotalPrice:  v.Price * v.Quantity,
-			MainImage: web.ImageResponse{
-				Id:       product.MainImage.Id.Hex(),
-				FileName: product.MainImage.FileName,
-				URL:      product.MainImage.URL,
-			},
-			Address: web.AddressResponse{
-				Address:    v.Address.Address,
-				City:       v.Address.City,
-				Province:   v.Address.Province,
-				PostalCode: v.Address.PostalCode,
-			},
-		})
+	if err != nil {
+		return web.ManageOrderResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	orders, pageInfo, err := service.OrderRepository.FindByMerchantId(ctx, merchant.Id.Hex(), repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.ManageOrderResponse{}, pageError(err)
+	}
+
+	itemCount, err := service.OrderRepository.CountByMerchantId(ctx, merchant.Id.Hex())
+	if err != nil {
+		return web.ManageOrderResponse{}, err
+	}
+
+	var ordersResponse []web.ManageOrderDetailResponse
+	for _, v := range orders {
+		orderResponse, err := service.manageOrderDetailResponse(ctx, v)
+		if err != nil {
+			return web.ManageOrderResponse{}, err
+		}
+		ordersResponse = append(ordersResponse, orderResponse)
 	}
 
 	return web.ManageOrderResponse{
 		MerchantId: merchant.Id.Hex(),
-		Products:   productsResponse,
+		Orders:     ordersResponse,
+		Metadata:   cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+func (service *MerchantServiceImpl) UpdateOrderStatus(ctx context.Context, request web.ManageOrderUpdateStatusRequest) (web.ManageOrderDetailResponse, error) {
+	order, err := service.findOrder(ctx, request.MerchantId, request.Id)
+	if err != nil {
+		return web.ManageOrderDetailResponse{}, err
 	}
+
+	history := schema.OrderHistory{
+		CreatedAt: request.UpdatedAt,
+		Status:    request.Status,
+		Note:      request.Note,
+	}
+
+	if request.Status != schema.OrderStatusCancelled {
+		order, err = moveOrder(ctx, service.OrderRepository, order, history, nil)
+	} else {
+		// a cancelled order gives the stock back, when the merchant was already credited the earning is taken back
+		err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+			cancelled, err := moveOrder(ctx, service.OrderRepository, order, history, nil)
+			if err != nil {
+				return err
+			}
+			for _, v := range order.Items {
+				err = updateStock(ctx, service.ProductRepository, v.ProductId, v.VariantId, v.Quantity)
+				if err != nil {
+					return err
+				}
+			}
+			if orderWasCompleted(order) {
+				total, commission := orderEarning(order)
+				err = recordLedger(ctx, service.LedgerRepository, service.MerchantRepository, order.MerchantId, schema.LedgerEntry{
+					CreatedAt:   request.UpdatedAt,
+					Type:        schema.LedgerTypeRefund,
+					Amount:      -(total - commission),
+					ReferenceId: order.Id.Hex(),
+					Note:        request.Note,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			order = cancelled
+			return nil
+		})
+	}
+	if err != nil {
+		return web.ManageOrderDetailResponse{}, err
+	}
+
+	return service.manageOrderDetailResponse(ctx, order)
 }
 
-func (service *MerchantServiceImpl) Update(ctx context.Context, request web.MerchantUpdateRequest) web.MerchantUpdateRequest {
+func (service *MerchantServiceImpl) UpdateOrderShipping(ctx context.Context, request web.ManageOrderUpdateShippingRequest) (web.ManageOrderDetailResponse, error) {
+	order, err := service.findOrder(ctx, request.MerchantId, request.Id)
+	if err != nil {
+		return web.ManageOrderDetailResponse{}, err
+	}
+
+	// the courier service the customer paid for is kept
+	shipping := &schema.OrderShipping{
+		Courier:        request.Courier,
+		TrackingNumber: request.TrackingNumber,
+	}
+	if order.Shipping != nil {
+		shipping.Service = order.Shipping.Service
+	}
+
+	order, err = moveOrder(ctx, service.OrderRepository, order, schema.OrderHistory{
+		CreatedAt: request.UpdatedAt,
+		Status:    schema.OrderStatusShipped,
+	}, shipping)
+	if err != nil {
+		return web.ManageOrderDetailResponse{}, err
+	}
+
+	return service.manageOrderDetailResponse(ctx, order)
+}
+
+// findOrder hides orders of other merchants behind a not found error
+func (service *MerchantServiceImpl) findOrder(ctx context.Context, merchantId string, orderId string) (schema.Order, error) {
+	order, err := service.OrderRepository.FindById(ctx, orderId)
+	if err != nil || order.MerchantId != merchantId {
+		return order, exception.NewNotFoundError(fmt.Sprintf("order id %s not found in merchant id %s", orderId, merchantId))
+	}
+	return order, nil
+}
+
+func (service *MerchantServiceImpl) manageOrderDetailResponse(ctx context.Context, order schema.Order) (web.ManageOrderDetailResponse, error) {
+	itemsResponse, err := orderItemResponses(ctx, service.ProductRepository, order.Items)
+	if err != nil {
+		return web.ManageOrderDetailResponse{}, err
+	}
+	return web.ManageOrderDetailResponse{
+		Id:            order.Id.Hex(),
+		CreatedAt:     order.CreatedAt,
+		UpdatedAt:     order.UpdatedAt,
+		TransactionId: order.TransactionId,
+		CustomerId:    order.CustomerId,
+		Status:        order.Status,
+		Items:         itemsResponse,
+		Subtotal:      order.Subtotal,
+		ShippingFee:   order.ShippingFee,
+		Discount:      order.Discount,
+		Total:         order.Total,
+		Address:       orderAddressResponse(order.Address),
+		Shipping:      orderShippingResponse(order.Shipping),
+		History:       orderHistoryResponses(order.History),
+	}, nil
+}
+
+func (service *MerchantServiceImpl) Update(ctx context.Context, request web.MerchantUpdateRequest) (web.MerchantUpdateRequest, error) {
 	merchant, err := service.MerchantRepository.FindById(ctx, request.Id)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
 
 	_, err = service.MerchantRepository.Update(ctx, schema.Merchant{
 		Id:        merchant.Id,
@@ -171,57 +279,65 @@ func (service *MerchantServiceImpl) Update(ctx context.Context, request web.Merc
 			PostalCode: request.Address.PostalCode,
 		},
 	})
-	helper.PanicIfError(err)
-	return request
+	if err != nil {
+		return request, err
+	}
+	return request, nil
 }
 
-func (service *MerchantServiceImpl) UpdateMainImage(ctx context.Context, request web.MerchantUpdateImageRequest) web.MerchantUpdateImageRequestResponse {
+func (service *MerchantServiceImpl) UpdateMainImage(ctx context.Context, request web.MerchantUpdateImageRequest) (web.MerchantUpdateImageRequestResponse, error) {
 	merchant, err := service.MerchantRepository.FindById(ctx, request.Id)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.MerchantUpdateImageRequestResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
-	url, err := service.CloudinaryRepository.UploadImage(ctx, request.MainImage.FileName, request.MainImage.URL)
-	helper.PanicIfError(err)
+	mainImage, err := uploadImage(ctx, service.ImageRepository, *request.MainImage)
+	if err != nil {
+		return web.MerchantUpdateImageRequestResponse{}, err
+	}
+	mainImage.Id = merchant.MainImage.Id
 
 	_, err = service.MerchantRepository.Update(ctx, schema.Merchant{
 		Id:        merchant.Id,
 		UpdatedAt: request.UpdatedAt,
 		Slug:      merchant.Slug,
-		MainImage: &schema.Image{
-			Id:       merchant.MainImage.Id,
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
+		MainImage: &mainImage,
 	})
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.MerchantUpdateImageRequestResponse{}, err
+	}
 
-	err = service.CloudinaryRepository.DeleteImage(ctx, merchant.MainImage.FileName)
-	helper.PanicIfError(err)
+	err = deleteImage(ctx, service.ImageRepository, *merchant.MainImage)
+	if err != nil {
+		return web.MerchantUpdateImageRequestResponse{}, err
+	}
 
 	return web.MerchantUpdateImageRequestResponse{
 		Id:        merchant.Id.Hex(),
 		UpdatedAt: request.UpdatedAt,
-		MainImage: web.ImageResponse{
-			Id:       merchant.Id.Hex(),
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
-	}
+		MainImage: imageResponse(&mainImage),
+	}, nil
 }
 
-func (service *MerchantServiceImpl) Delete(ctx context.Context, merchantId string) {
+func (service *MerchantServiceImpl) Delete(ctx context.Context, merchantId string) error {
 	merchant, err := service.MerchantRepository.FindById(ctx, merchantId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
 
 	products, err := service.ProductRepository.FindByMerchantId(ctx, merchant.Id.Hex())
-	helper.PanicIfError(err)
+	if err != nil {
+		return err
+	}
 
 	if len(products) > 0 {
-		panic("tidak dapat menghapus toko ini karena didalamnya masih terdapat produk")
+		return exception.NewConflictError("tidak dapat menghapus toko ini karena didalamnya masih terdapat produk")
 	}
 
 	err = service.MerchantRepository.Delete(ctx, merchant.Id.Hex())
-	helper.PanicIfError(err)
+	if err != nil {
+		return err
+	}
 
-	err = service.CloudinaryRepository.DeleteImage(ctx, merchant.MainImage.FileName)
-	helper.PanicIfError(err)
+	return deleteImage(ctx, service.ImageRepository, *merchant.MainImage)
 }
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+)
+
+// splitTransactionOrders groups the products by merchant, keeping the order the merchants first appear in, the shipping is added once it is quoted
+func splitTransactionOrders(products []schema.TransactionProduct) []schema.TransactionOrder {
+	var orders []schema.TransactionOrder
+	index := map[string]int{}
+	for _, p := range products {
+		i, ok := index[p.MerchantId]
+		if !ok {
+			i = len(orders)
+			index[p.MerchantId] = i
+			orders = append(orders, schema.TransactionOrder{
+				MerchantId: p.MerchantId,
+			})
+		}
+		orders[i].Subtotal += p.Price * p.Quantity
+	}
+	for i := range orders {
+		orders[i].Total = orders[i].Subtotal
+	}
+	return orders
+}
+
+// orderItems returns the products of the transaction sold by the merchant
+func orderItems(products []schema.TransactionProduct, merchantId string) []schema.OrderItem {
+	var items []schema.OrderItem
+	for _, p := range products {
+		if p.MerchantId == merchantId {
+			items = append(items, schema.OrderItem{
+				ProductId:   p.ProductId,
+				VariantId:   p.VariantId,
+				VariantName: p.VariantName,
+				Price:       p.Price,
+				Quantity:    p.Quantity,
+			})
+		}
+	}
+	return items
+}
+
+func transactionOrderResponses(orders []schema.TransactionOrder) []web.TransactionOrderResponse {
+	var ordersResponse []web.TransactionOrderResponse
+	for _, v := range orders {
+		ordersResponse = append(ordersResponse, web.TransactionOrderResponse{
+			MerchantId:  v.MerchantId,
+			Courier:     v.Courier,
+			Service:     v.Service,
+			Subtotal:    v.Subtotal,
+			ShippingFee: v.ShippingFee,
+			Discount:    v.Discount,
+			Total:       v.Total,
+		})
+	}
+	return ordersResponse
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"weplant-backend/exception"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// orderTransitions lists the statuses an order can move to from each status
+var orderTransitions = map[string][]string{
+	schema.OrderStatusPaid:            {schema.OrderStatusProcessing, schema.OrderStatusCancelled},
+	schema.OrderStatusProcessing:      {schema.OrderStatusShipped, schema.OrderStatusCancelled},
+	schema.OrderStatusShipped:         {schema.OrderStatusDelivered, schema.OrderStatusCompleted},
+	schema.OrderStatusDelivered:       {schema.OrderStatusCompleted, schema.OrderStatusRefundRequested},
+	schema.OrderStatusCompleted:       {schema.OrderStatusRefundRequested},
+	schema.OrderStatusRefundRequested: {schema.OrderStatusCancelled, schema.OrderStatusDelivered},
+}
+
+func canMoveOrder(from string, to string) bool {
+	for _, status := range orderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// moveOrder moves the order to history.Status and returns the order as it is stored afterwards
+func moveOrder(ctx context.Context, orderRepository repository.OrderRepository, order schema.Order, history schema.OrderHistory, shipping *schema.OrderShipping) (schema.Order, error) {
+	if !canMoveOrder(order.Status, history.Status) {
+		return order, exception.NewConflictError(fmt.Sprintf("order %s can not move from %s to %s", order.Id.Hex(), order.Status, history.Status))
+	}
+
+	err := orderRepository.UpdateStatus(ctx, order.Id.Hex(), order.Status, history, shipping)
+	if errors.Is(err, repository.ErrOrderStatusChanged) {
+		return order, exception.NewConflictError(fmt.Sprintf("order %s was updated by another request, please try again", order.Id.Hex()))
+	} else if err != nil {
+		return order, err
+	}
+
+	order.Status = history.Status
+	order.UpdatedAt = history.CreatedAt
+	order.History = append(order.History, history)
+	if shipping != nil {
+		order.Shipping = shipping
+	}
+	return order, nil
+}
+
+func orderShippingResponse(shipping *schema.OrderShipping) web.OrderShippingResponse {
+	if shipping == nil {
+		return web.OrderShippingResponse{}
+	}
+	return web.OrderShippingResponse{
+		Courier:        shipping.Courier,
+		Service:        shipping.Service,
+		TrackingNumber: shipping.TrackingNumber,
+	}
+}
+
+func orderHistoryResponses(history []schema.OrderHistory) []web.OrderHistoryResponse {
+	var historyResponses []web.OrderHistoryResponse
+	for _, v := range history {
+		historyResponses = append(historyResponses, web.OrderHistoryResponse{
+			CreatedAt: v.CreatedAt,
+			Status:    v.Status,
+			Note:      v.Note,
+		})
+	}
+	return historyResponses
+}
+
+// orderItemResponses describes the items with their current product, items of deleted products only keep what the order stored
+func orderItemResponses(ctx context.Context, productRepository repository.ProductRepository, items []schema.OrderItem) ([]web.OrderItemResponse, error) {
+	var itemsResponse []web.OrderItemResponse
+	for _, v := range items {
+		itemResponse := web.OrderItemResponse{
+			ProductId:   v.ProductId,
+			VariantId:   v.VariantId,
+			VariantName: v.VariantName,
+			Price:       v.Price,
+			Quantity:    v.Quantity,
+		}
+		product, err := productRepository.FindById(ctx, v.ProductId)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		if err == nil {
+			itemResponse.Name = product.Name
+			itemResponse.Slug = product.Slug
+			itemResponse.Description = product.Description
+			itemResponse.MainImage = imageResponse(product.MainImage)
+		}
+		itemsResponse = append(itemsResponse, itemResponse)
+	}
+	return itemsResponse, nil
+}
+
+func orderAddressResponse(address *schema.Address) web.AddressResponse {
+	if address == nil {
+		return web.AddressResponse{}
+	}
+	return web.AddressResponse{
+		Address:    address.Address,
+		City:       address.City,
+		Province:   address.Province,
+		PostalCode: address.PostalCode,
+	}
+}
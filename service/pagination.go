@@ -0,0 +1,28 @@
+package service
+
+import (
+	"errors"
+	"weplant-backend/exception"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+func cursorMetadata(perPage int, totalData int, pageInfo repository.PageInfo) web.MetadataPaginationResponse {
+	return web.MetadataPaginationResponse{
+		PerPage:    perPage,
+		TotalData:  totalData,
+		NextCursor: pageInfo.NextCursor,
+		PrevCursor: pageInfo.PrevCursor,
+	}
+}
+
+// pageError answers a cursor the client made up with a validation error
+func pageError(err error) error {
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return exception.NewValidationError("cursor is invalid", web.FieldErrorResponse{
+			Field:   "cursor",
+			Message: "must be a cursor from a previous page",
+		})
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+)
+
+// virtualAccountBanks maps the bank transfer methods to their midtrans bank
+var virtualAccountBanks = map[string]midtrans.Bank{
+	schema.PaymentMethodBcaVa:     midtrans.BankBca,
+	schema.PaymentMethodBniVa:     midtrans.BankBni,
+	schema.PaymentMethodBriVa:     midtrans.BankBri,
+	schema.PaymentMethodPermataVa: midtrans.BankPermata,
+}
+
+// setChargePayment fills the payment type and the method details of the charge request
+func setChargePayment(charge *coreapi.ChargeReq, request web.TransactionPaymentRequest) {
+	if bank, ok := virtualAccountBanks[request.Method]; ok {
+		charge.PaymentType = coreapi.PaymentTypeBankTransfer
+		charge.BankTransfer = &coreapi.BankTransferDetails{
+			Bank: bank,
+		}
+		return
+	}
+
+	switch request.Method {
+	case schema.PaymentMethodGopay:
+		charge.PaymentType = coreapi.PaymentTypeGopay
+		charge.Gopay = &coreapi.GopayDetails{
+			EnableCallback: request.CallbackURL != "",
+			CallbackUrl:    request.CallbackURL,
+		}
+	case schema.PaymentMethodShopeepay:
+		charge.PaymentType = coreapi.PaymentTypeShopeepay
+		charge.ShopeePay = &coreapi.ShopeePayDetails{
+			CallbackUrl: request.CallbackURL,
+		}
+	case schema.PaymentMethodCreditCard:
+		charge.PaymentType = coreapi.PaymentTypeCreditCard
+		charge.CreditCard = &coreapi.CreditCardDetails{
+			TokenID:        request.CardTokenId,
+			Authentication: true,
+		}
+	default:
+		charge.PaymentType = coreapi.PaymentTypeQris
+	}
+}
+
+// chargePayment picks the payment instructions of the method out of the midtrans response
+func chargePayment(method string, res *coreapi.ChargeResponse, expiredAt int) *schema.TransactionPayment {
+	payment := &schema.TransactionPayment{
+		Method:    method,
+		ExpiredAt: expiredAt,
+	}
+
+	if bank, ok := virtualAccountBanks[method]; ok {
+		payment.Bank = string(bank)
+		// permata answers with its own field, the other banks with va_numbers
+		payment.VANumber = res.PermataVaNumber
+		if len(res.VaNumbers) > 0 {
+			payment.VANumber = res.VaNumbers[0].VANumber
+		}
+		return payment
+	}
+
+	switch method {
+	case schema.PaymentMethodGopay, schema.PaymentMethodShopeepay:
+		payment.Deeplink = chargeAction(res, "deeplink-redirect")
+		payment.QRCode = chargeAction(res, "generate-qr-code")
+	case schema.PaymentMethodCreditCard:
+		payment.RedirectURL = res.RedirectURL
+	default:
+		payment.QRCode = chargeAction(res, "generate-qr-code")
+	}
+	return payment
+}
+
+func chargeAction(res *coreapi.ChargeResponse, name string) string {
+	for _, v := range res.Actions {
+		if v.Name == name {
+			return v.URL
+		}
+	}
+	return ""
+}
+
+func transactionPaymentResponse(payment *schema.TransactionPayment) web.TransactionPaymentResponse {
+	if payment == nil {
+		return web.TransactionPaymentResponse{}
+	}
+
+	response := web.TransactionPaymentResponse{
+		Method:    payment.Method,
+		ExpiredAt: payment.ExpiredAt,
+	}
+	if _, ok := virtualAccountBanks[payment.Method]; ok {
+		response.BankTransfer = &web.BankTransferPaymentResponse{
+			Bank:     payment.Bank,
+			VANumber: payment.VANumber,
+		}
+		return response
+	}
+
+	switch payment.Method {
+	case schema.PaymentMethodGopay, schema.PaymentMethodShopeepay:
+		response.EWallet = &web.EWalletPaymentResponse{
+			Deeplink: payment.Deeplink,
+			QRCode:   payment.QRCode,
+		}
+	case schema.PaymentMethodCreditCard:
+		response.Card = &web.CardPaymentResponse{
+			RedirectURL: payment.RedirectURL,
+		}
+	default:
+		response.Qris = &web.QrisPaymentResponse{
+			QRCode: payment.QRCode,
+		}
+	}
+	return response
+}
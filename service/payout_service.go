@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+type PayoutService interface {
+	Create(ctx context.Context, request web.PayoutCreateRequest) (web.PayoutResponse, error)
+	FindByMerchantId(ctx context.Context, merchantId string, cursor string, perPage int) (web.PayoutFindAllResponse, error)
+	UpdateStatus(ctx context.Context, request web.PayoutUpdateStatusRequest) (web.PayoutResponse, error)
+}
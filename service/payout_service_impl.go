@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"weplant-backend/exception"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// payoutTransitions lists the statuses a payout can move to from each status
+var payoutTransitions = map[string][]string{
+	schema.PayoutStatusRequested:  {schema.PayoutStatusProcessing, schema.PayoutStatusRejected},
+	schema.PayoutStatusProcessing: {schema.PayoutStatusPaid, schema.PayoutStatusRejected},
+}
+
+type PayoutServiceImpl struct {
+	PayoutRepository   repository.PayoutRepository
+	LedgerRepository   repository.LedgerRepository
+	MerchantRepository repository.MerchantRepository
+	SessionRepository  repository.SessionRepository
+}
+
+func NewPayoutService(payoutRepository repository.PayoutRepository, ledgerRepository repository.LedgerRepository, merchantRepository repository.MerchantRepository, sessionRepository repository.SessionRepository) PayoutService {
+	return &PayoutServiceImpl{
+		PayoutRepository:   payoutRepository,
+		LedgerRepository:   ledgerRepository,
+		MerchantRepository: merchantRepository,
+		SessionRepository:  sessionRepository,
+	}
+}
+
+// Create takes the amount from the balance right away, so the same money can not be requested twice
+func (service *PayoutServiceImpl) Create(ctx context.Context, request web.PayoutCreateRequest) (web.PayoutResponse, error) {
+	_, err := service.MerchantRepository.FindById(ctx, request.MerchantId)
+	if err != nil {
+		return web.PayoutResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	var payout schema.Payout
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		err := service.MerchantRepository.WithdrawBalance(ctx, request.MerchantId, request.Amount)
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return exception.NewValidationError("balance is not enough for this payout", web.FieldErrorResponse{
+				Field:   "amount",
+				Message: "amount is more than the balance",
+			})
+		} else if err != nil {
+			return err
+		}
+
+		payout, err = service.PayoutRepository.Create(ctx, schema.Payout{
+			CreatedAt:     request.CreatedAt,
+			UpdatedAt:     request.UpdatedAt,
+			MerchantId:    request.MerchantId,
+			Amount:        request.Amount,
+			Status:        schema.PayoutStatusRequested,
+			BankName:      request.BankName,
+			AccountNumber: request.AccountNumber,
+			AccountName:   request.AccountName,
+			History: []schema.PayoutHistory{
+				{
+					CreatedAt: request.CreatedAt,
+					Status:    schema.PayoutStatusRequested,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// the balance is already taken above, the entry only records it
+		_, err = service.LedgerRepository.Create(ctx, schema.LedgerEntry{
+			CreatedAt:   request.CreatedAt,
+			MerchantId:  request.MerchantId,
+			Type:        schema.LedgerTypeWithdrawal,
+			Amount:      -request.Amount,
+			ReferenceId: payout.Id.Hex(),
+		})
+		return err
+	})
+	if err != nil {
+		return web.PayoutResponse{}, err
+	}
+
+	return payoutResponse(payout), nil
+}
+
+func (service *PayoutServiceImpl) FindByMerchantId(ctx context.Context, merchantId string, cursor string, perPage int) (web.PayoutFindAllResponse, error) {
+	merchant, err := service.MerchantRepository.FindById(ctx, merchantId)
+	if err != nil {
+		return web.PayoutFindAllResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	payouts, pageInfo, err := service.PayoutRepository.FindByMerchantId(ctx, merchantId, repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.PayoutFindAllResponse{}, pageError(err)
+	}
+	itemCount, err := service.PayoutRepository.CountByMerchantId(ctx, merchantId)
+	if err != nil {
+		return web.PayoutFindAllResponse{}, err
+	}
+
+	var payoutsResponse []web.PayoutResponse
+	for _, v := range payouts {
+		payoutsResponse = append(payoutsResponse, payoutResponse(v))
+	}
+
+	return web.PayoutFindAllResponse{
+		MerchantId: merchant.Id.Hex(),
+		Payouts:    payoutsResponse,
+		Metadata:   cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+// UpdateStatus moves the payout along its flow, a rejected payout gives the amount back to the balance
+func (service *PayoutServiceImpl) UpdateStatus(ctx context.Context, request web.PayoutUpdateStatusRequest) (web.PayoutResponse, error) {
+	payout, err := service.PayoutRepository.FindById(ctx, request.Id)
+	if err != nil {
+		return web.PayoutResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	if !canMovePayout(payout.Status, request.Status) {
+		return web.PayoutResponse{}, exception.NewConflictError(fmt.Sprintf("payout %s can not move from %s to %s", request.Id, payout.Status, request.Status))
+	}
+
+	history := schema.PayoutHistory{
+		CreatedAt: request.UpdatedAt,
+		Status:    request.Status,
+		Note:      request.Note,
+	}
+
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		err := service.PayoutRepository.UpdateStatus(ctx, request.Id, payout.Status, history)
+		if errors.Is(err, repository.ErrPayoutStatusChanged) {
+			return exception.NewConflictError(fmt.Sprintf("payout %s was updated by another request, please try again", request.Id))
+		} else if err != nil {
+			return err
+		}
+
+		if request.Status != schema.PayoutStatusRejected {
+			return nil
+		}
+		return recordLedger(ctx, service.LedgerRepository, service.MerchantRepository, payout.MerchantId, schema.LedgerEntry{
+			CreatedAt:   request.UpdatedAt,
+			Type:        schema.LedgerTypeWithdrawalReversal,
+			Amount:      payout.Amount,
+			ReferenceId: payout.Id.Hex(),
+			Note:        request.Note,
+		})
+	})
+	if err != nil {
+		return web.PayoutResponse{}, err
+	}
+
+	payout.Status = history.Status
+	payout.UpdatedAt = history.CreatedAt
+	payout.History = append(payout.History, history)
+	return payoutResponse(payout), nil
+}
+
+func canMovePayout(from string, to string) bool {
+	for _, status := range payoutTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func payoutResponse(payout schema.Payout) web.PayoutResponse {
+	var historyResponses []web.PayoutHistoryResponse
+	for _, v := range payout.History {
+		historyResponses = append(historyResponses, web.PayoutHistoryResponse{
+			CreatedAt: v.CreatedAt,
+			Status:    v.Status,
+			Note:      v.Note,
+		})
+	}
+	return web.PayoutResponse{
+		Id:            payout.Id.Hex(),
+		CreatedAt:     payout.CreatedAt,
+		UpdatedAt:     payout.UpdatedAt,
+		MerchantId:    payout.MerchantId,
+		Amount:        payout.Amount,
+		Status:        payout.Status,
+		BankName:      payout.BankName,
+		AccountNumber: payout.AccountNumber,
+		AccountName:   payout.AccountName,
+		History:       historyResponses,
+	}
+}
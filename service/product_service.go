@@ -6,13 +6,15 @@ import (
 )
 
 type ProductService interface {
-	Create(ctx context.Context, request web.ProductCreateRequest) web.ProductCreateRequestResponse
-	FindById(ctx context.Context, productId string) web.ProductDetailResponse
-	FindAll(ctx context.Context, page int, perPage int) web.ProductFindAllResponse
-	FindAllWithSearch(ctx context.Context, search string, page int, perPage int) web.ProductFindAllResponse
-	Update(ctx context.Context, request web.ProductUpdateRequest) web.ProductUpdateRequest
-	UpdateMainImage(ctx context.Context, request web.ProductUpdateImageRequest) web.ProductUpdateImageRequestResponse
-	PushImageIntoImages(ctx context.Context, productId string, request []web.ImageCreateRequest) []web.ImageCreateRequest
-	PullImageFromImages(ctx context.Context, productId string, imageId string)
-	Delete(ctx context.Context, productId string)
+	Create(ctx context.Context, request web.ProductCreateRequest) (web.ProductCreateRequestResponse, error)
+	FindById(ctx context.Context, productId string) (web.ProductDetailResponse, error)
+	Search(ctx context.Context, request web.ProductSearchRequest) (web.ProductFindAllResponse, error)
+	Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductUpdateRequest, error)
+	UpdateMainImage(ctx context.Context, request web.ProductUpdateImageRequest) (web.ProductUpdateImageRequestResponse, error)
+	PushImageIntoImages(ctx context.Context, productId string, request []web.ImageCreateRequest) ([]web.ImageResponse, error)
+	PullImageFromImages(ctx context.Context, productId string, imageId string) error
+	Delete(ctx context.Context, productId string) error
+	CreateVariant(ctx context.Context, request web.ProductVariantCreateRequest) (web.ProductVariantResponse, error)
+	UpdateVariant(ctx context.Context, request web.ProductVariantUpdateRequest) (web.ProductVariantResponse, error)
+	DeleteVariant(ctx context.Context, productId string, variantId string) error
 }
@@ -2,46 +2,57 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"weplant-backend/exception"
 	"weplant-backend/helper"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
+	"weplant-backend/pkg"
 	"weplant-backend/repository"
 )
 
 type ProductServiceImpl struct {
-	ProductRepository    repository.ProductRepository
-	CloudinaryRepository repository.CloudinaryRepository
-	CategoryRepository   repository.CategoryRepository
-	MerchantRepository   repository.MerchantRepository
-	CustomerRepository   repository.CustomerRepository
+	ProductRepository  repository.ProductRepository
+	ImageRepository    repository.ImageRepository
+	CategoryRepository repository.CategoryRepository
+	MerchantRepository repository.MerchantRepository
+	CustomerRepository repository.CustomerRepository
 }
 
-func NewProductService(productRepository repository.ProductRepository, cloudinaryRepository repository.CloudinaryRepository, categoryRepository repository.CategoryRepository, merchantRepository repository.MerchantRepository, customerRepository repository.CustomerRepository) ProductService {
+func NewProductService(productRepository repository.ProductRepository, imageRepository repository.ImageRepository, categoryRepository repository.CategoryRepository, merchantRepository repository.MerchantRepository, customerRepository repository.CustomerRepository) ProductService {
 	return &ProductServiceImpl{
-		ProductRepository:    productRepository,
-		CloudinaryRepository: cloudinaryRepository,
-		CategoryRepository:   categoryRepository,
-		MerchantRepository:   merchantRepository,
-		CustomerRepository:   customerRepository,
+		ProductRepository:  productRepository,
+		ImageRepository:    imageRepository,
+		CategoryRepository: categoryRepository,
+		MerchantRepository: merchantRepository,
+		CustomerRepository: customerRepository,
 	}
 }
 
-func (service *ProductServiceImpl) Create(ctx context.Context, request web.ProductCreateRequest) web.ProductCreateRequestResponse {
+func (service *ProductServiceImpl) Create(ctx context.Context, request web.ProductCreateRequest) (web.ProductCreateRequestResponse, error) {
 	merchant, err := service.MerchantRepository.FindById(ctx, request.MerchantId)
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.ProductCreateRequestResponse{}, err
+	}
 
-	url, err := service.CloudinaryRepository.UploadImage(ctx, request.MainImage.FileName, request.MainImage.URL)
-	helper.PanicIfError(err)
+	mainImage, err := uploadImage(ctx, service.ImageRepository, *request.MainImage)
+	if err != nil {
+		return web.ProductCreateRequestResponse{}, err
+	}
 
 	var categoriesResponse []web.ProductCategoryCreateRequest
 
 	var categoriesCreateRequest []schema.ProductCategory
 	for _, category := range request.Categories {
 		c, err := service.CategoryRepository.FindById(ctx, category.CategoryId)
-		helper.PanicIfErrorNotFound(err)
+		if err != nil {
+			return web.ProductCreateRequestResponse{}, exception.NewNotFoundError(err.Error())
+		}
 		categoriesCreateRequest = append(categoriesCreateRequest, schema.ProductCategory{
 			CategoryId: c.Id.Hex(),
+			Name:       c.Name,
 		})
 		categoriesResponse = append(categoriesResponse, web.ProductCategoryCreateRequest{
 			CategoryId: c.Id.Hex(),
@@ -50,13 +61,11 @@ func (service *ProductServiceImpl) Create(ctx context.Context, request web.Produ
 
 	var imageCreateRequest []schema.Image
 	for _, image := range request.Images {
-		url, err := service.CloudinaryRepository.UploadImage(ctx, image.FileName, image.URL)
-		helper.PanicIfError(err)
-		imageCreateRequest = append(imageCreateRequest, schema.Image{
-			Id:       primitive.NewObjectID(),
-			FileName: image.FileName,
-			URL:      url,
-		})
+		img, err := uploadImage(ctx, service.ImageRepository, image)
+		if err != nil {
+			return web.ProductCreateRequestResponse{}, err
+		}
+		imageCreateRequest = append(imageCreateRequest, img)
 	}
 
 	res, err := service.ProductRepository.Create(ctx, schema.Product{
@@ -68,31 +77,31 @@ func (service *ProductServiceImpl) Create(ctx context.Context, request web.Produ
 		Description: request.Description,
 		Price:       request.Price,
 		Stock:       request.Stock,
-		MainImage: &schema.Image{
-			Id:       primitive.NewObjectID(),
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
-		Images:     imageCreateRequest,
-		Categories: categoriesCreateRequest,
+		Weight:      request.Weight,
+		Length:      request.Length,
+		Width:       request.Width,
+		Height:      request.Height,
+		MainImage:   &mainImage,
+		Images:      imageCreateRequest,
+		Categories:  categoriesCreateRequest,
 	})
 	if err != nil {
-		err := service.CloudinaryRepository.DeleteImage(ctx, request.MainImage.FileName)
-		helper.PanicIfError(err)
-		for _, image := range request.Images {
-			err := service.CloudinaryRepository.DeleteImage(ctx, image.FileName)
-			helper.PanicIfError(err)
+		errDelete := deleteImage(ctx, service.ImageRepository, mainImage)
+		if errDelete != nil {
+			return web.ProductCreateRequestResponse{}, errDelete
+		}
+		for _, image := range imageCreateRequest {
+			errDelete := deleteImage(ctx, service.ImageRepository, image)
+			if errDelete != nil {
+				return web.ProductCreateRequestResponse{}, errDelete
+			}
 		}
-		panic(err.Error())
+		return web.ProductCreateRequestResponse{}, helper.WrapDuplicateKeyError(err, "product "+request.Slug+" already exists")
 	}
 
 	var imagesResponse []web.ImageResponse
 	for _, image := range imageCreateRequest {
-		imagesResponse = append(imagesResponse, web.ImageResponse{
-			Id:       image.Id.Hex(),
-			FileName: image.FileName,
-			URL:      image.URL,
-		})
+		imagesResponse = append(imagesResponse, imageResponse(&image))
 	}
 
 	return web.ProductCreateRequestResponse{
@@ -105,36 +114,38 @@ func (service *ProductServiceImpl) Create(ctx context.Context, request web.Produ
 		Description: res.Description,
 		Price:       res.Price,
 		Stock:       res.Stock,
-		MainImage: web.ImageResponse{
-			Id:       res.MainImage.Id.Hex(),
-			FileName: res.MainImage.FileName,
-			URL:      res.MainImage.URL,
-		},
-		Images:     imagesResponse,
-		Categories: categoriesResponse,
-	}
+		Weight:      res.Weight,
+		Length:      res.Length,
+		Width:       res.Width,
+		Height:      res.Height,
+		MainImage:   imageResponse(res.MainImage),
+		Images:      imagesResponse,
+		Categories:  categoriesResponse,
+	}, nil
 }
 
-func (service *ProductServiceImpl) FindById(ctx context.Context, productId string) web.ProductDetailResponse {
+func (service *ProductServiceImpl) FindById(ctx context.Context, productId string) (web.ProductDetailResponse, error) {
 	product, err := service.ProductRepository.FindById(ctx, productId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.ProductDetailResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
 	merchant, err := service.MerchantRepository.FindById(ctx, product.MerchantId)
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.ProductDetailResponse{}, err
+	}
 
 	var imagesResponse []web.ImageResponse
 	for _, img := range product.Images {
-		imagesResponse = append(imagesResponse, web.ImageResponse{
-			Id:       img.Id.Hex(),
-			FileName: img.FileName,
-			URL:      img.URL,
-		})
+		imagesResponse = append(imagesResponse, imageResponse(&img))
 	}
 
 	var categoriesResponse []web.CategorySimpleResponse
 	for _, v := range product.Categories {
 		category, err := service.CategoryRepository.FindById(ctx, v.CategoryId)
-		helper.PanicIfError(err)
+		if err != nil {
+			return web.ProductDetailResponse{}, err
+		}
 		categoriesResponse = append(categoriesResponse, web.CategorySimpleResponse{
 			Id:   category.Id.Hex(),
 			Name: category.Name,
@@ -151,23 +162,21 @@ func (service *ProductServiceImpl) FindById(ctx context.Context, productId strin
 		Description: product.Description,
 		Price:       product.Price,
 		Stock:       product.Stock,
-		MainImage: web.ImageResponse{
-			Id:       product.MainImage.Id.Hex(),
-			FileName: product.MainImage.FileName,
-			URL:      product.MainImage.URL,
-		},
-		Images:     imagesResponse,
-		Categories: categoriesResponse,
+		Weight:      product.Weight,
+		Length:      product.Length,
+		Width:       product.Width,
+		Height:      product.Height,
+		MainImage:   imageResponse(product.MainImage),
+		Images:      imagesResponse,
+		Categories:  categoriesResponse,
+		Variants:    productVariantResponses(product),
+		Rating:      ratingResponse(product.RatingCount, product.RatingTotal),
 		Merchant: web.MerchantSimpleResponse{
-			Id:    merchant.Id.Hex(),
-			Name:  merchant.Name,
-			Slug:  merchant.Slug,
-			Phone: merchant.Phone,
-			MainImage: web.ImageResponse{
-				Id:       merchant.MainImage.Id.Hex(),
-				FileName: merchant.MainImage.FileName,
-				URL:      merchant.MainImage.URL,
-			},
+			Id:        merchant.Id.Hex(),
+			Name:      merchant.Name,
+			Slug:      merchant.Slug,
+			Phone:     merchant.Phone,
+			MainImage: imageResponse(merchant.MainImage),
 			Address: web.AddressResponse{
 				Address:    merchant.Address.Address,
 				City:       merchant.Address.City,
@@ -175,59 +184,61 @@ func (service *ProductServiceImpl) FindById(ctx context.Context, productId strin
 				PostalCode: merchant.Address.PostalCode,
 			},
 		},
-	}
+	}, nil
 }
 
-func (service *ProductServiceImpl) FindAll(ctx context.Context, page int, perPage int) web.ProductFindAllResponse {
-	skip := (page - 1) * perPage
-	limit := perPage
-
-	products, err := service.ProductRepository.FindAll(ctx, skip, limit)
-	helper.PanicIfError(err)
-
-	itemCount, err := service.ProductRepository.CountDocuments(ctx)
-	helper.PanicIfError(err)
+func (service *ProductServiceImpl) Search(ctx context.Context, request web.ProductSearchRequest) (web.ProductFindAllResponse, error) {
 
-	var productsResponse []web.ProductSimpleResponse
-	for _, product := range products {
-		productsResponse = append(productsResponse, web.ProductSimpleResponse{
-			Id:          product.Id.Hex(),
-			MerchantId:  product.MerchantId,
-			Name:        product.Name,
-			Slug:        product.Slug,
-			Description: product.Description,
-			Price:       product.Price,
-			Stock:       product.Stock,
-			MainImage: web.ImageResponse{
-				Id:       product.MainImage.Id.Hex(),
-				FileName: product.MainImage.FileName,
-				URL:      product.MainImage.URL,
-			},
-		})
+	categories, err := service.CategoryRepository.FindAll(ctx)
+	if err != nil {
+		return web.ProductFindAllResponse{}, err
 	}
 
-	return web.ProductFindAllResponse{
-		Products: productsResponse,
-		Metadata: web.MetadataPaginationResponse{
-			CurrentPage: page,
-			PerPage:     perPage,
-			TotalData:   itemCount,
-		},
+	search := repository.ProductSearch{
+		Search:   request.Search,
+		MinPrice: request.MinPrice,
+		MaxPrice: request.MaxPrice,
+		InStock:  request.InStock,
+		Sort:     request.Sort,
+	}
+	if search.Sort == "" {
+		search.Sort = repository.ProductSortRelevance
+	}
+	// there is no relevance without a text to search, the newest come first then
+	if search.Sort == repository.ProductSortRelevance && search.Search == "" {
+		search.Sort = repository.ProductSortNewest
 	}
-}
-func (service *ProductServiceImpl) FindAllWithSearch(ctx context.Context, search string, page int, perPage int) web.ProductFindAllResponse {
 
-	skip := (page - 1) * perPage
-	limit := perPage
+	// a category also matches the products of its subcategories
+	for _, categoryId := range request.CategoryIds {
+		search.CategoryIds = append(search.CategoryIds, categoryId)
+		search.CategoryIds = append(search.CategoryIds, categoryDescendantIds(categories, categoryId)...)
+	}
 
-	products, err := service.ProductRepository.FindAllWithSearch(ctx, search, skip, limit)
-	helper.PanicIfError(err)
+	if request.City != "" || request.Province != "" {
+		merchants, err := service.MerchantRepository.FindByLocation(ctx, request.City, request.Province)
+		if err != nil {
+			return web.ProductFindAllResponse{}, err
+		}
+		for _, merchant := range merchants {
+			if request.MerchantId == "" || request.MerchantId == merchant.Id.Hex() {
+				search.MerchantIds = append(search.MerchantIds, merchant.Id.Hex())
+			}
+		}
+		if len(search.MerchantIds) == 0 {
+			return web.ProductFindAllResponse{Metadata: cursorMetadata(request.PerPage, 0, repository.PageInfo{})}, nil
+		}
+	} else if request.MerchantId != "" {
+		search.MerchantIds = []string{request.MerchantId}
+	}
 
-	itemCount, err := service.ProductRepository.CountDocuments(ctx)
-	helper.PanicIfError(err)
+	result, err := service.ProductRepository.Search(ctx, search, repository.Page{Cursor: request.Cursor, Limit: request.PerPage})
+	if err != nil {
+		return web.ProductFindAllResponse{}, pageError(err)
+	}
 
 	var productsResponse []web.ProductSimpleResponse
-	for _, product := range products {
+	for _, product := range result.Products {
 		productsResponse = append(productsResponse, web.ProductSimpleResponse{
 			Id:          product.Id.Hex(),
 			MerchantId:  product.MerchantId,
@@ -236,37 +247,70 @@ func (service *ProductServiceImpl) FindAllWithSearch(ctx context.Context, search
 			Description: product.Description,
 			Price:       product.Price,
 			Stock:       product.Stock,
-			MainImage: web.ImageResponse{
-				Id:       product.MainImage.Id.Hex(),
-				FileName: product.MainImage.FileName,
-				URL:      product.MainImage.URL,
-			},
+			MainImage:   imageResponse(product.MainImage),
+		})
+	}
+
+	categoryNames := make(map[string]string)
+	for _, category := range categories {
+		categoryNames[category.Id.Hex()] = category.Name
+	}
+	var categoriesFacet []web.ProductCategoryFacetResponse
+	for _, count := range result.Categories {
+		name, ok := categoryNames[count.CategoryId]
+		if !ok {
+			continue
+		}
+		categoriesFacet = append(categoriesFacet, web.ProductCategoryFacetResponse{
+			CategoryId: count.CategoryId,
+			Name:       name,
+			Count:      count.Count,
+		})
+	}
+
+	var pricesFacet []web.ProductPriceFacetResponse
+	for _, count := range result.Prices {
+		pricesFacet = append(pricesFacet, web.ProductPriceFacetResponse{
+			Min:   count.Min,
+			Max:   productPriceBucketMax(count.Min),
+			Count: count.Count,
 		})
 	}
 
 	return web.ProductFindAllResponse{
 		Products: productsResponse,
-		Metadata: web.MetadataPaginationResponse{
-			CurrentPage: page,
-			PerPage:     perPage,
-			TotalData:   itemCount,
+		Facets: web.ProductFacetResponse{
+			Categories: categoriesFacet,
+			Prices:     pricesFacet,
 		},
-	}
+		Metadata: cursorMetadata(request.PerPage, result.TotalData, result.PageInfo),
+	}, nil
 }
 
-func (service *ProductServiceImpl) Update(ctx context.Context, request web.ProductUpdateRequest) web.ProductUpdateRequest {
+func (service *ProductServiceImpl) Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductUpdateRequest, error) {
 	product, err := service.ProductRepository.FindById(ctx, request.Id)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
 
 	var categoriesUpdateRequest []schema.ProductCategory
 	for _, v := range request.Categories {
 		category, err := service.CategoryRepository.FindById(ctx, v.CategoryId)
-		helper.PanicIfError(err)
+		if err != nil {
+			return request, err
+		}
 		categoriesUpdateRequest = append(categoriesUpdateRequest, schema.ProductCategory{
 			CategoryId: category.Id.Hex(),
+			Name:       category.Name,
 		})
 	}
 
+	// the stock of a product with variants follows its variants, a zero stock is left out of the update
+	stock := request.Stock
+	if len(product.Variants) > 0 {
+		stock = 0
+	}
+
 	_, err = service.ProductRepository.Update(ctx, schema.Product{
 		Id:          product.Id,
 		UpdatedAt:   request.UpdatedAt,
@@ -274,100 +318,216 @@ func (service *ProductServiceImpl) Update(ctx context.Context, request web.Produ
 		Slug:        product.Slug,
 		Description: request.Description,
 		Price:       request.Price,
-		Stock:       request.Stock,
+		Stock:       stock,
+		Weight:      request.Weight,
+		Length:      request.Length,
+		Width:       request.Width,
+		Height:      request.Height,
 		Categories:  categoriesUpdateRequest,
 	})
-	helper.PanicIfError(err)
-	return request
+	if err != nil {
+		return request, err
+	}
+	return request, nil
 }
 
-func (service *ProductServiceImpl) UpdateMainImage(ctx context.Context, request web.ProductUpdateImageRequest) web.ProductUpdateImageRequestResponse {
+func (service *ProductServiceImpl) UpdateMainImage(ctx context.Context, request web.ProductUpdateImageRequest) (web.ProductUpdateImageRequestResponse, error) {
 	product, err := service.ProductRepository.FindById(ctx, request.Id)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.ProductUpdateImageRequestResponse{}, exception.NewNotFoundError(err.Error())
+	}
 
-	url, err := service.CloudinaryRepository.UploadImage(ctx, request.MainImage.FileName, request.MainImage.URL)
-	helper.PanicIfError(err)
+	mainImage, err := uploadImage(ctx, service.ImageRepository, *request.MainImage)
+	if err != nil {
+		return web.ProductUpdateImageRequestResponse{}, err
+	}
+	mainImage.Id = product.MainImage.Id
 
 	_, err = service.ProductRepository.Update(ctx, schema.Product{
 		Id:        product.Id,
 		UpdatedAt: request.UpdatedAt,
 		Slug:      product.Slug,
-		MainImage: &schema.Image{
-			Id:       product.MainImage.Id,
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
+		MainImage: &mainImage,
 	})
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.ProductUpdateImageRequestResponse{}, err
+	}
 
-	err = service.CloudinaryRepository.DeleteImage(ctx, product.MainImage.FileName)
-	helper.PanicIfError(err)
+	err = deleteImage(ctx, service.ImageRepository, *product.MainImage)
+	if err != nil {
+		return web.ProductUpdateImageRequestResponse{}, err
+	}
 
 	return web.ProductUpdateImageRequestResponse{
 		Id:        product.Id.Hex(),
 		UpdatedAt: request.UpdatedAt,
-		MainImage: web.ImageResponse{
-			Id:       product.MainImage.Id.Hex(),
-			FileName: request.MainImage.FileName,
-			URL:      url,
-		},
-	}
+		MainImage: imageResponse(&mainImage),
+	}, nil
 }
 
-func (service *ProductServiceImpl) PushImageIntoImages(ctx context.Context, productId string, request []web.ImageCreateRequest) []web.ImageCreateRequest {
+func (service *ProductServiceImpl) PushImageIntoImages(ctx context.Context, productId string, request []web.ImageCreateRequest) ([]web.ImageResponse, error) {
 	product, err := service.ProductRepository.FindById(ctx, productId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return nil, exception.NewNotFoundError(err.Error())
+	}
 
 	var imagesCreateRequest []schema.Image
-	var imagesResponse []web.ImageCreateRequest
+	var imagesResponse []web.ImageResponse
 
 	for _, image := range request {
-		url, err := service.CloudinaryRepository.UploadImage(ctx, image.FileName, image.URL)
-		helper.PanicIfError(err)
-		imagesCreateRequest = append(imagesCreateRequest, schema.Image{
-			Id:       primitive.NewObjectID(),
-			FileName: image.FileName,
-			URL:      url,
-		})
-		imagesResponse = append(imagesResponse, web.ImageCreateRequest{
-			FileName: image.FileName,
-			URL:      url,
-		})
+		img, err := uploadImage(ctx, service.ImageRepository, image)
+		if err != nil {
+			return nil, err
+		}
+		imagesCreateRequest = append(imagesCreateRequest, img)
+		imagesResponse = append(imagesResponse, imageResponse(&img))
 	}
 
 	_, err = service.ProductRepository.PushImageIntoImages(ctx, product.Id.Hex(), imagesCreateRequest)
-	helper.PanicIfError(err)
+	if err != nil {
+		return nil, err
+	}
 
-	return imagesResponse
+	return imagesResponse, nil
 }
 
-func (service *ProductServiceImpl) PullImageFromImages(ctx context.Context, productId string, imageId string) {
+func (service *ProductServiceImpl) PullImageFromImages(ctx context.Context, productId string, imageId string) error {
 	product, err := service.ProductRepository.FindById(ctx, productId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
 
 	res, err := service.ProductRepository.PullImageFromImages(ctx, product.Id.Hex(), imageId)
-	helper.PanicIfErrorNotFound(err)
-
-	err = service.CloudinaryRepository.DeleteImage(ctx, res.FileName)
-	helper.PanicIfError(err)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
 
+	return deleteImage(ctx, service.ImageRepository, res)
 }
 
-func (service *ProductServiceImpl) Delete(ctx context.Context, productId string) {
+func (service *ProductServiceImpl) Delete(ctx context.Context, productId string) error {
 	product, err := service.ProductRepository.FindById(ctx, productId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
 
 	err = service.CustomerRepository.PullProductFromAllCart(ctx, product.Id.Hex())
-	helper.PanicIfError(err)
+	if err != nil {
+		return err
+	}
+
+	err = service.CustomerRepository.PullProductFromAllWishlist(ctx, product.Id.Hex())
+	if err != nil {
+		return err
+	}
 
 	err = service.ProductRepository.Delete(ctx, product.Id.Hex())
-	helper.PanicIfError(err)
+	if err != nil {
+		return err
+	}
 
-	err = service.CloudinaryRepository.DeleteImage(ctx, product.MainImage.FileName)
-	helper.PanicIfError(err)
+	err = deleteImage(ctx, service.ImageRepository, *product.MainImage)
+	if err != nil {
+		return err
+	}
 
 	for _, image := range product.Images {
-		err = service.CloudinaryRepository.DeleteImage(ctx, image.FileName)
-		helper.PanicIfError(err)
+		err = deleteImage(ctx, service.ImageRepository, image)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *ProductServiceImpl) CreateVariant(ctx context.Context, request web.ProductVariantCreateRequest) (web.ProductVariantResponse, error) {
+	product, err := service.ProductRepository.FindById(ctx, request.ProductId)
+	if err != nil {
+		return web.ProductVariantResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	variant := schema.ProductVariant{
+		Id:      primitive.NewObjectID(),
+		Sku:     request.Sku,
+		Options: variantOptions(request.Options),
+		Price:   request.Price,
+		Stock:   request.Stock,
+		ImageId: request.ImageId,
+	}
+	err = validateVariant(product, variant)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	err = service.ProductRepository.PushVariant(ctx, product.Id.Hex(), variant)
+	if err != nil {
+		return web.ProductVariantResponse{}, helper.WrapDuplicateKeyError(err, "sku "+request.Sku+" already exists")
+	}
+	return productVariantResponse(product, variant), nil
+}
+
+func (service *ProductServiceImpl) UpdateVariant(ctx context.Context, request web.ProductVariantUpdateRequest) (web.ProductVariantResponse, error) {
+	product, err := service.ProductRepository.FindById(ctx, request.ProductId)
+	if err != nil {
+		return web.ProductVariantResponse{}, exception.NewNotFoundError(err.Error())
+	}
+	from, ok := findVariant(product, request.Id)
+	if !ok {
+		return web.ProductVariantResponse{}, exception.NewNotFoundError(fmt.Sprintf("variant id %s not found in product id %s", request.Id, request.ProductId))
+	}
+
+	variant := schema.ProductVariant{
+		Id:      from.Id,
+		Sku:     request.Sku,
+		Options: variantOptions(request.Options),
+		Price:   request.Price,
+		Stock:   request.Stock,
+		ImageId: request.ImageId,
+	}
+	err = validateVariant(product, variant)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	err = service.ProductRepository.UpdateVariant(ctx, product.Id.Hex(), from, variant)
+	if errors.Is(err, repository.ErrVariantChanged) {
+		return web.ProductVariantResponse{}, exception.NewConflictError(fmt.Sprintf("variant %s was updated by another request, please try again", request.Id))
+	} else if err != nil {
+		return web.ProductVariantResponse{}, helper.WrapDuplicateKeyError(err, "sku "+request.Sku+" already exists")
+	}
+	if from.Stock > 0 && variant.Stock == 0 {
+		pkg.StockOuts.WithLabelValues(pkg.StockOutSetToZero).Inc()
+	}
+	return productVariantResponse(product, variant), nil
+}
+
+// DeleteVariant also takes the variant out of every cart, orders keep the name of the variant they bought
+func (service *ProductServiceImpl) DeleteVariant(ctx context.Context, productId string, variantId string) error {
+	product, err := service.ProductRepository.FindById(ctx, productId)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
+	variant, ok := findVariant(product, variantId)
+	if !ok {
+		return exception.NewNotFoundError(fmt.Sprintf("variant id %s not found in product id %s", variantId, productId))
+	}
+
+	err = service.ProductRepository.PullVariant(ctx, product.Id.Hex(), variant)
+	if errors.Is(err, repository.ErrVariantChanged) {
+		return exception.NewConflictError(fmt.Sprintf("variant %s was updated by another request, please try again", variantId))
+	} else if err != nil {
+		return err
+	}
+
+	return service.CustomerRepository.PullVariantFromAllCart(ctx, product.Id.Hex(), variantId)
+}
+
+// productPriceBucketMax is the boundary following min, 0 for the last bucket
+func productPriceBucketMax(min int) int {
+	for _, boundary := range repository.ProductPriceBoundaries {
+		if boundary > min {
+			return boundary
+		}
 	}
+	return 0
 }
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"weplant-backend/exception"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+func findVariant(product schema.Product, variantId string) (schema.ProductVariant, bool) {
+	for _, v := range product.Variants {
+		if v.Id.Hex() == variantId {
+			return v, true
+		}
+	}
+	return schema.ProductVariant{}, false
+}
+
+// variantName describes the options of the variant, like "pot: 10cm, planter: with"
+func variantName(variant schema.ProductVariant) string {
+	var options []string
+	for _, v := range variant.Options {
+		options = append(options, v.Name+": "+v.Value)
+	}
+	return strings.Join(options, ", ")
+}
+
+// sellableVariant returns the variant a cart entry buys, products with variants can only be bought through one of them
+func sellableVariant(product schema.Product, variantId string) (schema.ProductVariant, error) {
+	if variantId == "" {
+		if len(product.Variants) > 0 {
+			return schema.ProductVariant{}, exception.NewValidationError(fmt.Sprintf("choose a variant of %s", product.Name), web.FieldErrorResponse{
+				Field:   "variant_id",
+				Message: "is required",
+			})
+		}
+		return schema.ProductVariant{}, nil
+	}
+
+	variant, ok := findVariant(product, variantId)
+	if !ok {
+		return variant, exception.NewValidationError(fmt.Sprintf("variant %s of %s is not available", variantId, product.Name), web.FieldErrorResponse{
+			Field:   "variant_id",
+			Message: "is not available",
+		})
+	}
+	return variant, nil
+}
+
+// updateStock adds the quantity to the stock of the product or of its variant, a negative quantity takes it
+func updateStock(ctx context.Context, productRepository repository.ProductRepository, productId string, variantId string, quantity int) error {
+	if variantId != "" {
+		return productRepository.UpdateVariantQuantity(ctx, productId, variantId, quantity)
+	}
+	return productRepository.UpdateQuantity(ctx, schema.Product{
+		Id:    helper.ObjectIDFromHex(productId),
+		Stock: quantity,
+	})
+}
+
+// takeStock only takes the stock when enough is left, otherwise repository.ErrInsufficientStock is returned
+func takeStock(ctx context.Context, productRepository repository.ProductRepository, productId string, variantId string, quantity int) error {
+	if variantId != "" {
+		return productRepository.ReserveVariantStock(ctx, productId, variantId, quantity)
+	}
+	return productRepository.ReserveStock(ctx, productId, quantity)
+}
+
+// validateVariant checks the variant against the other variants of the product
+func validateVariant(product schema.Product, variant schema.ProductVariant) error {
+	names := map[string]bool{}
+	for _, v := range variant.Options {
+		name := strings.ToLower(v.Name)
+		if names[name] {
+			return exception.NewValidationError(fmt.Sprintf("option %s is set twice", v.Name))
+		}
+		names[name] = true
+	}
+
+	if variant.ImageId != "" && productImage(product, variant.ImageId) == nil {
+		return exception.NewValidationError(fmt.Sprintf("image %s is not an image of %s", variant.ImageId, product.Name), web.FieldErrorResponse{
+			Field:   "image_id",
+			Message: "is not an image of the product",
+		})
+	}
+
+	for _, v := range product.Variants {
+		if v.Id == variant.Id {
+			continue
+		}
+		if !sameOptionNames(v, variant) {
+			return exception.NewValidationError(fmt.Sprintf("variants of %s have the options %s", product.Name, optionNames(v)))
+		}
+		if strings.EqualFold(variantName(v), variantName(variant)) {
+			return exception.NewConflictError(fmt.Sprintf("variant %s of %s already exists", variantName(variant), product.Name))
+		}
+		if v.Sku == variant.Sku {
+			return exception.NewConflictError(fmt.Sprintf("sku %s already exists", variant.Sku))
+		}
+	}
+	return nil
+}
+
+func sameOptionNames(a schema.ProductVariant, b schema.ProductVariant) bool {
+	if len(a.Options) != len(b.Options) {
+		return false
+	}
+	for i := range a.Options {
+		if !strings.EqualFold(a.Options[i].Name, b.Options[i].Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func optionNames(variant schema.ProductVariant) string {
+	var names []string
+	for _, v := range variant.Options {
+		names = append(names, v.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func productImage(product schema.Product, imageId string) *schema.Image {
+	if product.MainImage != nil && product.MainImage.Id.Hex() == imageId {
+		return product.MainImage
+	}
+	for i := range product.Images {
+		if product.Images[i].Id.Hex() == imageId {
+			return &product.Images[i]
+		}
+	}
+	return nil
+}
+
+// variantImageResponse falls back to the main image of the product when the variant has no image of its own
+func variantImageResponse(product schema.Product, variant schema.ProductVariant) web.ImageResponse {
+	image := productImage(product, variant.ImageId)
+	if image == nil {
+		image = product.MainImage
+	}
+	return imageResponse(image)
+}
+
+func variantOptions(options []web.ProductVariantOptionRequest) []schema.ProductVariantOption {
+	var variantOptions []schema.ProductVariantOption
+	for _, v := range options {
+		variantOptions = append(variantOptions, schema.ProductVariantOption{
+			Name:  strings.TrimSpace(v.Name),
+			Value: strings.TrimSpace(v.Value),
+		})
+	}
+	return variantOptions
+}
+
+func productVariantResponse(product schema.Product, variant schema.ProductVariant) web.ProductVariantResponse {
+	var optionsResponse []web.ProductVariantOptionResponse
+	for _, v := range variant.Options {
+		optionsResponse = append(optionsResponse, web.ProductVariantOptionResponse{
+			Name:  v.Name,
+			Value: v.Value,
+		})
+	}
+	return web.ProductVariantResponse{
+		Id:      variant.Id.Hex(),
+		Sku:     variant.Sku,
+		Options: optionsResponse,
+		Price:   variant.Price,
+		Stock:   variant.Stock,
+		Image:   variantImageResponse(product, variant),
+	}
+}
+
+func productVariantResponses(product schema.Product) []web.ProductVariantResponse {
+	var variantsResponse []web.ProductVariantResponse
+	for _, v := range product.Variants {
+		variantsResponse = append(variantsResponse, productVariantResponse(product, v))
+	}
+	return variantsResponse
+}
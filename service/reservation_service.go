@@ -0,0 +1,7 @@
+package service
+
+import "context"
+
+type ReservationService interface {
+	ReleaseExpired(ctx context.Context) (int, error)
+}
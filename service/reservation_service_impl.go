@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"weplant-backend/exception"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/repository"
+)
+
+// ReservationDuration is how long checkout holds the stock, the midtrans payment expires after the same duration
+const ReservationDuration = 15 * time.Minute
+
+type ReservationServiceImpl struct {
+	ReservationRepository repository.ReservationRepository
+	ProductRepository     repository.ProductRepository
+	MidtransRepository    repository.MidtransRepository
+	SessionRepository     repository.SessionRepository
+}
+
+func NewReservationService(reservationRepository repository.ReservationRepository, productRepository repository.ProductRepository, midtransRepository repository.MidtransRepository, sessionRepository repository.SessionRepository) ReservationService {
+	return &ReservationServiceImpl{
+		ReservationRepository: reservationRepository,
+		ProductRepository:     productRepository,
+		MidtransRepository:    midtransRepository,
+		SessionRepository:     sessionRepository,
+	}
+}
+
+// ReleaseExpired gives the stock of expired reservations back, unless midtrans says the payment went through.
+// A reservation that fails is left for the next sweep, the others are still released.
+func (service *ReservationServiceImpl) ReleaseExpired(ctx context.Context) (int, error) {
+	reservations, err := service.ReservationRepository.FindExpired(ctx, helper.GetTimeNow())
+	if err != nil {
+		return 0, err
+	}
+
+	var released int
+	var errs releaseErrors
+	for _, reservation := range reservations {
+		err = service.releaseExpired(ctx, reservation)
+		if errors.Is(err, errReservationPaid) {
+			continue
+		} else if err != nil {
+			helper.GetLogger(ctx).Error("release expired reservation", "transaction_id", reservation.TransactionId, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		released++
+	}
+	if len(errs) > 0 {
+		return released, errs
+	}
+	return released, nil
+}
+
+// errReservationPaid leaves the reservation to the callback, which commits it
+var errReservationPaid = errors.New("reservation is paid")
+
+func (service *ReservationServiceImpl) releaseExpired(ctx context.Context, reservation schema.Reservation) error {
+	res, errMidtrans := service.MidtransRepository.CheckTransaction(reservation.TransactionId)
+	if errMidtrans != nil && errMidtrans.StatusCode != http.StatusNotFound {
+		return exception.NewPaymentGatewayError(errMidtrans.GetMessage())
+	}
+	if errMidtrans == nil {
+		switch helper.CheckTransactionStatus(*res) {
+		case "success":
+			return errReservationPaid
+		case "pending":
+			_, errMidtrans = service.MidtransRepository.CancelTransaction(reservation.TransactionId)
+			if errMidtrans != nil {
+				return exception.NewPaymentGatewayError(errMidtrans.GetMessage())
+			}
+		}
+	}
+
+	return service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		return releaseReservation(ctx, service.ReservationRepository, service.ProductRepository, reservation)
+	})
+}
+
+// releaseErrors are the errors of one sweep
+type releaseErrors []error
+
+func (errs releaseErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d reservations failed: %s", len(errs), strings.Join(messages, "; "))
+}
+
+// releaseReservation puts the reserved stock back, it is a no-op when the reservation was already committed or released
+func releaseReservation(ctx context.Context, reservationRepository repository.ReservationRepository, productRepository repository.ProductRepository, reservation schema.Reservation) error {
+	err := reservationRepository.UpdateStatus(ctx, reservation.Id.Hex(), schema.ReservationStatusActive, schema.ReservationStatusReleased)
+	if errors.Is(err, repository.ErrReservationStatusChanged) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, p := range reservation.Products {
+		err = updateStock(ctx, productRepository, p.ProductId, p.VariantId, p.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
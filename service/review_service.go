@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+type ReviewService interface {
+	Create(ctx context.Context, request web.ReviewCreateRequest) (web.ReviewResponse, error)
+	FindByProductId(ctx context.Context, productId string, cursor string, perPage int) (web.ReviewFindAllResponse, error)
+	Update(ctx context.Context, request web.ReviewUpdateRequest) (web.ReviewResponse, error)
+	Delete(ctx context.Context, customerId string, reviewId string) error
+	Reply(ctx context.Context, request web.ReviewReplyRequest) (web.ReviewResponse, error)
+}
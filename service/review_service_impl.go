@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"weplant-backend/exception"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+type ReviewServiceImpl struct {
+	ReviewRepository   repository.ReviewRepository
+	OrderRepository    repository.OrderRepository
+	ProductRepository  repository.ProductRepository
+	MerchantRepository repository.MerchantRepository
+	CustomerRepository repository.CustomerRepository
+	ImageRepository    repository.ImageRepository
+	SessionRepository  repository.SessionRepository
+}
+
+func NewReviewService(reviewRepository repository.ReviewRepository, orderRepository repository.OrderRepository, productRepository repository.ProductRepository, merchantRepository repository.MerchantRepository, customerRepository repository.CustomerRepository, imageRepository repository.ImageRepository, sessionRepository repository.SessionRepository) ReviewService {
+	return &ReviewServiceImpl{
+		ReviewRepository:   reviewRepository,
+		OrderRepository:    orderRepository,
+		ProductRepository:  productRepository,
+		MerchantRepository: merchantRepository,
+		CustomerRepository: customerRepository,
+		ImageRepository:    imageRepository,
+		SessionRepository:  sessionRepository,
+	}
+}
+
+// Create only accepts a review from the customer of a completed order that contains the product
+func (service *ReviewServiceImpl) Create(ctx context.Context, request web.ReviewCreateRequest) (web.ReviewResponse, error) {
+	order, err := service.OrderRepository.FindById(ctx, request.OrderId)
+	if err != nil || order.CustomerId != request.CustomerId {
+		return web.ReviewResponse{}, exception.NewNotFoundError(fmt.Sprintf("order id %s not found in customer id %s", request.OrderId, request.CustomerId))
+	}
+	if !orderWasCompleted(order) {
+		return web.ReviewResponse{}, exception.NewForbiddenError("only a completed order can be reviewed")
+	}
+	if !orderHasProduct(order, request.ProductId) {
+		return web.ReviewResponse{}, exception.NewForbiddenError(fmt.Sprintf("product id %s is not part of order id %s", request.ProductId, request.OrderId))
+	}
+
+	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
+	if err != nil {
+		return web.ReviewResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	var images []schema.Image
+	for _, image := range request.Images {
+		img, err := uploadImage(ctx, service.ImageRepository, image)
+		if err != nil {
+			service.deleteImages(ctx, images)
+			return web.ReviewResponse{}, err
+		}
+		images = append(images, img)
+	}
+
+	var review schema.Review
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = service.ReviewRepository.Create(ctx, schema.Review{
+			CreatedAt:    request.CreatedAt,
+			UpdatedAt:    request.UpdatedAt,
+			OrderId:      order.Id.Hex(),
+			ProductId:    request.ProductId,
+			MerchantId:   order.MerchantId,
+			CustomerId:   request.CustomerId,
+			CustomerName: customer.UserName,
+			Rating:       request.Rating,
+			Text:         request.Text,
+			Images:       images,
+		})
+		if err != nil {
+			return helper.WrapDuplicateKeyError(err, "product id "+request.ProductId+" is already reviewed for order id "+request.OrderId)
+		}
+		return service.updateRating(ctx, review, 1, request.Rating)
+	})
+	if err != nil {
+		service.deleteImages(ctx, images)
+		return web.ReviewResponse{}, err
+	}
+
+	return reviewResponse(review), nil
+}
+
+func (service *ReviewServiceImpl) FindByProductId(ctx context.Context, productId string, cursor string, perPage int) (web.ReviewFindAllResponse, error) {
+	product, err := service.ProductRepository.FindById(ctx, productId)
+	if err != nil {
+		return web.ReviewFindAllResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	reviews, pageInfo, err := service.ReviewRepository.FindByProductId(ctx, productId, repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.ReviewFindAllResponse{}, pageError(err)
+	}
+	itemCount, err := service.ReviewRepository.CountByProductId(ctx, productId)
+	if err != nil {
+		return web.ReviewFindAllResponse{}, err
+	}
+
+	var reviewsResponse []web.ReviewResponse
+	for _, v := range reviews {
+		reviewsResponse = append(reviewsResponse, reviewResponse(v))
+	}
+
+	return web.ReviewFindAllResponse{
+		ProductId: product.Id.Hex(),
+		Rating:    ratingResponse(product.RatingCount, product.RatingTotal),
+		Reviews:   reviewsResponse,
+		Metadata:  cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+func (service *ReviewServiceImpl) Update(ctx context.Context, request web.ReviewUpdateRequest) (web.ReviewResponse, error) {
+	review, err := service.ReviewRepository.FindById(ctx, request.Id)
+	if err != nil || review.CustomerId != request.CustomerId {
+		return web.ReviewResponse{}, exception.NewNotFoundError(fmt.Sprintf("review id %s not found in customer id %s", request.Id, request.CustomerId))
+	}
+
+	fromRating := review.Rating
+	review.UpdatedAt = request.UpdatedAt
+	review.Rating = request.Rating
+	review.Text = request.Text
+
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		err := service.ReviewRepository.Update(ctx, review, fromRating)
+		if errors.Is(err, repository.ErrReviewChanged) {
+			return exception.NewConflictError("review was changed by another request, please try again")
+		} else if err != nil {
+			return err
+		}
+		return service.updateRating(ctx, review, 0, request.Rating-fromRating)
+	})
+	if err != nil {
+		return web.ReviewResponse{}, err
+	}
+
+	return reviewResponse(review), nil
+}
+
+func (service *ReviewServiceImpl) Delete(ctx context.Context, customerId string, reviewId string) error {
+	review, err := service.ReviewRepository.FindById(ctx, reviewId)
+	if err != nil || review.CustomerId != customerId {
+		return exception.NewNotFoundError(fmt.Sprintf("review id %s not found in customer id %s", reviewId, customerId))
+	}
+
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		err := service.ReviewRepository.Delete(ctx, review)
+		if errors.Is(err, repository.ErrReviewChanged) {
+			return exception.NewConflictError("review was changed by another request, please try again")
+		} else if err != nil {
+			return err
+		}
+		return service.updateRating(ctx, review, -1, -review.Rating)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, image := range review.Images {
+		err := deleteImage(ctx, service.ImageRepository, image)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *ReviewServiceImpl) Reply(ctx context.Context, request web.ReviewReplyRequest) (web.ReviewResponse, error) {
+	review, err := service.ReviewRepository.FindById(ctx, request.Id)
+	if err != nil || review.MerchantId != request.MerchantId {
+		return web.ReviewResponse{}, exception.NewNotFoundError(fmt.Sprintf("review id %s not found in merchant id %s", request.Id, request.MerchantId))
+	}
+
+	reply := schema.ReviewReply{
+		CreatedAt: request.UpdatedAt,
+		UpdatedAt: request.UpdatedAt,
+		Text:      request.Text,
+	}
+	if review.Reply != nil {
+		reply.CreatedAt = review.Reply.CreatedAt
+	}
+
+	err = service.ReviewRepository.UpdateReply(ctx, request.Id, reply)
+	if err != nil {
+		return web.ReviewResponse{}, err
+	}
+	review.Reply = &reply
+
+	return reviewResponse(review), nil
+}
+
+// updateRating keeps the rating count and total of the product and its merchant in step with the reviews
+func (service *ReviewServiceImpl) updateRating(ctx context.Context, review schema.Review, count int, total int) error {
+	err := service.ProductRepository.UpdateRating(ctx, review.ProductId, count, total)
+	if err != nil {
+		return err
+	}
+	return service.MerchantRepository.UpdateRating(ctx, review.MerchantId, count, total)
+}
+
+// deleteImages is a best effort cleanup of images uploaded for a review that was not saved
+func (service *ReviewServiceImpl) deleteImages(ctx context.Context, images []schema.Image) {
+	for _, image := range images {
+		err := deleteImage(ctx, service.ImageRepository, image)
+		if err != nil {
+			helper.GetLogger(ctx).Error("delete review image", "file_name", image.FileName, "error", err)
+		}
+	}
+}
+
+func orderHasProduct(order schema.Order, productId string) bool {
+	for _, item := range order.Items {
+		if item.ProductId == productId {
+			return true
+		}
+	}
+	return false
+}
+
+// ratingResponse rounds the average to one decimal
+func ratingResponse(count int, total int) web.RatingResponse {
+	if count <= 0 {
+		return web.RatingResponse{}
+	}
+	return web.RatingResponse{
+		Average: math.Round(float64(total)/float64(count)*10) / 10,
+		Count:   count,
+	}
+}
+
+func reviewResponse(review schema.Review) web.ReviewResponse {
+	var imagesResponse []web.ImageResponse
+	for _, image := range review.Images {
+		imagesResponse = append(imagesResponse, imageResponse(&image))
+	}
+
+	var replyResponse *web.ReviewReplyResponse
+	if review.Reply != nil {
+		replyResponse = &web.ReviewReplyResponse{
+			CreatedAt: review.Reply.CreatedAt,
+			UpdatedAt: review.Reply.UpdatedAt,
+			Text:      review.Reply.Text,
+		}
+	}
+
+	return web.ReviewResponse{
+		Id:           review.Id.Hex(),
+		CreatedAt:    review.CreatedAt,
+		UpdatedAt:    review.UpdatedAt,
+		OrderId:      review.OrderId,
+		ProductId:    review.ProductId,
+		MerchantId:   review.MerchantId,
+		CustomerId:   review.CustomerId,
+		CustomerName: review.CustomerName,
+		Rating:       review.Rating,
+		Text:         review.Text,
+		Images:       imagesResponse,
+		Reply:        replyResponse,
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"weplant-backend/exception"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// productShippingWeight is the weight in grams couriers charge, the packed size counts when it is heavier (6000 cm3 per kilogram)
+func productShippingWeight(product schema.Product) int {
+	volumetric := product.Length * product.Width * product.Height / 6
+	if volumetric > product.Weight {
+		return volumetric
+	}
+	return product.Weight
+}
+
+// shippingRates quotes the package a merchant sends to the destination province
+func shippingRates(ctx context.Context, provider repository.ShippingRateProvider, merchant schema.Merchant, destination string, weight int) ([]repository.ShippingRate, error) {
+	if merchant.Address == nil || merchant.Address.Province == "" {
+		return nil, exception.NewValidationError(fmt.Sprintf("merchant %s has not set the address it ships from", merchant.Name))
+	}
+
+	rates, err := provider.Rates(ctx, repository.ShippingRateRequest{
+		OriginProvince:      merchant.Address.Province,
+		DestinationProvince: destination,
+		Weight:              weight,
+	})
+	if errors.Is(err, repository.ErrShippingRouteUnavailable) {
+		return nil, exception.NewValidationError(fmt.Sprintf("no courier ships from %s to %s", merchant.Address.Province, destination), web.FieldErrorResponse{
+			Field:   "province",
+			Message: "no courier ships to this province",
+		})
+	}
+	return rates, err
+}
+
+// chooseShippingRate returns the rate of the courier service the customer chose
+func chooseShippingRate(rates []repository.ShippingRate, courier string, service string) (repository.ShippingRate, error) {
+	for _, v := range rates {
+		if strings.EqualFold(v.Courier, courier) && strings.EqualFold(v.Service, service) {
+			return v, nil
+		}
+	}
+	return repository.ShippingRate{}, exception.NewValidationError(fmt.Sprintf("courier %s %s is not available for this address", courier, service), web.FieldErrorResponse{
+		Field:   "service",
+		Message: "courier service is not available",
+	})
+}
+
+func shippingRateResponses(rates []repository.ShippingRate) []web.ShippingRateResponse {
+	var ratesResponse []web.ShippingRateResponse
+	for _, v := range rates {
+		ratesResponse = append(ratesResponse, web.ShippingRateResponse{
+			Courier: v.Courier,
+			Service: v.Service,
+			Fee:     v.Fee,
+			Etd:     v.Etd,
+		})
+	}
+	return ratesResponse
+}
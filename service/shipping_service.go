@@ -0,0 +1,10 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+type ShippingService interface {
+	Quote(ctx context.Context, request web.ShippingQuoteRequest) (web.ShippingQuoteResponse, error)
+}
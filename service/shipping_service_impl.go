@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/exception"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+type ShippingServiceImpl struct {
+	CustomerRepository   repository.CustomerRepository
+	ProductRepository    repository.ProductRepository
+	MerchantRepository   repository.MerchantRepository
+	ShippingRateProvider repository.ShippingRateProvider
+}
+
+func NewShippingService(customerRepository repository.CustomerRepository, productRepository repository.ProductRepository, merchantRepository repository.MerchantRepository, shippingRateProvider repository.ShippingRateProvider) ShippingService {
+	return &ShippingServiceImpl{
+		CustomerRepository:   customerRepository,
+		ProductRepository:    productRepository,
+		MerchantRepository:   merchantRepository,
+		ShippingRateProvider: shippingRateProvider,
+	}
+}
+
+// Quote prices the cart the way checkout does, every merchant sends its own package
+func (service *ShippingServiceImpl) Quote(ctx context.Context, request web.ShippingQuoteRequest) (web.ShippingQuoteResponse, error) {
+	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
+	if err != nil {
+		return web.ShippingQuoteResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	var merchantIds []string
+	weights := map[string]int{}
+	for _, v := range customer.Carts {
+		product, err := service.ProductRepository.FindById(ctx, v.ProductId)
+		if err != nil {
+			return web.ShippingQuoteResponse{}, err
+		}
+		if _, ok := weights[product.MerchantId]; !ok {
+			merchantIds = append(merchantIds, product.MerchantId)
+		}
+		weights[product.MerchantId] += productShippingWeight(product) * v.Quantity
+	}
+
+	var packagesResponse []web.ShippingPackageResponse
+	for _, merchantId := range merchantIds {
+		merchant, err := service.MerchantRepository.FindById(ctx, merchantId)
+		if err != nil {
+			return web.ShippingQuoteResponse{}, err
+		}
+		rates, err := shippingRates(ctx, service.ShippingRateProvider, merchant, request.Province, weights[merchantId])
+		if err != nil {
+			return web.ShippingQuoteResponse{}, err
+		}
+		packagesResponse = append(packagesResponse, web.ShippingPackageResponse{
+			MerchantId:   merchantId,
+			MerchantName: merchant.Name,
+			Weight:       weights[merchantId],
+			Rates:        shippingRateResponses(rates),
+		})
+	}
+
+	return web.ShippingQuoteResponse{
+		CustomerId: customer.Id.Hex(),
+		Province:   request.Province,
+		Packages:   packagesResponse,
+	}, nil
+}
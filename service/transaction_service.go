@@ -7,7 +7,7 @@ import (
 )
 
 type TransactionService interface {
-	Create(ctx context.Context, request web.TransactionCreateRequest) web.TransactionCreateRequestResponse
-	Cancel(ctx context.Context, customerId string, transactionId string)
-	Callback(ctx context.Context, request coreapi.TransactionStatusResponse)
+	Create(ctx context.Context, request web.TransactionCreateRequest) (web.TransactionCreateRequestResponse, error)
+	Cancel(ctx context.Context, customerId string, transactionId string) error
+	Callback(ctx context.Context, request coreapi.TransactionStatusResponse) error
 }
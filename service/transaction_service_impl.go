@@ -7,76 +7,251 @@ import (
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"strings"
+	"weplant-backend/exception"
 	"weplant-backend/helper"
 	"weplant-backend/model/schema"
 	"weplant-backend/model/web"
+	"weplant-backend/pkg"
 	"weplant-backend/repository"
 )
 
 type TransactionServiceImpl struct {
-	CustomerRepository repository.CustomerRepository
-	ProductRepository  repository.ProductRepository
-	MidtransRepository repository.MidtransRepository
-	MerchantRepository repository.MerchantRepository
+	CustomerRepository            repository.CustomerRepository
+	ProductRepository             repository.ProductRepository
+	MidtransRepository            repository.MidtransRepository
+	MerchantRepository            repository.MerchantRepository
+	PaymentNotificationRepository repository.PaymentNotificationRepository
+	ReservationRepository         repository.ReservationRepository
+	SessionRepository             repository.SessionRepository
+	TransactionRepository         repository.TransactionRepository
+	OrderRepository               repository.OrderRepository
+	ShippingRateProvider          repository.ShippingRateProvider
+	VoucherRepository             repository.VoucherRepository
+	VoucherUsageRepository        repository.VoucherUsageRepository
 }
 
-func NewTransactionService(customerRepository repository.CustomerRepository, productRepository repository.ProductRepository, midtransRepository repository.MidtransRepository, merchantRepository repository.MerchantRepository) TransactionService {
+func NewTransactionService(customerRepository repository.CustomerRepository, productRepository repository.ProductRepository, midtransRepository repository.MidtransRepository, merchantRepository repository.MerchantRepository, paymentNotificationRepository repository.PaymentNotificationRepository, reservationRepository repository.ReservationRepository, sessionRepository repository.SessionRepository, transactionRepository repository.TransactionRepository, orderRepository repository.OrderRepository, shippingRateProvider repository.ShippingRateProvider, voucherRepository repository.VoucherRepository, voucherUsageRepository repository.VoucherUsageRepository) TransactionService {
 	return &TransactionServiceImpl{
-		CustomerRepository: customerRepository,
-		ProductRepository:  productRepository,
-		MidtransRepository: midtransRepository,
-		MerchantRepository: merchantRepository,
+		CustomerRepository:            customerRepository,
+		ProductRepository:             productRepository,
+		MidtransRepository:            midtransRepository,
+		MerchantRepository:            merchantRepository,
+		PaymentNotificationRepository: paymentNotificationRepository,
+		ReservationRepository:         reservationRepository,
+		SessionRepository:             sessionRepository,
+		TransactionRepository:         transactionRepository,
+		OrderRepository:               orderRepository,
+		ShippingRateProvider:          shippingRateProvider,
+		VoucherRepository:             voucherRepository,
+		VoucherUsageRepository:        voucherUsageRepository,
 	}
 }
 
-func (service *TransactionServiceImpl) Create(ctx context.Context, request web.TransactionCreateRequest) web.TransactionCreateRequestResponse {
+// errNotificationProcessed aborts the callback transaction when the notification is already in the ledger
+var errNotificationProcessed = errors.New("payment notification already processed")
+
+func (service *TransactionServiceImpl) Create(ctx context.Context, request web.TransactionCreateRequest) (web.TransactionCreateRequestResponse, error) {
 	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
-	helper.PanicIfErrorNotFound(err)
+	if err != nil {
+		return web.TransactionCreateRequestResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	// checkouts that do not choose a method keep paying by qris
+	payment := *request.Payment
+	if payment.Method == "" {
+		payment.Method = schema.PaymentMethodQris
+	}
+
+	// the voucher is only counted as used once the transaction is paid
+	var voucher *schema.Voucher
+	if request.VoucherCode != "" {
+		v, err := usableVoucher(ctx, service.VoucherRepository, service.VoucherUsageRepository, request.VoucherCode, customer.Id.Hex(), request.CreatedAt)
+		if err != nil {
+			return web.TransactionCreateRequestResponse{}, err
+		}
+		voucher = &v
+	}
+
+	transactionId := primitive.NewObjectID().Hex()
 
 	var productDetailMidtrans []midtrans.ItemDetails
 	var productDetailTransaction []schema.TransactionProduct
+	var reservationProducts []schema.ReservationProduct
+	var transactionOrders []schema.TransactionOrder
+	var transactionVoucher *schema.TransactionVoucher
 
 	var totalPrice int64
+	var soldOut int
+
+	// the stock is taken from the products while the payment is pending and given back when it expires
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		productDetailMidtrans = nil
+		productDetailTransaction = nil
+		reservationProducts = nil
+		transactionVoucher = nil
+		totalPrice = 0
+		soldOut = 0
+		var voucherLines []voucherLine
+		merchants := map[string]schema.Merchant{}
+		weights := map[string]int{}
+
+		for _, v := range customer.Carts {
+			product, err := service.ProductRepository.FindById(ctx, v.ProductId)
+			if err != nil {
+				return err
+			}
+
+			if v.Quantity < 1 {
+				return exception.NewValidationError(fmt.Sprintf("barang %s yang anda beli tidak boleh kurang dari 1", product.Name))
+			}
+
+			variant, err := sellableVariant(product, v.VariantId)
+			if err != nil {
+				return err
+			}
+
+			// a variant is sold at its own price from its own stock
+			itemId, name, price, stock := product.Id.Hex(), product.Name, product.Price, product.Stock
+			if v.VariantId != "" {
+				itemId = product.Id.Hex() + "-" + v.VariantId
+				name = product.Name + " (" + variantName(variant) + ")"
+				price, stock = variant.Price, variant.Stock
+				err = service.ProductRepository.ReserveVariantStock(ctx, product.Id.Hex(), v.VariantId, v.Quantity)
+			} else {
+				err = service.ProductRepository.ReserveStock(ctx, product.Id.Hex(), v.Quantity)
+			}
+			if errors.Is(err, repository.ErrInsufficientStock) {
+				return exception.NewOutOfStockError(fmt.Sprintf("barang %s yang anda beli harus kurang dari %d, dari stock yang tersedia", name, stock), product.Id.Hex())
+			} else if err != nil {
+				return err
+			}
+			if stock == v.Quantity {
+				soldOut++
+			}
+
+			merchant, err := service.MerchantRepository.FindById(ctx, product.MerchantId)
+			if err != nil {
+				return err
+			}
+
+			totalPrice += int64(price * v.Quantity)
+
+			productDetailMidtrans = append(productDetailMidtrans, midtrans.ItemDetails{
+				ID:           itemId,
+				Name:         name,
+				Price:        int64(price),
+				Qty:          int32(v.Quantity),
+				MerchantName: merchant.Name,
+			})
+
+			productDetailTransaction = append(productDetailTransaction, schema.TransactionProduct{
+				ProductId:   product.Id.Hex(),
+				VariantId:   v.VariantId,
+				VariantName: variantName(variant),
+				MerchantId:  product.MerchantId,
+				Price:       price,
+				Quantity:    v.Quantity,
+			})
+			voucherLines = append(voucherLines, voucherLine{
+				MerchantId:  product.MerchantId,
+				CategoryIds: productCategoryIds(product),
+				Amount:      price * v.Quantity,
+			})
+			merchants[product.MerchantId] = merchant
+			weights[product.MerchantId] += productShippingWeight(product) * v.Quantity
 
-	for _, v := range customer.Carts {
-		product, err := service.ProductRepository.FindById(ctx, v.ProductId)
-		helper.PanicIfError(err)
+			reservationProducts = append(reservationProducts, schema.ReservationProduct{
+				ProductId: product.Id.Hex(),
+				VariantId: v.VariantId,
+				Quantity:  v.Quantity,
+			})
 
-		if v.Quantity > product.Stock {
-			panic(fmt.Sprintf("barang %s yang anda beli harus kurang dari %d, dari stock yang tersedia", product.Name, product.Stock))
-		} else if v.Quantity < 1 {
-			panic(fmt.Sprintf("barang %s yang anda beli tidak boleh kurang dari 1", product.Name))
+			err = service.CustomerRepository.PullProductFromCart(ctx, customer.Id.Hex(), v)
+			if err != nil {
+				return err
+			}
 		}
 
-		merchant, err := service.MerchantRepository.FindById(ctx, product.MerchantId)
-		helper.PanicIfError(err)
+		// every merchant ships its own package with the courier service the customer chose
+		transactionOrders = splitTransactionOrders(productDetailTransaction)
+		for i, v := range transactionOrders {
+			merchant := merchants[v.MerchantId]
+			rates, err := shippingRates(ctx, service.ShippingRateProvider, merchant, request.Address.Province, weights[v.MerchantId])
+			if err != nil {
+				return err
+			}
+			rate, err := chooseShippingRate(rates, request.Shipping.Courier, request.Shipping.Service)
+			if err != nil {
+				return err
+			}
 
-		totalPrice += int64(product.Price * v.Quantity)
+			transactionOrders[i].Courier = rate.Courier
+			transactionOrders[i].Service = rate.Service
+			transactionOrders[i].ShippingFee = rate.Fee
+			transactionOrders[i].Total = v.Subtotal + rate.Fee
+			totalPrice += int64(rate.Fee)
 
-		productDetailMidtrans = append(productDetailMidtrans, midtrans.ItemDetails{
-			ID:           product.Id.Hex(),
-			Name:         product.Name,
-			Price:        int64(product.Price),
-			Qty:          int32(v.Quantity),
-			MerchantName: merchant.Name,
-		})
+			productDetailMidtrans = append(productDetailMidtrans, midtrans.ItemDetails{
+				ID:           "shipping-" + v.MerchantId,
+				Name:         fmt.Sprintf("Ongkos kirim %s %s", strings.ToUpper(rate.Courier), rate.Service),
+				Price:        int64(rate.Fee),
+				Qty:          1,
+				MerchantName: merchant.Name,
+			})
+		}
 
-		productDetailTransaction = append(productDetailTransaction, schema.TransactionProduct{
-			ProductId: product.Id.Hex(),
-			Price:     product.Price,
-			Quantity:  v.Quantity,
-		})
+		// the discount is charged as a negative line so the items still add up to the gross amount
+		if voucher != nil {
+			_, discount, err := voucherDiscount(*voucher, voucherLines)
+			if err != nil {
+				return err
+			}
+			transactionVoucher = &schema.TransactionVoucher{
+				VoucherId:  voucher.Id.Hex(),
+				Code:       voucher.Code,
+				MerchantId: voucher.MerchantId,
+				Discount:   discount,
+			}
+			applyVoucherDiscount(transactionOrders, *transactionVoucher)
+			totalPrice -= int64(discount)
+
+			productDetailMidtrans = append(productDetailMidtrans, midtrans.ItemDetails{
+				ID:    "voucher-" + voucher.Code,
+				Name:  "Voucher " + voucher.Code,
+				Price: -int64(discount),
+				Qty:   1,
+			})
+		}
 
-		err = service.CustomerRepository.PullProductFromCart(ctx, customer.Id.Hex(), product.Id.Hex())
-		helper.PanicIfError(err)
+		_, err := service.ReservationRepository.Create(ctx, schema.Reservation{
+			CreatedAt:     request.CreatedAt,
+			UpdatedAt:     request.UpdatedAt,
+			ExpiredAt:     request.CreatedAt + int(ReservationDuration.Seconds()),
+			TransactionId: transactionId,
+			CustomerId:    customer.Id.Hex(),
+			Status:        schema.ReservationStatusActive,
+			Products:      reservationProducts,
+		})
+		return err
+	})
+	if errors.As(err, &exception.OutOfStockError{}) {
+		pkg.StockOuts.WithLabelValues(pkg.StockOutInsufficient).Inc()
+	}
+	if err != nil {
+		return web.TransactionCreateRequestResponse{}, err
 	}
 
-	resMidtrans, errMidtrans := service.MidtransRepository.CreateTransaction(coreapi.ChargeReq{
-		PaymentType: coreapi.PaymentTypeQris,
+	charge := coreapi.ChargeReq{
 		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  primitive.NewObjectID().Hex(),
+			OrderID:  transactionId,
 			GrossAmt: totalPrice,
 		},
+		CustomExpiry: &coreapi.CustomExpiry{
+			ExpiryDuration: int(ReservationDuration.Minutes()),
+			Unit:           "minute",
+		},
 		Items: &productDetailMidtrans,
 		CustomerDetails: &midtrans.CustomerDetails{
 			FName: customer.UserName,
@@ -100,19 +275,31 @@ func (service *TransactionServiceImpl) Create(ctx context.Context, request web.T
 			},
 		},
 		CustomField1: helper.ReturnPointerString(customer.Id.Hex()),
-	})
+	}
+	setChargePayment(&charge, payment)
+
+	resMidtrans, errMidtrans := service.MidtransRepository.CreateTransaction(charge)
 	if errMidtrans != nil {
-		panic(errMidtrans.GetMessage())
+		err = service.releaseReservation(ctx, transactionId)
+		if err != nil {
+			return web.TransactionCreateRequestResponse{}, err
+		}
+		return web.TransactionCreateRequestResponse{}, exception.NewPaymentGatewayError(errMidtrans.GetMessage())
 	}
 
-	err = service.CustomerRepository.CreateTransaction(ctx, customer.Id.Hex(), schema.Transaction{
+	instructions := chargePayment(payment.Method, resMidtrans, request.CreatedAt+int(ReservationDuration.Seconds()))
+
+	_, err = service.TransactionRepository.Create(ctx, schema.Transaction{
 		Id:          helper.ObjectIDFromHex(resMidtrans.OrderID),
 		CreatedAt:   request.CreatedAt,
 		UpdatedAt:   request.UpdatedAt,
+		CustomerId:  customer.Id.Hex(),
 		PaymentType: resMidtrans.PaymentType,
 		Status:      resMidtrans.TransactionStatus,
-		QRCode:      resMidtrans.Actions[0].URL,
+		Payment:     instructions,
 		Products:    productDetailTransaction,
+		Orders:      transactionOrders,
+		Voucher:     transactionVoucher,
 		Address: &schema.Address{
 			Address:    request.Address.Address,
 			City:       request.Address.City,
@@ -120,106 +307,256 @@ func (service *TransactionServiceImpl) Create(ctx context.Context, request web.T
 			PostalCode: request.Address.PostalCode,
 		},
 	})
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.TransactionCreateRequestResponse{}, err
+	}
+	pkg.CheckoutsCreated.WithLabelValues(payment.Method).Inc()
+	pkg.StockOuts.WithLabelValues(pkg.StockOutSoldOut).Add(float64(soldOut))
 
 	return web.TransactionCreateRequestResponse{
 		CreatedAt:   request.CreatedAt,
 		UpdatedAt:   request.UpdatedAt,
 		PaymentType: resMidtrans.PaymentType,
 		Status:      resMidtrans.TransactionStatus,
-		QRCode:      resMidtrans.Actions[0].URL,
+		Payment:     transactionPaymentResponse(instructions),
 		TotalPrice:  int(totalPrice),
+		Orders:      transactionOrderResponses(transactionOrders),
+		Voucher:     transactionVoucherResponse(transactionVoucher),
 		Address:     *request.Address,
-	}
+	}, nil
 }
 
-func (service *TransactionServiceImpl) Cancel(ctx context.Context, customerId string, transactionId string) {
+func (service *TransactionServiceImpl) Cancel(ctx context.Context, customerId string, transactionId string) error {
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
-	helper.PanicIfErrorNotFound(err)
-
-	var found bool
-
-	for _, transaction := range customer.Transactions {
-		if transaction.Id.Hex() == transactionId {
-			found = true
-			break
-		}
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
 	}
 
-	if !found {
-		helper.PanicIfErrorNotFound(errors.New(fmt.Sprintf("transaction id %s not found in customer id %s ", customerId, transactionId)))
+	transaction, err := service.TransactionRepository.FindById(ctx, transactionId)
+	if err != nil || transaction.CustomerId != customer.Id.Hex() {
+		return exception.NewNotFoundError(fmt.Sprintf("transaction id %s not found in customer id %s ", transactionId, customerId))
 	}
 
 	_, errMidtrans := service.MidtransRepository.CancelTransaction(transactionId)
 	if errMidtrans != nil {
-		panic(errMidtrans.GetMessage())
+		return exception.NewPaymentGatewayError(errMidtrans.GetMessage())
 	}
+
+	return service.releaseReservation(ctx, transactionId)
 }
 
-func (service *TransactionServiceImpl) Callback(ctx context.Context, request coreapi.TransactionStatusResponse) {
+func (service *TransactionServiceImpl) Callback(ctx context.Context, request coreapi.TransactionStatusResponse) error {
 	timeNow := helper.GetTimeNow()
 
 	res, errMidtrans := service.MidtransRepository.CheckTransaction(request.OrderID)
 	if errMidtrans != nil {
-		panic(errMidtrans.GetMessage())
+		return exception.NewPaymentGatewayError(errMidtrans.GetMessage())
+	}
+
+	pkg.PaymentCallbacks.WithLabelValues(res.TransactionStatus).Inc()
+
+	status := helper.CheckTransactionStatus(*res)
+	if status != "success" && status != "failed" {
+		// midtrans retries every notification not answered with 200, pending ones are only acknowledged
+		helper.GetLogger(ctx).Info("payment notification is not handled", "order_id", res.OrderID, "transaction_status", res.TransactionStatus)
+		return nil
 	}
 
 	customer, err := service.CustomerRepository.FindById(ctx, res.CustomField1)
-	helper.PanicIfError(err)
-
-	switch helper.CheckTransactionStatus(*res) {
-	case "success":
-		for _, v := range customer.Transactions {
-			if v.Id.Hex() == res.OrderID {
-				for _, p := range v.Products {
-					product, err := service.ProductRepository.FindById(ctx, p.ProductId)
-					helper.PanicIfError(err)
-					err = service.CustomerRepository.CreateOrder(ctx, customer.Id.Hex(), schema.OrderProduct{
-						Id:        primitive.NewObjectID(),
-						CreatedAt: timeNow,
-						UpdatedAt: timeNow,
-						ProductId: product.Id.Hex(),
-						Price:     p.Price,
-						Quantity:  p.Quantity,
-						Address: &schema.Address{
-							Address:    v.Address.Address,
-							City:       v.Address.City,
-							Province:   v.Address.Province,
-							PostalCode: v.Address.PostalCode,
-						},
-					})
-					helper.PanicIfError(err)
-					err = service.MerchantRepository.PushProductToManageOrders(ctx, product.MerchantId, schema.ManageOrderProduct{
-						Id:        primitive.NewObjectID(),
-						CreatedAt: timeNow,
-						UpdatedAt: timeNow,
-						ProductId: product.Id.Hex(),
-						Price:     p.Price,
-						Quantity:  p.Quantity,
-						Address: &schema.Address{
-							Address:    v.Address.Address,
-							City:       v.Address.City,
-							Province:   v.Address.Province,
-							PostalCode: v.Address.PostalCode,
-						},
-					})
-					helper.PanicIfError(err)
-					err = service.ProductRepository.UpdateQuantity(ctx, schema.Product{
-						Id:    product.Id,
-						Stock: -p.Quantity,
-					})
+	if err != nil {
+		return err
+	}
+
+	var paidOrders int
+	err = service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		paidOrders = 0
+		_, err := service.PaymentNotificationRepository.Create(ctx, schema.PaymentNotification{
+			CreatedAt:         timeNow,
+			OrderId:           res.OrderID,
+			TransactionStatus: res.TransactionStatus,
+			CustomerId:        customer.Id.Hex(),
+		})
+		if mongo.IsDuplicateKeyError(err) {
+			return errNotificationProcessed
+		} else if err != nil {
+			return err
+		}
+
+		if status == "failed" {
+			err = service.TransactionRepository.Delete(ctx, res.OrderID)
+			if err != nil {
+				return err
+			}
+			reservation, err := service.ReservationRepository.FindByTransactionId(ctx, res.OrderID)
+			if err == mongo.ErrNoDocuments {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			return releaseReservation(ctx, service.ReservationRepository, service.ProductRepository, reservation)
+		}
+
+		transaction, err := service.TransactionRepository.FindById(ctx, res.OrderID)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// transactions created before the split do not know the merchant of their products nor their sub-orders
+		transactionOrders := transaction.Orders
+		if len(transactionOrders) == 0 {
+			for i, p := range transaction.Products {
+				product, err := service.ProductRepository.FindById(ctx, p.ProductId)
+				if err != nil {
+					return err
 				}
-				err = service.CustomerRepository.DeleteTransaction(ctx, res.CustomField1, res.OrderID)
-				helper.PanicIfError(err)
+				transaction.Products[i].MerchantId = product.MerchantId
+			}
+			transactionOrders = splitTransactionOrders(transaction.Products)
+		}
 
-			} else {
-				continue
+		// the stock is taken before the orders are written so the oversold ones go straight to a refund
+		oversold, err := service.commitReservation(ctx, transaction)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range transactionOrders {
+			items := orderItems(transaction.Products, v.MerchantId)
+			status, history := schema.OrderStatusPaid, []schema.OrderHistory{
+				{
+					CreatedAt: timeNow,
+					Status:    schema.OrderStatusPaid,
+				},
+			}
+			if oversoldOrder(oversold, items) {
+				status = schema.OrderStatusRefundRequested
+				history = append(history, schema.OrderHistory{
+					CreatedAt: timeNow,
+					Status:    schema.OrderStatusRefundRequested,
+					Note:      "stok habis saat pembayaran diterima",
+				})
+			}
+
+			var shipping *schema.OrderShipping
+			if v.Courier != "" {
+				shipping = &schema.OrderShipping{
+					Courier: v.Courier,
+					Service: v.Service,
+				}
+			}
+			_, err = service.OrderRepository.Create(ctx, schema.Order{
+				CreatedAt:     timeNow,
+				UpdatedAt:     timeNow,
+				TransactionId: transaction.Id.Hex(),
+				CustomerId:    customer.Id.Hex(),
+				MerchantId:    v.MerchantId,
+				Items:         items,
+				Subtotal:      v.Subtotal,
+				ShippingFee:   v.ShippingFee,
+				Discount:      v.Discount,
+				Total:         v.Total,
+				Status:        status,
+				History:       history,
+				Address: &schema.Address{
+					Address:    transaction.Address.Address,
+					City:       transaction.Address.City,
+					Province:   transaction.Address.Province,
+					PostalCode: transaction.Address.PostalCode,
+				},
+				Shipping: shipping,
+			})
+			if err != nil {
+				return err
+			}
+			paidOrders++
+		}
+		if transaction.Voucher != nil {
+			err = service.VoucherRepository.IncrementUsage(ctx, transaction.Voucher.VoucherId)
+			if err != nil {
+				return err
+			}
+			_, err = service.VoucherUsageRepository.Create(ctx, schema.VoucherUsage{
+				CreatedAt:     timeNow,
+				VoucherId:     transaction.Voucher.VoucherId,
+				CustomerId:    customer.Id.Hex(),
+				TransactionId: transaction.Id.Hex(),
+				Discount:      transaction.Voucher.Discount,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, p := range transaction.Products {
+			err = service.ProductRepository.IncrementSold(ctx, p.ProductId, p.Quantity)
+			if err != nil {
+				return err
 			}
 		}
-	case "failed":
-		err = service.CustomerRepository.DeleteTransaction(ctx, res.CustomField1, res.OrderID)
-		helper.PanicIfError(err)
-	default:
-		panic("not found")
+		return service.TransactionRepository.Delete(ctx, res.OrderID)
+	})
+	if errors.Is(err, errNotificationProcessed) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	pkg.OrdersPaid.Add(float64(paidOrders))
+	return nil
+}
+
+// releaseReservation is used outside the callback, where no transaction is running yet
+func (service *TransactionServiceImpl) releaseReservation(ctx context.Context, transactionId string) error {
+	reservation, err := service.ReservationRepository.FindByTransactionId(ctx, transactionId)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		return releaseReservation(ctx, service.ReservationRepository, service.ProductRepository, reservation)
+	})
+}
+
+// commitReservation keeps the reserved stock taken, transactions without an active reservation take the stock now.
+// The items whose stock was sold to someone else in the meantime are returned, their orders need a refund.
+func (service *TransactionServiceImpl) commitReservation(ctx context.Context, transaction schema.Transaction) (map[string]bool, error) {
+	reservation, err := service.ReservationRepository.FindByTransactionId(ctx, transaction.Id.Hex())
+	if err == nil {
+		err = service.ReservationRepository.UpdateStatus(ctx, reservation.Id.Hex(), schema.ReservationStatusActive, schema.ReservationStatusCommitted)
+		if err == nil {
+			return nil, nil
+		}
+	}
+	if err != mongo.ErrNoDocuments && !errors.Is(err, repository.ErrReservationStatusChanged) {
+		return nil, err
+	}
+
+	oversold := map[string]bool{}
+	for _, p := range transaction.Products {
+		err = takeStock(ctx, service.ProductRepository, p.ProductId, p.VariantId, p.Quantity)
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			helper.GetLogger(ctx).Error("paid item is out of stock", "transaction_id", transaction.Id.Hex(), "product_id", p.ProductId, "variant_id", p.VariantId, "quantity", p.Quantity)
+			oversold[oversoldKey(p.ProductId, p.VariantId)] = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return oversold, nil
+}
+
+func oversoldKey(productId string, variantId string) string {
+	return productId + "-" + variantId
+}
+
+// oversoldOrder tells whether one of the items of the order could not be taken from the stock
+func oversoldOrder(oversold map[string]bool, items []schema.OrderItem) bool {
+	for _, v := range items {
+		if oversold[oversoldKey(v.ProductId, v.VariantId)] {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"weplant-backend/exception"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+// voucherLine is one cart line as far as a voucher is concerned
+type voucherLine struct {
+	MerchantId  string
+	CategoryIds []string
+	Amount      int
+}
+
+func voucherCodeError(message string) error {
+	return exception.NewValidationError(message, web.FieldErrorResponse{
+		Field:   "voucher_code",
+		Message: message,
+	})
+}
+
+// usableVoucher finds the voucher of the code and checks it is running and the customer can still use it
+func usableVoucher(ctx context.Context, voucherRepository repository.VoucherRepository, voucherUsageRepository repository.VoucherUsageRepository, code string, customerId string, now int) (schema.Voucher, error) {
+	voucher, err := voucherRepository.FindByCode(ctx, strings.ToUpper(code))
+	if err != nil {
+		return voucher, voucherCodeError(fmt.Sprintf("voucher %s not found", code))
+	}
+	if now < voucher.StartAt || now > voucher.EndAt {
+		return voucher, voucherCodeError(fmt.Sprintf("voucher %s is not running", voucher.Code))
+	}
+	if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+		return voucher, voucherCodeError(fmt.Sprintf("voucher %s is used up", voucher.Code))
+	}
+	if voucher.PerCustomerLimit > 0 {
+		used, err := voucherUsageRepository.CountByCustomerId(ctx, voucher.Id.Hex(), customerId)
+		if err != nil {
+			return voucher, err
+		}
+		if used >= voucher.PerCustomerLimit {
+			return voucher, voucherCodeError(fmt.Sprintf("voucher %s can only be used %d times", voucher.Code, voucher.PerCustomerLimit))
+		}
+	}
+	return voucher, nil
+}
+
+// voucherDiscount returns the part of the lines the voucher applies to and the discount on it,
+// the discount never goes over that part
+func voucherDiscount(voucher schema.Voucher, lines []voucherLine) (int, int, error) {
+	var subtotal int
+	for _, line := range lines {
+		if voucherAppliesTo(voucher, line) {
+			subtotal += line.Amount
+		}
+	}
+	if subtotal == 0 {
+		return 0, 0, voucherCodeError(fmt.Sprintf("voucher %s does not apply to any product in the cart", voucher.Code))
+	}
+	if subtotal < voucher.MinSpend {
+		return subtotal, 0, voucherCodeError(fmt.Sprintf("voucher %s needs a minimum spend of %d", voucher.Code, voucher.MinSpend))
+	}
+
+	discount := voucher.Value
+	if voucher.Type == schema.VoucherTypePercentage {
+		discount = subtotal * voucher.Value / 100
+	}
+	if voucher.MaxDiscount > 0 && discount > voucher.MaxDiscount {
+		discount = voucher.MaxDiscount
+	}
+	if discount > subtotal {
+		discount = subtotal
+	}
+	return subtotal, discount, nil
+}
+
+func voucherAppliesTo(voucher schema.Voucher, line voucherLine) bool {
+	if voucher.MerchantId != "" && voucher.MerchantId != line.MerchantId {
+		return false
+	}
+	if len(voucher.CategoryIds) == 0 {
+		return true
+	}
+	for _, categoryId := range line.CategoryIds {
+		for _, v := range voucher.CategoryIds {
+			if v == categoryId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func productCategoryIds(product schema.Product) []string {
+	var categoryIds []string
+	for _, v := range product.Categories {
+		categoryIds = append(categoryIds, v.CategoryId)
+	}
+	return categoryIds
+}
+
+// applyVoucherDiscount lowers the order of the merchant of a merchant voucher, a platform voucher leaves the orders alone
+func applyVoucherDiscount(orders []schema.TransactionOrder, voucher schema.TransactionVoucher) {
+	if voucher.MerchantId == "" {
+		return
+	}
+	for i, v := range orders {
+		if v.MerchantId == voucher.MerchantId {
+			orders[i].Discount = voucher.Discount
+			orders[i].Total = v.Subtotal + v.ShippingFee - voucher.Discount
+		}
+	}
+}
+
+func transactionVoucherResponse(voucher *schema.TransactionVoucher) *web.TransactionVoucherResponse {
+	if voucher == nil {
+		return nil
+	}
+	return &web.TransactionVoucherResponse{
+		Code:       voucher.Code,
+		MerchantId: voucher.MerchantId,
+		Discount:   voucher.Discount,
+	}
+}
+
+func voucherResponse(voucher schema.Voucher) web.VoucherResponse {
+	return web.VoucherResponse{
+		Id:               voucher.Id.Hex(),
+		CreatedAt:        voucher.CreatedAt,
+		UpdatedAt:        voucher.UpdatedAt,
+		Code:             voucher.Code,
+		MerchantId:       voucher.MerchantId,
+		CategoryIds:      voucher.CategoryIds,
+		Type:             voucher.Type,
+		Value:            voucher.Value,
+		MinSpend:         voucher.MinSpend,
+		MaxDiscount:      voucher.MaxDiscount,
+		StartAt:          voucher.StartAt,
+		EndAt:            voucher.EndAt,
+		UsageLimit:       voucher.UsageLimit,
+		PerCustomerLimit: voucher.PerCustomerLimit,
+		UsedCount:        voucher.UsedCount,
+	}
+}
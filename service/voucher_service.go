@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+// VoucherService manages the vouchers of a merchant, an empty merchant id manages the platform vouchers
+type VoucherService interface {
+	Create(ctx context.Context, request web.VoucherCreateRequest) (web.VoucherResponse, error)
+	FindByMerchantId(ctx context.Context, merchantId string, cursor string, perPage int) (web.VoucherFindAllResponse, error)
+	Update(ctx context.Context, request web.VoucherUpdateRequest) (web.VoucherResponse, error)
+	Delete(ctx context.Context, merchantId string, voucherId string) error
+	ValidateCode(ctx context.Context, request web.VoucherValidateRequest) (web.VoucherValidateResponse, error)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"weplant-backend/exception"
+	"weplant-backend/helper"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+type VoucherServiceImpl struct {
+	VoucherRepository      repository.VoucherRepository
+	VoucherUsageRepository repository.VoucherUsageRepository
+	MerchantRepository     repository.MerchantRepository
+	CategoryRepository     repository.CategoryRepository
+	CustomerRepository     repository.CustomerRepository
+	ProductRepository      repository.ProductRepository
+}
+
+func NewVoucherService(voucherRepository repository.VoucherRepository, voucherUsageRepository repository.VoucherUsageRepository, merchantRepository repository.MerchantRepository, categoryRepository repository.CategoryRepository, customerRepository repository.CustomerRepository, productRepository repository.ProductRepository) VoucherService {
+	return &VoucherServiceImpl{
+		VoucherRepository:      voucherRepository,
+		VoucherUsageRepository: voucherUsageRepository,
+		MerchantRepository:     merchantRepository,
+		CategoryRepository:     categoryRepository,
+		CustomerRepository:     customerRepository,
+		ProductRepository:      productRepository,
+	}
+}
+
+func (service *VoucherServiceImpl) Create(ctx context.Context, request web.VoucherCreateRequest) (web.VoucherResponse, error) {
+	if request.MerchantId != "" {
+		_, err := service.MerchantRepository.FindById(ctx, request.MerchantId)
+		if err != nil {
+			return web.VoucherResponse{}, exception.NewNotFoundError(err.Error())
+		}
+	}
+
+	err := service.checkVoucher(ctx, request.Type, request.Value, request.CategoryIds)
+	if err != nil {
+		return web.VoucherResponse{}, err
+	}
+
+	code := strings.ToUpper(request.Code)
+	voucher, err := service.VoucherRepository.Create(ctx, schema.Voucher{
+		CreatedAt:        request.CreatedAt,
+		UpdatedAt:        request.UpdatedAt,
+		Code:             code,
+		MerchantId:       request.MerchantId,
+		CategoryIds:      request.CategoryIds,
+		Type:             request.Type,
+		Value:            request.Value,
+		MinSpend:         request.MinSpend,
+		MaxDiscount:      request.MaxDiscount,
+		StartAt:          request.StartAt,
+		EndAt:            request.EndAt,
+		UsageLimit:       request.UsageLimit,
+		PerCustomerLimit: request.PerCustomerLimit,
+	})
+	if err != nil {
+		return web.VoucherResponse{}, helper.WrapDuplicateKeyError(err, "voucher "+code+" already exists")
+	}
+
+	return voucherResponse(voucher), nil
+}
+
+func (service *VoucherServiceImpl) FindByMerchantId(ctx context.Context, merchantId string, cursor string, perPage int) (web.VoucherFindAllResponse, error) {
+	vouchers, pageInfo, err := service.VoucherRepository.FindByMerchantId(ctx, merchantId, repository.Page{Cursor: cursor, Limit: perPage})
+	if err != nil {
+		return web.VoucherFindAllResponse{}, pageError(err)
+	}
+	itemCount, err := service.VoucherRepository.CountByMerchantId(ctx, merchantId)
+	if err != nil {
+		return web.VoucherFindAllResponse{}, err
+	}
+
+	var vouchersResponse []web.VoucherResponse
+	for _, v := range vouchers {
+		vouchersResponse = append(vouchersResponse, voucherResponse(v))
+	}
+
+	return web.VoucherFindAllResponse{
+		MerchantId: merchantId,
+		Vouchers:   vouchersResponse,
+		Metadata:   cursorMetadata(perPage, itemCount, pageInfo),
+	}, nil
+}
+
+func (service *VoucherServiceImpl) Update(ctx context.Context, request web.VoucherUpdateRequest) (web.VoucherResponse, error) {
+	voucher, err := service.findVoucher(ctx, request.MerchantId, request.Id)
+	if err != nil {
+		return web.VoucherResponse{}, err
+	}
+
+	err = service.checkVoucher(ctx, request.Type, request.Value, request.CategoryIds)
+	if err != nil {
+		return web.VoucherResponse{}, err
+	}
+
+	voucher.UpdatedAt = request.UpdatedAt
+	voucher.Code = strings.ToUpper(request.Code)
+	voucher.CategoryIds = request.CategoryIds
+	voucher.Type = request.Type
+	voucher.Value = request.Value
+	voucher.MinSpend = request.MinSpend
+	voucher.MaxDiscount = request.MaxDiscount
+	voucher.StartAt = request.StartAt
+	voucher.EndAt = request.EndAt
+	voucher.UsageLimit = request.UsageLimit
+	voucher.PerCustomerLimit = request.PerCustomerLimit
+
+	voucher, err = service.VoucherRepository.Update(ctx, voucher)
+	if err != nil {
+		return web.VoucherResponse{}, helper.WrapDuplicateKeyError(err, "voucher "+voucher.Code+" already exists")
+	}
+
+	return voucherResponse(voucher), nil
+}
+
+func (service *VoucherServiceImpl) Delete(ctx context.Context, merchantId string, voucherId string) error {
+	voucher, err := service.findVoucher(ctx, merchantId, voucherId)
+	if err != nil {
+		return err
+	}
+
+	return service.VoucherRepository.Delete(ctx, voucher.Id.Hex())
+}
+
+// ValidateCode tells the customer the discount the voucher gives on the cart as it is now
+func (service *VoucherServiceImpl) ValidateCode(ctx context.Context, request web.VoucherValidateRequest) (web.VoucherValidateResponse, error) {
+	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
+	if err != nil {
+		return web.VoucherValidateResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	voucher, err := usableVoucher(ctx, service.VoucherRepository, service.VoucherUsageRepository, request.Code, customer.Id.Hex(), helper.GetTimeNow())
+	if err != nil {
+		return web.VoucherValidateResponse{}, err
+	}
+
+	var lines []voucherLine
+	for _, v := range customer.Carts {
+		product, err := service.ProductRepository.FindById(ctx, v.ProductId)
+		if err != nil {
+			return web.VoucherValidateResponse{}, err
+		}
+		price := product.Price
+		if variant, ok := findVariant(product, v.VariantId); ok {
+			price = variant.Price
+		}
+		lines = append(lines, voucherLine{
+			MerchantId:  product.MerchantId,
+			CategoryIds: productCategoryIds(product),
+			Amount:      price * v.Quantity,
+		})
+	}
+
+	subtotal, discount, err := voucherDiscount(voucher, lines)
+	if err != nil {
+		return web.VoucherValidateResponse{}, err
+	}
+
+	return web.VoucherValidateResponse{
+		Code:       voucher.Code,
+		MerchantId: voucher.MerchantId,
+		Subtotal:   subtotal,
+		Discount:   discount,
+	}, nil
+}
+
+func (service *VoucherServiceImpl) findVoucher(ctx context.Context, merchantId string, voucherId string) (schema.Voucher, error) {
+	voucher, err := service.VoucherRepository.FindById(ctx, voucherId)
+	if err != nil || voucher.MerchantId != merchantId {
+		return voucher, exception.NewNotFoundError(fmt.Sprintf("voucher id %s not found", voucherId))
+	}
+	return voucher, nil
+}
+
+// checkVoucher rejects a percentage over 100 and categories that do not exist
+func (service *VoucherServiceImpl) checkVoucher(ctx context.Context, voucherType string, value int, categoryIds []string) error {
+	if voucherType == schema.VoucherTypePercentage && value > 100 {
+		return exception.NewValidationError("a percentage voucher can not take more than 100%", web.FieldErrorResponse{
+			Field:   "value",
+			Message: "must be 100 or less",
+		})
+	}
+	for _, categoryId := range categoryIds {
+		_, err := service.CategoryRepository.FindById(ctx, categoryId)
+		if err != nil {
+			return exception.NewNotFoundError(err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"weplant-backend/model/web"
+)
+
+type WishlistService interface {
+	FindByCustomerId(ctx context.Context, customerId string) (web.WishlistResponse, error)
+	PushProductToWishlist(ctx context.Context, request web.WishlistProductCreateRequest) (web.WishlistProductCreateRequest, error)
+	PullProductFromWishlist(ctx context.Context, customerId string, productId string) error
+	MoveToCart(ctx context.Context, request web.WishlistMoveToCartRequest) error
+}
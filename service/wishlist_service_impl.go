@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"weplant-backend/exception"
+	"weplant-backend/model/schema"
+	"weplant-backend/model/web"
+	"weplant-backend/repository"
+)
+
+type WishlistServiceImpl struct {
+	CustomerRepository repository.CustomerRepository
+	ProductRepository  repository.ProductRepository
+	SessionRepository  repository.SessionRepository
+}
+
+func NewWishlistService(customerRepository repository.CustomerRepository, productRepository repository.ProductRepository, sessionRepository repository.SessionRepository) WishlistService {
+	return &WishlistServiceImpl{
+		CustomerRepository: customerRepository,
+		ProductRepository:  productRepository,
+		SessionRepository:  sessionRepository,
+	}
+}
+
+func (service *WishlistServiceImpl) FindByCustomerId(ctx context.Context, customerId string) (web.WishlistResponse, error) {
+	customer, err := service.CustomerRepository.FindById(ctx, customerId)
+	if err != nil {
+		return web.WishlistResponse{}, exception.NewNotFoundError(err.Error())
+	}
+
+	var productsResponse []web.ProductSimpleResponse
+	for _, v := range customer.Wishlist {
+		product, err := service.ProductRepository.FindById(ctx, v.ProductId)
+		if err != nil {
+			return web.WishlistResponse{}, err
+		}
+		productsResponse = append(productsResponse, web.ProductSimpleResponse{
+			Id:          product.Id.Hex(),
+			MerchantId:  product.MerchantId,
+			Name:        product.Name,
+			Slug:        product.Slug,
+			Description: product.Description,
+			Price:       product.Price,
+			Stock:       product.Stock,
+			MainImage:   imageResponse(product.MainImage),
+		})
+	}
+
+	return web.WishlistResponse{
+		CustomerId: customer.Id.Hex(),
+		Products:   productsResponse,
+	}, nil
+}
+
+func (service *WishlistServiceImpl) PushProductToWishlist(ctx context.Context, request web.WishlistProductCreateRequest) (web.WishlistProductCreateRequest, error) {
+	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
+
+	product, err := service.ProductRepository.FindById(ctx, request.ProductId)
+	if err != nil {
+		return request, exception.NewNotFoundError(err.Error())
+	}
+
+	err = service.CustomerRepository.PushProductToWishlist(ctx, customer.Id.Hex(), schema.WishlistProduct{
+		CreatedAt: request.CreatedAt,
+		ProductId: product.Id.Hex(),
+	})
+	if err != nil {
+		return request, err
+	}
+	return request, nil
+}
+
+func (service *WishlistServiceImpl) PullProductFromWishlist(ctx context.Context, customerId string, productId string) error {
+	customer, err := service.CustomerRepository.FindById(ctx, customerId)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
+
+	if !wishlistHasProduct(customer, productId) {
+		return exception.NewNotFoundError(fmt.Sprintf("product id %s not found in wishlist of customer id %s", productId, customerId))
+	}
+
+	err = service.CustomerRepository.PullProductFromWishlist(ctx, customer.Id.Hex(), productId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// MoveToCart puts one of the product in the cart and takes it off the wishlist
+func (service *WishlistServiceImpl) MoveToCart(ctx context.Context, request web.WishlistMoveToCartRequest) error {
+	customer, err := service.CustomerRepository.FindById(ctx, request.CustomerId)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
+
+	if !wishlistHasProduct(customer, request.ProductId) {
+		return exception.NewNotFoundError(fmt.Sprintf("product id %s not found in wishlist of customer id %s", request.ProductId, request.CustomerId))
+	}
+
+	product, err := service.ProductRepository.FindById(ctx, request.ProductId)
+	if err != nil {
+		return exception.NewNotFoundError(err.Error())
+	}
+
+	_, err = sellableVariant(product, request.VariantId)
+	if err != nil {
+		return err
+	}
+
+	return service.SessionRepository.WithTransaction(ctx, func(ctx context.Context) error {
+		err := service.CustomerRepository.PushProductToCart(ctx, customer.Id.Hex(), schema.CartProduct{
+			ProductId: product.Id.Hex(),
+			VariantId: request.VariantId,
+			Quantity:  1,
+		})
+		if err != nil {
+			return err
+		}
+		return service.CustomerRepository.PullProductFromWishlist(ctx, customer.Id.Hex(), product.Id.Hex())
+	})
+}
+
+func wishlistHasProduct(customer schema.Customer, productId string) bool {
+	for _, v := range customer.Wishlist {
+		if v.ProductId == productId {
+			return true
+		}
+	}
+	return false
+}